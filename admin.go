@@ -0,0 +1,72 @@
+package health
+
+import (
+	"crypto/subtle"
+	"maps"
+	"net/http"
+	"strings"
+)
+
+// DefaultDebugLinks returns the paths net/http/pprof and expvar register
+// themselves on.
+func DefaultDebugLinks() map[string]string {
+	return map[string]string{
+		"pprof":  "/debug/pprof/",
+		"expvar": "/debug/vars",
+	}
+}
+
+// BearerToken returns an admin auth function accepting requests carrying
+// "Authorization: Bearer <token>".
+func BearerToken(token string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		return ok && subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+	}
+}
+
+// WithAdminAuth sets how admin callers are recognized. Admin-only parts of
+// the reports, such as debug links, are only shown to requests for which
+// auth returns true. Without it nobody is an admin.
+func (h *Health) WithAdminAuth(auth func(r *http.Request) bool) *Health {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.adminAuth = auth
+	return h
+}
+
+// WithDebugLinks sets the links to debugging endpoints (pprof, expvar, ...)
+// shown to admin callers in the JSON report and the dashboard, by name. See
+// DefaultDebugLinks for the standard ones.
+func (h *Health) WithDebugLinks(links map[string]string) *Health {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.debugLinks = maps.Clone(links)
+	return h
+}
+
+// isAdmin reports whether r passes the admin auth.
+func (h *Health) isAdmin(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+
+	h.mutex.RLock()
+	auth := h.adminAuth
+	h.mutex.RUnlock()
+
+	return auth != nil && auth(r)
+}
+
+// getDebugLinks returns a copy of the configured debug links.
+func (h *Health) getDebugLinks() map[string]string {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	if len(h.debugLinks) == 0 {
+		return nil
+	}
+	return maps.Clone(h.debugLinks)
+}
@@ -0,0 +1,67 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBearerToken(t *testing.T) {
+	auth := BearerToken("s3cret")
+
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"Bearer s3cret", true},
+		{"Bearer wrong", false},
+		{"s3cret", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/health", nil)
+		if tt.header != "" {
+			r.Header.Set("Authorization", tt.header)
+		}
+		if got := auth(r); got != tt.want {
+			t.Errorf("%q: got %v want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestDebugLinksGated(t *testing.T) {
+	h := &Health{status: Up, useJSON: true}
+	h.WithDebugLinks(DefaultDebugLinks()).WithAdminAuth(BearerToken("s3cret"))
+
+	get := func(token string) responseBody {
+		r := httptest.NewRequest("GET", "/health", nil)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, r)
+
+		var response responseBody
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+		return response
+	}
+
+	if response := get(""); response.Debug != nil {
+		t.Errorf("debug links exposed without auth: %v", response.Debug)
+	}
+	if response := get("wrong"); response.Debug != nil {
+		t.Errorf("debug links exposed with a bad token: %v", response.Debug)
+	}
+	if response := get("s3cret"); response.Debug["pprof"] != "/debug/pprof/" || response.Debug["expvar"] != "/debug/vars" {
+		t.Errorf("debug links missing for admin: %v", response.Debug)
+	}
+
+	// Without admin auth configured nobody sees them
+	h.WithAdminAuth(nil)
+	if response := get("s3cret"); response.Debug != nil {
+		t.Errorf("debug links exposed without admin auth configured: %v", response.Debug)
+	}
+}
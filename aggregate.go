@@ -0,0 +1,172 @@
+package health
+
+// Aggregator decides how check results roll up into the overall status. It
+// returns the overall status and, when that isn't UP, the result to blame
+// for it, whose reason becomes the overall reason (nil if there's none to
+// single out). Aggregators run on every request, so they should be cheap
+// and shouldn't allocate.
+//
+// The manual status set with SetStatus takes precedence: aggregators are
+// only consulted while it is UP.
+type Aggregator interface {
+	Aggregate(results []CheckResult) (Status, *CheckResult)
+}
+
+// AggregatorFunc adapts a function to the Aggregator interface.
+type AggregatorFunc func(results []CheckResult) (Status, *CheckResult)
+
+// Aggregate calls f(results).
+func (f AggregatorFunc) Aggregate(results []CheckResult) (Status, *CheckResult) {
+	return f(results)
+}
+
+// Failed reports whether the result counts as a failure of the service: it
+// is DOWN or TIMED_OUT. Failures of checks only required at startup are
+// reported DEGRADED once the service has been available, so they don't
+// count.
+func (r CheckResult) Failed() bool {
+	return r.Status == Down || r.Status == TimedOut
+}
+
+// WorstOf is the default aggregation: the service is DOWN as soon as any
+// check failed, blaming the first one. Otherwise it is OVERLOADED if any
+// check is, then DEGRADED if any check is.
+func WorstOf() Aggregator {
+	return worstOf{}
+}
+
+type worstOf struct{}
+
+func (worstOf) Aggregate(results []CheckResult) (Status, *CheckResult) {
+	return worst(results, nil)
+}
+
+// worst rolls up the results of the checks counted, all of them when
+// counted is nil, the way WorstOf does.
+func worst(results []CheckResult, counted func(name string) bool) (Status, *CheckResult) {
+	var overloaded, degraded *CheckResult
+	for i := range results {
+		if counted != nil && !counted(results[i].Name) {
+			continue
+		}
+		if results[i].Failed() {
+			return Down, &results[i]
+		}
+		if overloaded == nil && results[i].Status == Overloaded {
+			overloaded = &results[i]
+		}
+		if degraded == nil && results[i].Status == Degraded {
+			degraded = &results[i]
+		}
+	}
+	if overloaded != nil {
+		return Overloaded, overloaded
+	}
+	if degraded != nil {
+		return Degraded, degraded
+	}
+	return Up, nil
+}
+
+// AllCritical makes the service DOWN only when one of the named critical
+// checks failed, and otherwise OVERLOADED or DEGRADED when one of them is.
+// Every other check is reported but ignored.
+func AllCritical(names ...string) Aggregator {
+	critical := make(map[string]bool, len(names))
+	for _, name := range names {
+		critical[name] = true
+	}
+	isCritical := func(name string) bool { return critical[name] }
+
+	return AggregatorFunc(func(results []CheckResult) (Status, *CheckResult) {
+		return worst(results, isCritical)
+	})
+}
+
+// Quorum keeps the service available as long as at least n checks are UP
+// or DEGRADED, for redundant dependencies of which only some are needed.
+// With the quorum met, the service is still OVERLOADED or DEGRADED when a
+// check is, blaming it. When it goes DOWN the first failed check is blamed,
+// or else the first overloaded one, as overloaded checks don't count.
+func Quorum(n int) Aggregator {
+	return AggregatorFunc(func(results []CheckResult) (Status, *CheckResult) {
+		passed := 0
+		var failed, overloaded, degraded *CheckResult
+		for i := range results {
+			switch {
+			case results[i].Status == Up:
+				passed++
+			case results[i].Status == Degraded:
+				passed++
+				if degraded == nil {
+					degraded = &results[i]
+				}
+			case results[i].Status == Overloaded:
+				if overloaded == nil {
+					overloaded = &results[i]
+				}
+			case failed == nil && results[i].Failed():
+				failed = &results[i]
+			}
+		}
+
+		switch {
+		case passed < n && failed != nil:
+			return Down, failed
+		case passed < n:
+			return Down, overloaded
+		case overloaded != nil:
+			return Overloaded, overloaded
+		case degraded != nil:
+			return Degraded, degraded
+		}
+		return Up, nil
+	})
+}
+
+// WeightedScore scores the service as the weighted share of evaluated
+// checks that didn't fail, and keeps it UP while the score is at least
+// threshold (between 0 and 1). Checks missing from weights weigh 1. When it
+// goes DOWN the heaviest failed check is blamed.
+func WeightedScore(weights map[string]float64, threshold float64) Aggregator {
+	weightOf := func(name string) float64 {
+		if w, ok := weights[name]; ok {
+			return w
+		}
+		return 1
+	}
+
+	return AggregatorFunc(func(results []CheckResult) (Status, *CheckResult) {
+		var total, healthy float64
+		var failed *CheckResult
+		for i := range results {
+			if results[i].Status == NotEvaluated {
+				continue
+			}
+
+			w := weightOf(results[i].Name)
+			total += w
+			if !results[i].Failed() {
+				healthy += w
+			} else if failed == nil || w > weightOf(failed.Name) {
+				failed = &results[i]
+			}
+		}
+
+		if total == 0 || healthy/total >= threshold {
+			return Up, nil
+		}
+		return Down, failed
+	})
+}
+
+// WithAggregator sets how check results roll up into the overall status.
+// Nil restores WorstOf.
+func (h *Health) WithAggregator(a Aggregator) *Health {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.aggregator = a
+	h.observeLocked()
+	return h
+}
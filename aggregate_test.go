@@ -0,0 +1,117 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func aggregateResults(statuses map[string]Status, order ...string) []CheckResult {
+	results := make([]CheckResult, len(order))
+	for i, name := range order {
+		results[i] = CheckResult{Name: name, Status: statuses[name]}
+	}
+	return results
+}
+
+func TestAggregators(t *testing.T) {
+	statuses := map[string]Status{"a": Up, "b": Down, "c": Up, "d": NotEvaluated}
+	results := aggregateResults(statuses, "a", "b", "c", "d")
+
+	tests := []struct {
+		name       string
+		aggregator Aggregator
+		status     Status
+		blamed     string
+	}{
+		{"worst of", WorstOf(), Down, "b"},
+		{"critical failing", AllCritical("a", "b"), Down, "b"},
+		{"critical passing", AllCritical("a", "c"), Up, ""},
+		{"quorum met", Quorum(2), Up, ""},
+		{"quorum missed", Quorum(3), Down, "b"},
+		{"score above threshold", WeightedScore(nil, 0.6), Up, ""},
+		{"score below threshold", WeightedScore(map[string]float64{"b": 3}, 0.5), Down, "b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, failed := tt.aggregator.Aggregate(results)
+			if status != tt.status {
+				t.Errorf("status: got %v want %v", status, tt.status)
+			}
+			blamed := ""
+			if failed != nil {
+				blamed = failed.Name
+			}
+			if blamed != tt.blamed {
+				t.Errorf("blamed: got %q want %q", blamed, tt.blamed)
+			}
+		})
+	}
+}
+
+func TestAggregatorsDegraded(t *testing.T) {
+	statuses := map[string]Status{"a": Up, "b": Degraded, "c": Overloaded, "d": Down}
+
+	tests := []struct {
+		name       string
+		aggregator Aggregator
+		order      []string
+		status     Status
+		blamed     string
+	}{
+		{"critical degraded", AllCritical("a", "b"), []string{"a", "b", "c", "d"}, Degraded, "b"},
+		{"critical overloaded", AllCritical("b", "c"), []string{"a", "b", "c", "d"}, Overloaded, "c"},
+		{"critical failing", AllCritical("b", "c", "d"), []string{"a", "b", "c", "d"}, Down, "d"},
+		{"critical up", AllCritical("a"), []string{"a", "b", "c", "d"}, Up, ""},
+		{"quorum with degraded", Quorum(2), []string{"a", "b", "d"}, Degraded, "b"},
+		{"quorum with overloaded", Quorum(2), []string{"a", "b", "c"}, Overloaded, "c"},
+		{"quorum of up", Quorum(1), []string{"a", "d"}, Up, ""},
+		{"quorum missed", Quorum(3), []string{"a", "b", "c", "d"}, Down, "d"},
+		{"quorum missed overloaded", Quorum(3), []string{"a", "b", "c"}, Down, "c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, failed := tt.aggregator.Aggregate(aggregateResults(statuses, tt.order...))
+			if status != tt.status {
+				t.Errorf("status: got %v want %v", status, tt.status)
+			}
+			blamed := ""
+			if failed != nil {
+				blamed = failed.Name
+			}
+			if blamed != tt.blamed {
+				t.Errorf("blamed: got %q want %q", blamed, tt.blamed)
+			}
+		})
+	}
+}
+
+func TestWithAggregator(t *testing.T) {
+	h := &Health{status: Up}
+	h.Register("primary", func(ctx context.Context) error { return errors.New("connection refused") })
+	h.Register("replica", func(ctx context.Context) error { return nil })
+	h.Evaluate(context.Background())
+
+	if status, reason, _ := h.overall(); status != Down || reason != "primary: connection refused" {
+		t.Errorf("default aggregation: got %v %q", status, reason)
+	}
+
+	h.WithAggregator(Quorum(1))
+	if status, _, _ := h.overall(); status != Up {
+		t.Errorf("quorum aggregation: got %v want %v", status, Up)
+	}
+
+	// The manual status still takes precedence
+	h.status = Down
+	if status, _, _ := h.overall(); status != Down {
+		t.Errorf("manual status: got %v want %v", status, Down)
+	}
+
+	h.status = Up
+	h.WithAggregator(nil)
+	if status, _, _ := h.overall(); status != Down {
+		t.Errorf("restored default aggregation: got %v want %v", status, Down)
+	}
+}
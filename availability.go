@@ -0,0 +1,83 @@
+package health
+
+import "time"
+
+// AvailabilityReport is the availability percentage of the instance over
+// the last 5 minutes, hour and day.
+type AvailabilityReport struct {
+	FiveMinutes float64 `json:"5m"`
+	OneHour     float64 `json:"1h"`
+	OneDay      float64 `json:"24h"`
+}
+
+// isAvailable reports whether the instance counts as available in status:
+// a degraded instance still serves.
+func isAvailable(status Status) bool {
+	return status == Up || status == Degraded
+}
+
+// Availability returns the percentage of time check (empty for the overall
+// status) was available during the window ending at now. Only the tracked
+// part of the window counts; with nothing tracked yet it returns 100.
+func (hist *History) Availability(check string, window time.Duration, now time.Time) float64 {
+	hist.mutex.RLock()
+	defer hist.mutex.RUnlock()
+
+	s, ok := hist.series[check]
+	if !ok {
+		return 100
+	}
+
+	from := now.Add(-window)
+	if s.start.After(from) {
+		from = s.start
+	}
+	if !now.After(from) {
+		if isAvailable(s.current) {
+			return 100
+		}
+		return 0
+	}
+
+	// Walk the transitions, accumulating the time spent available
+	var available time.Duration
+	status := s.initial
+	at := from
+	for _, t := range hist.transitions {
+		if t.Check != check {
+			continue
+		}
+		if t.At.After(now) {
+			break
+		}
+		if t.At.After(at) {
+			if isAvailable(status) {
+				available += t.At.Sub(at)
+			}
+			at = t.At
+		}
+		status = t.To
+	}
+	if isAvailable(status) {
+		available += now.Sub(at)
+	}
+
+	return 100 * float64(available) / float64(now.Sub(from))
+}
+
+// Availability returns the percentage of time the instance was available
+// during the rolling window ending now.
+func (h *Health) Availability(window time.Duration) float64 {
+	return h.history.Availability("", window, h.getClock().Now())
+}
+
+// availabilityReport computes the availability over the reported windows.
+func (h *Health) availabilityReport() *AvailabilityReport {
+	now := h.getClock().Now()
+
+	return &AvailabilityReport{
+		FiveMinutes: h.history.Availability("", 5*time.Minute, now),
+		OneHour:     h.history.Availability("", time.Hour, now),
+		OneDay:      h.history.Availability("", 24*time.Hour, now),
+	}
+}
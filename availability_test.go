@@ -0,0 +1,80 @@
+package health
+
+import (
+	"encoding/json"
+	"math"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAvailability(t *testing.T) {
+	h, clock, set := newToggleHandler()
+
+	// Nothing tracked yet
+	if got := h.Availability(time.Hour); got != 100 {
+		t.Errorf("untracked availability: got %v want 100", got)
+	}
+
+	// 40 minutes up, 10 minutes down, 10 minutes up again
+	set(true)
+	clock.advance(40 * time.Minute)
+	set(false)
+	clock.advance(10 * time.Minute)
+	set(true)
+	clock.advance(10 * time.Minute)
+
+	tests := []struct {
+		window time.Duration
+		want   float64
+	}{
+		{5 * time.Minute, 100},
+		{15 * time.Minute, 100 * 10.0 / 15.0},
+		{time.Hour, 100 * 50.0 / 60.0},
+		// Only the tracked hour counts
+		{24 * time.Hour, 100 * 50.0 / 60.0},
+	}
+
+	for _, tt := range tests {
+		if got := h.Availability(tt.window); math.Abs(got-tt.want) > 0.001 {
+			t.Errorf("%v window: got %v want %v", tt.window, got, tt.want)
+		}
+	}
+
+	// Checks are tracked separately
+	if got := h.History().Availability("db", time.Hour, clock.Now()); math.Abs(got-100*50.0/60.0) > 0.001 {
+		t.Errorf("db availability: got %v", got)
+	}
+}
+
+func TestAvailabilityInJSON(t *testing.T) {
+	h, clock, set := newToggleHandler()
+	h.WithJSON(true)
+
+	set(false)
+	clock.advance(time.Minute)
+	set(true)
+	clock.advance(3 * time.Minute)
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest("GET", "/health", nil))
+
+	var response responseBody
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Availability == nil || math.Abs(response.Availability.FiveMinutes-75) > 0.001 {
+		t.Errorf("unexpected availability: %+v", response.Availability)
+	}
+}
+
+func TestDegradedCountsAsAvailable(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var hist History
+	hist.observe("", Up, "", start)
+	hist.observe("", Degraded, "cache: connection refused", start.Add(time.Minute))
+
+	if got := hist.Availability("", time.Hour, start.Add(2*time.Minute)); got != 100 {
+		t.Errorf("expected a degraded instance to stay available, got %v", got)
+	}
+}
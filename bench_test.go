@@ -0,0 +1,143 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+)
+
+// Probe endpoints sit on the request path of every load balancer node, so
+// their cost is part of the package's contract. The budgets below are checked
+// by the tests in this file; raise them only deliberately.
+const (
+	// maxTerseAllocs is the allocation budget of a plain text response
+	maxTerseAllocs = 1
+	// maxJSONAllocs is the allocation budget of a JSON response with checks
+	maxJSONAllocs = 8
+	// maxP99Latency is the latency budget of the handler itself
+	maxP99Latency = time.Millisecond
+)
+
+// discardWriter is a ResponseWriter that doesn't allocate, so measurements
+// only cover the handler.
+type discardWriter struct {
+	header http.Header
+}
+
+func (w *discardWriter) Header() http.Header         { return w.header }
+func (w *discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *discardWriter) WriteHeader(statusCode int)  {}
+
+func newBenchHandler(useJSON bool) *Health {
+	h := &Health{status: Up, useJSON: useJSON}
+	h.Register("db", func(ctx context.Context) error { return nil })
+	h.Register("cache", func(ctx context.Context) error { return errors.New("connection refused") })
+	h.Register("queue", func(ctx context.Context) error { return nil })
+	h.Evaluate(context.Background())
+	return h
+}
+
+func BenchmarkTerseHandler(b *testing.B) {
+	h := newBenchHandler(false)
+	w := &discardWriter{header: http.Header{}}
+	r := httptest.NewRequest("GET", "/health", nil)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		h.ServeHTTP(w, r)
+	}
+}
+
+func BenchmarkJSONHandler(b *testing.B) {
+	h := newBenchHandler(true)
+	w := &discardWriter{header: http.Header{}}
+	r := httptest.NewRequest("GET", "/health", nil)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		h.ServeHTTP(w, r)
+	}
+}
+
+func BenchmarkConcurrentReadWrite(b *testing.B) {
+	h := newBenchHandler(true)
+	r := httptest.NewRequest("GET", "/health", nil)
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		w := &discardWriter{header: http.Header{}}
+		i := 0
+		for pb.Next() {
+			// One write for every nine reads, roughly what a busy service
+			// toggling its status while being probed looks like.
+			if i%10 == 0 {
+				h.mutex.Lock()
+				h.reason = "toggled"
+				h.mutex.Unlock()
+			} else {
+				h.ServeHTTP(w, r)
+			}
+			i++
+		}
+	})
+}
+
+func TestHandlerAllocations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping allocation contract in short mode")
+	}
+	if raceEnabled {
+		t.Skip("skipping allocation contract with the race detector on")
+	}
+
+	tests := []struct {
+		name    string
+		useJSON bool
+		budget  float64
+	}{
+		{"terse", false, maxTerseAllocs},
+		{"json", true, maxJSONAllocs},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newBenchHandler(tt.useJSON)
+			w := &discardWriter{header: http.Header{}}
+			r := httptest.NewRequest("GET", "/health", nil)
+
+			allocs := testing.AllocsPerRun(1000, func() {
+				h.ServeHTTP(w, r)
+			})
+			if allocs > tt.budget {
+				t.Errorf("handler allocates %.0f times per request, budget is %.0f", allocs, tt.budget)
+			}
+		})
+	}
+}
+
+func TestHandlerLatencyP99(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping latency contract in short mode")
+	}
+
+	h := newBenchHandler(true)
+	w := &discardWriter{header: http.Header{}}
+	r := httptest.NewRequest("GET", "/health", nil)
+
+	const requests = 10000
+	latencies := make([]time.Duration, requests)
+	for i := range latencies {
+		start := time.Now()
+		h.ServeHTTP(w, r)
+		latencies[i] = time.Since(start)
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	if p99 := latencies[requests*99/100]; p99 > maxP99Latency {
+		t.Errorf("p99 handler latency is %v, budget is %v", p99, maxP99Latency)
+	}
+}
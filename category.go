@@ -0,0 +1,85 @@
+package health
+
+import (
+	"context"
+	"errors"
+)
+
+// Category classifies check failures, so alert routing can differ by class.
+type Category string
+
+const (
+	// CategoryDependency is a failure of something the service depends on
+	CategoryDependency Category = "dependency"
+	// CategoryConfiguration is a failure caused by bad configuration
+	CategoryConfiguration Category = "configuration"
+	// CategoryResourceExhaustion is a failure caused by running out of a
+	// resource (memory, disk, connections, ...)
+	CategoryResourceExhaustion Category = "resource-exhaustion"
+	// CategoryInternal is a failure of the service itself
+	CategoryInternal Category = "internal"
+)
+
+type categorizedError struct {
+	category Category
+	err      error
+}
+
+func (e *categorizedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *categorizedError) Unwrap() error {
+	return e.err
+}
+
+// CategorizedError tags err with category. It returns nil for a nil err, so
+// it can wrap a check's return value directly.
+func CategorizedError(category Category, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &categorizedError{category: category, err: err}
+}
+
+// DependencyError tags err as a dependency failure
+func DependencyError(err error) error {
+	return CategorizedError(CategoryDependency, err)
+}
+
+// ConfigurationError tags err as a configuration failure
+func ConfigurationError(err error) error {
+	return CategorizedError(CategoryConfiguration, err)
+}
+
+// ResourceExhaustionError tags err as a resource exhaustion failure
+func ResourceExhaustionError(err error) error {
+	return CategorizedError(CategoryResourceExhaustion, err)
+}
+
+// InternalError tags err as an internal failure
+func InternalError(err error) error {
+	return CategorizedError(CategoryInternal, err)
+}
+
+// CategoryOf returns the category err was tagged with, looking through
+// wrapped errors, or an empty category if there is none.
+func CategoryOf(err error) Category {
+	var categorized *categorizedError
+	if errors.As(err, &categorized) {
+		return categorized.category
+	}
+	return ""
+}
+
+// Categorize wraps check so its failures default to category. Errors the
+// check already tagged keep their own category.
+func Categorize(category Category, check CheckFunc) CheckFunc {
+	return func(ctx context.Context) error {
+		err := check(ctx)
+		if err == nil || CategoryOf(err) != "" {
+			return err
+		}
+		return CategorizedError(category, err)
+	}
+}
@@ -0,0 +1,71 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCategoryOf(t *testing.T) {
+	base := errors.New("boom")
+
+	tests := []struct {
+		name string
+		err  error
+		want Category
+	}{
+		{"plain", base, ""},
+		{"nil", nil, ""},
+		{"dependency", DependencyError(base), CategoryDependency},
+		{"configuration", ConfigurationError(base), CategoryConfiguration},
+		{"resource exhaustion", ResourceExhaustionError(base), CategoryResourceExhaustion},
+		{"internal", InternalError(base), CategoryInternal},
+		{"wrapped", fmt.Errorf("checking db: %w", DependencyError(base)), CategoryDependency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CategoryOf(tt.err); got != tt.want {
+				t.Errorf("got %q want %q", got, tt.want)
+			}
+		})
+	}
+
+	if !errors.Is(DependencyError(base), base) {
+		t.Error("categorized errors should unwrap to the original error")
+	}
+	if DependencyError(nil) != nil {
+		t.Error("categorizing a nil error should return nil")
+	}
+}
+
+func TestCategoryInJSON(t *testing.T) {
+	h := &Health{status: Up, useJSON: true}
+	h.Register("db", func(ctx context.Context) error { return DependencyError(errors.New("refused")) })
+	h.Register("config", Categorize(CategoryConfiguration, func(ctx context.Context) error {
+		return errors.New("missing DSN")
+	}))
+	h.Register("disk", Categorize(CategoryConfiguration, func(ctx context.Context) error {
+		return ResourceExhaustionError(errors.New("disk full"))
+	}))
+	h.Evaluate(context.Background())
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest("GET", "/health", nil))
+
+	var response responseBody
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	// Checks come out sorted by name: config, db, disk
+	expected := []Category{CategoryConfiguration, CategoryDependency, CategoryResourceExhaustion}
+	for i, want := range expected {
+		if got := response.Checks[i].Category; got != want {
+			t.Errorf("%s: got %q want %q", response.Checks[i].Name, got, want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// changesResponse is the body served by ChangesHandler.
+type changesResponse struct {
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+	// Cursor is the since parameter of the next poll
+	Cursor string `json:"cursor"`
+	// Full is set when every check is included, because the request had
+	// no since parameter or the history no longer goes back that far
+	Full   bool          `json:"full,omitempty"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// ChangesHandler serves the default handler's checks that changed status.
+// See (*Health).ChangesHandler.
+func ChangesHandler() http.Handler {
+	return handler.ChangesHandler()
+}
+
+// ChangesHandler serves the overall status and the results of the checks
+// whose status changed since the ?since parameter, either an RFC 3339 time
+// or the cursor returned by the previous request, so dashboards polling
+// hundreds of instances don't transfer full reports every time:
+//
+//	http.Handle("/health/changes", health.ChangesHandler())
+//
+// Without since, or when the history no longer goes back that far, every
+// check is included and the response is marked full.
+func (h *Health) ChangesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			changed  map[string]bool
+			cursor   uint64
+			complete bool
+		)
+		switch since := r.URL.Query().Get("since"); {
+		case since == "":
+			_, cursor, _ = h.history.changedSince(0)
+		default:
+			if n, err := strconv.ParseUint(since, 10, 64); err == nil {
+				changed, cursor, complete = h.history.changedSince(n)
+				break
+			}
+			t, err := time.Parse(time.RFC3339, since)
+			if err != nil {
+				http.Error(w, "since must be an RFC 3339 time or a cursor", http.StatusBadRequest)
+				return
+			}
+			changed, cursor, complete = h.history.changedAfter(t)
+		}
+
+		status, reason, results := h.overall()
+		resp := changesResponse{
+			Status: string(status),
+			Reason: reason,
+			Cursor: strconv.FormatUint(cursor, 10),
+			Full:   !complete,
+			Checks: []CheckResult{},
+		}
+		for _, result := range results {
+			if !complete || changed[result.Name] {
+				resp.Checks = append(resp.Checks, result)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
@@ -0,0 +1,64 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestChangesHandler(t *testing.T) {
+	clock := &stepClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	h := New(WithClock(clock))
+
+	var dbErr error
+	h.Register("db", func(ctx context.Context) error { return dbErr })
+	h.Register("cache", func(ctx context.Context) error { return nil })
+	h.Evaluate(context.Background())
+
+	poll := func(since string) changesResponse {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		h.ChangesHandler().ServeHTTP(rr, httptest.NewRequest("GET", "/health/changes?since="+url.QueryEscape(since), nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("unexpected status code %d: %s", rr.Code, rr.Body)
+		}
+		var resp changesResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	first := poll("")
+	if !first.Full || len(first.Checks) != 2 {
+		t.Fatalf("expected every check at first, got %+v", first)
+	}
+
+	clock.advance(time.Minute)
+	dbErr = errors.New("connection refused")
+	h.Evaluate(context.Background())
+
+	changes := poll(first.Cursor)
+	if changes.Full || len(changes.Checks) != 1 || changes.Checks[0].Name != "db" || changes.Status != string(Down) {
+		t.Errorf("expected only db's change, got %+v", changes)
+	}
+	if again := poll(changes.Cursor); again.Full || len(again.Checks) != 0 {
+		t.Errorf("expected no changes since the last poll, got %+v", again)
+	}
+
+	since := time.Date(2024, 1, 1, 0, 0, 30, 0, time.UTC).Format(time.RFC3339)
+	if changes := poll(since); len(changes.Checks) != 1 || changes.Checks[0].Name != "db" {
+		t.Errorf("expected db's change since %s, got %+v", since, changes)
+	}
+
+	rr := httptest.NewRecorder()
+	h.ChangesHandler().ServeHTTP(rr, httptest.NewRequest("GET", "/health/changes?since=yesterday", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid since, got %d", rr.Code)
+	}
+}
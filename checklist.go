@@ -0,0 +1,77 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// CheckInfo describes a registered check and how it is run, regardless of
+// its results.
+type CheckInfo struct {
+	Name  string `json:"name"`
+	Group string `json:"group,omitempty"`
+	// Interval is the handler's evaluation interval, zero when checks are
+	// only run on demand
+	Interval time.Duration `json:"interval,omitempty"`
+	Timeout  time.Duration `json:"timeout,omitempty"`
+	// RequiredAtStartup is set for checks that only degrade the service
+	// once it has been available
+	RequiredAtStartup bool     `json:"required_at_startup,omitempty"`
+	Owner             string   `json:"owner,omitempty"`
+	Runbook           string   `json:"runbook,omitempty"`
+	Description       string   `json:"description,omitempty"`
+	Tags              []string `json:"tags,omitempty"`
+	// Informational is set for non-critical checks, see Informational
+	Informational bool `json:"informational,omitempty"`
+}
+
+// RegisteredChecks describes the checks registered on the default handler.
+func RegisteredChecks() []CheckInfo {
+	return handler.RegisteredChecks()
+}
+
+// RegisteredChecks describes the registered checks, ordered by group then
+// name.
+func (h *Health) RegisteredChecks() []CheckInfo {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	infos := make([]CheckInfo, 0, len(h.checks))
+	for _, c := range h.checks {
+		infos = append(infos, CheckInfo{
+			Name:              c.name,
+			Group:             c.group,
+			Interval:          h.interval,
+			Timeout:           c.timeout,
+			RequiredAtStartup: c.requiredAtStartup,
+			Owner:             c.owner,
+			Runbook:           c.runbook,
+			Description:       c.description,
+			Tags:              slices.Clone(c.tags),
+			Informational:     c.informational,
+		})
+	}
+	return infos
+}
+
+// ChecksHandler lists the checks registered on the default handler. See
+// (*Health).ChecksHandler.
+func ChecksHandler() http.Handler {
+	return handler.ChecksHandler()
+}
+
+// ChecksHandler serves the registered checks and their configuration as
+// JSON, whatever their results, so operators can audit what a service
+// actually monitors:
+//
+//	http.Handle("/health/checks", health.ChecksHandler())
+func (h *Health) ChecksHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(struct {
+			Checks []CheckInfo `json:"checks"`
+		}{h.RegisteredChecks()})
+	})
+}
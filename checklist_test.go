@@ -0,0 +1,41 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestChecksHandler(t *testing.T) {
+	h := New()
+	h.Register("db", func(ctx context.Context) error { return errors.New("never run") },
+		WithTimeout(2*time.Second),
+		WithOwner("payments-team"),
+		WithTags("postgres", "critical"))
+	h.Register("catalog", func(ctx context.Context) error { return nil },
+		RequiredAtStartup(), WithGroup("upstreams"))
+
+	rr := httptest.NewRecorder()
+	h.ChecksHandler().ServeHTTP(rr, httptest.NewRequest("GET", "/health/checks", nil))
+
+	var body struct {
+		Checks []CheckInfo `json:"checks"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Checks) != 2 {
+		t.Fatalf("expected the 2 checks without any evaluation, got %+v", body.Checks)
+	}
+
+	db, catalog := body.Checks[0], body.Checks[1]
+	if db.Name != "db" || db.Timeout != 2*time.Second || db.Owner != "payments-team" || len(db.Tags) != 2 {
+		t.Errorf("unexpected db check %+v", db)
+	}
+	if catalog.Group != "upstreams" || !catalog.RequiredAtStartup {
+		t.Errorf("unexpected catalog check %+v", catalog)
+	}
+}
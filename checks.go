@@ -0,0 +1,518 @@
+package health
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CheckFunc checks a single dependency. It returns a non-nil error when the
+// dependency is unhealthy and should give up as soon as ctx is done.
+type CheckFunc func(ctx context.Context) error
+
+// CheckResult is the outcome of running a single registered check.
+type CheckResult struct {
+	Name     string   `json:"name"`
+	Status   Status   `json:"status"`
+	Reason   string   `json:"reason,omitempty"`
+	Category Category `json:"category,omitempty"`
+	Group    string   `json:"group,omitempty"`
+	// RunID identifies the evaluation cycle that produced the result
+	RunID     string        `json:"run_id,omitempty"`
+	Duration  time.Duration `json:"duration"`
+	CheckedAt time.Time     `json:"checked_at"`
+	// Latency is what a ResultFunc measured of its dependency
+	Latency time.Duration `json:"latency,omitempty"`
+	// Details are set by the check with SetDetail, or returned in its Result
+	Details map[string]any `json:"details,omitempty"`
+
+	// Owner, Runbook and Description tell whoever sees the check fail who
+	// to page and what to read; see WithOwner, WithRunbook and
+	// WithDescription
+	Owner       string `json:"owner,omitempty"`
+	Runbook     string `json:"runbook,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Informational is set for checks registered with Informational
+	Informational bool `json:"informational,omitempty"`
+}
+
+var (
+	errEvaluationTimeout = errors.New("health: evaluation timed out")
+	errCheckTimeout      = errors.New("health: check timed out")
+)
+
+type namedCheck struct {
+	name  string
+	check ResultFunc
+
+	group             string
+	requiredAtStartup bool
+	informational     bool
+	timeout           time.Duration
+
+	owner, runbook, description string
+	tags                        []string
+}
+
+// CheckOption configures how a registered check is run and how its result
+// counts towards the overall status.
+type CheckOption func(*namedCheck)
+
+// RequiredAtStartup makes the check's failures block readiness only until
+// the service has been UP or DEGRADED once. Afterwards they only make the
+// check, and so the service, DEGRADED with the check's reason, for
+// dependencies the service can do without once warmed up (serving from a
+// cache, say).
+func RequiredAtStartup() CheckOption {
+	return func(c *namedCheck) {
+		c.requiredAtStartup = true
+	}
+}
+
+// Informational makes the check non-critical: its failures make it, and
+// the service, DEGRADED rather than DOWN, and it is listed in the JSON
+// report's warnings. A pod is then not drained just because an optional
+// feature's dependency is flaky. Checks are critical by default.
+func Informational() CheckOption {
+	return func(c *namedCheck) {
+		c.informational = true
+	}
+}
+
+// WithTimeout gives up on the check after timeout, so a hanging dependency
+// can't stall the whole evaluation or the request waiting on it. A check
+// that times out is reported as TIMED_OUT with a "timeout" reason.
+func WithTimeout(timeout time.Duration) CheckOption {
+	return func(c *namedCheck) {
+		c.timeout = timeout
+	}
+}
+
+// WithOwner names the team or person owning the check, shown with its
+// result in the JSON report and on the dashboard.
+func WithOwner(owner string) CheckOption {
+	return func(c *namedCheck) {
+		c.owner = owner
+	}
+}
+
+// WithRunbook links the runbook to follow when the check fails, shown with
+// its result in the JSON report and on the dashboard.
+func WithRunbook(url string) CheckOption {
+	return func(c *namedCheck) {
+		c.runbook = url
+	}
+}
+
+// WithDescription describes what the check verifies, shown with its result
+// in the JSON report and on the dashboard.
+func WithDescription(description string) CheckOption {
+	return func(c *namedCheck) {
+		c.description = description
+	}
+}
+
+// WithTags tags the check, for listing and auditing what a service monitors
+// with ChecksHandler.
+func WithTags(tags ...string) CheckOption {
+	return func(c *namedCheck) {
+		c.tags = append(c.tags, tags...)
+	}
+}
+
+// WithGroup puts the check in a group. Checks are ordered by group then name,
+// and the JSON report and the dashboard render each group as a section.
+func WithGroup(group string) CheckOption {
+	return func(c *namedCheck) {
+		c.group = group
+	}
+}
+
+// Register adds a named check to the default handler.
+func Register(name string, check CheckFunc, opts ...CheckOption) {
+	handler.Register(name, check, opts...)
+}
+
+// RegisterResult adds a named check reporting a Result to the default
+// handler.
+func RegisterResult(name string, check ResultFunc, opts ...CheckOption) {
+	handler.RegisterResult(name, check, opts...)
+}
+
+// RegisterWithTimeout adds a named check with a timeout to the default
+// handler. See WithTimeout.
+func RegisterWithTimeout(name string, check CheckFunc, timeout time.Duration, opts ...CheckOption) {
+	handler.RegisterWithTimeout(name, check, timeout, opts...)
+}
+
+// Unregister removes a named check from the default handler.
+func Unregister(name string) {
+	handler.Unregister(name)
+}
+
+// Evaluate runs the checks registered on the default handler once.
+func Evaluate(ctx context.Context) []CheckResult {
+	return handler.Evaluate(ctx)
+}
+
+// Register adds a named check. Registering a name twice replaces the
+// previous check and its options. Checks are kept sorted by group then name,
+// so results come out in the same order whatever the registration order.
+func (h *Health) Register(name string, check CheckFunc, opts ...CheckOption) *Health {
+	return h.RegisterResult(name, adaptCheck(check), opts...)
+}
+
+// RegisterResult is Register for a check reporting a Result.
+func (h *Health) RegisterResult(name string, check ResultFunc, opts ...CheckOption) *Health {
+	c := namedCheck{name: name, check: check}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.checks = slices.DeleteFunc(h.checks, func(existing namedCheck) bool {
+		return existing.name == name
+	})
+	i, _ := slices.BinarySearchFunc(h.checks, c, compareChecks)
+	h.checks = slices.Insert(h.checks, i, c)
+
+	return h
+}
+
+// RegisterWithTimeout is Register with the WithTimeout option.
+func (h *Health) RegisterWithTimeout(name string, check CheckFunc, timeout time.Duration, opts ...CheckOption) *Health {
+	return h.Register(name, check, append(opts, WithTimeout(timeout))...)
+}
+
+// Unregister removes a named check along with its last result. Unknown
+// names are ignored.
+func (h *Health) Unregister(name string) *Health {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.checks = slices.DeleteFunc(h.checks, func(c namedCheck) bool {
+		return c.name == name
+	})
+	// Don't modify the results in place, Evaluate returned them to its
+	// caller
+	if i := slices.IndexFunc(h.results, func(r CheckResult) bool { return r.Name == name }); i >= 0 {
+		h.results = slices.Concat(h.results[:i], h.results[i+1:])
+	}
+	delete(h.samples, name)
+	h.observeLocked()
+
+	return h
+}
+
+// compareChecks orders checks by group then name.
+func compareChecks(a, b namedCheck) int {
+	return cmp.Or(strings.Compare(a.group, b.group), strings.Compare(a.name, b.name))
+}
+
+// optional reports whether a failure of the named check only degrades the
+// service, because the check is only required at startup and the service
+// has already been available. It must be called with the mutex held.
+func (h *Health) optional(name string) bool {
+	if !h.started {
+		return false
+	}
+	for i := range h.checks {
+		if h.checks[i].name == name {
+			return h.checks[i].requiredAtStartup
+		}
+	}
+	return false
+}
+
+// Results returns the results of the last evaluation, ordered by group then
+// name.
+func (h *Health) Results() []CheckResult {
+	_, _, results := h.overall()
+	return results
+}
+
+// WithOnDemand makes the handler run the registered checks on every request,
+// bounded by timeout (and by the request's own deadline, if it is shorter).
+// A zero timeout disables on-demand evaluation.
+func (h *Health) WithOnDemand(timeout time.Duration) *Health {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.onDemand = timeout
+	return h
+}
+
+// WithEvaluationTimeout bounds a whole check cycle. When the timeout expires
+// the results gathered so far are published and every check that hasn't
+// finished is reported as TIMED_OUT. A zero timeout disables the limit.
+func (h *Health) WithEvaluationTimeout(timeout time.Duration) *Health {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.evaluationTimeout = timeout
+	return h
+}
+
+// WithMaxConcurrency runs up to n checks at once, so an evaluation of
+// several network checks takes about as long as the slowest of them rather
+// than their sum. Checks run one at a time when n is zero or one.
+func (h *Health) WithMaxConcurrency(n int) *Health {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.maxConcurrency = n
+	return h
+}
+
+// Evaluate runs every registered check once and stores the results.
+//
+// When ctx carries a deadline the remaining time is split evenly across the
+// rounds of checks that have not started yet (one check per round unless
+// WithMaxConcurrency allows more), so each check only gets its share of the
+// budget. A check that cannot finish within its share (or is never started
+// because the deadline already passed) is reported as NOT_EVALUATED instead
+// of pushing the whole evaluation past the deadline.
+func (h *Health) Evaluate(ctx context.Context) []CheckResult {
+	return h.evaluate(ctx, selection{})
+}
+
+// evaluate runs the selected checks once. The results of the other checks
+// are kept from their last evaluation.
+func (h *Health) evaluate(ctx context.Context, sel selection) []CheckResult {
+	h.mutex.RLock()
+	checks := make([]namedCheck, 0, len(h.checks))
+	for _, c := range h.checks {
+		if sel.includes(c.name) {
+			checks = append(checks, c)
+		}
+	}
+	timeout := h.evaluationTimeout
+	concurrency := max(1, h.maxConcurrency)
+	generation := h.generation
+	h.mutex.RUnlock()
+
+	clock := h.getClock()
+
+	// Only the caller's deadline is split across the checks, the evaluation
+	// timeout caps the cycle as a whole. Deadlines belong to the context and
+	// therefore always run on real time, whatever the handler's clock.
+	deadline, hasDeadline := ctx.Deadline()
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, timeout, errEvaluationTimeout)
+		defer cancel()
+	}
+
+	// Every result of the cycle carries its run ID, telling results of the
+	// same cycle apart from ones kept from earlier partial evaluations
+	runID := newRunID()
+	ctx = context.WithValue(ctx, runIDKey{}, runID)
+
+	results := make([]CheckResult, len(checks))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		slots <- struct{}{}
+
+		var budget time.Duration
+		if hasDeadline {
+			rounds := (len(checks) - i + concurrency - 1) / concurrency
+			budget = time.Until(deadline) / time.Duration(rounds)
+			if budget <= 0 {
+				results[i] = notRun(ctx, clock, c)
+				<-slots
+				continue
+			}
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			results[i] = h.runCheck(ctx, clock, c, budget)
+		}()
+	}
+	wg.Wait()
+
+	h.mutex.Lock()
+	if h.generation != generation {
+		// Reset while running: the checks and their history are gone
+		h.mutex.Unlock()
+		return results
+	}
+	for i := range results {
+		results[i].RunID = runID
+		if results[i].Failed() && h.optional(results[i].Name) {
+			results[i].Status = Degraded
+		}
+	}
+	h.runID = runID
+	if sel.active() {
+		h.results = mergeResults(h.checks, h.results, results)
+	} else {
+		h.results = results
+	}
+	for _, result := range results {
+		h.recordLocked(Event{Kind: EventResult, At: result.CheckedAt, Result: &result})
+		h.sampleLocked(result)
+		// Not knowing a check's status isn't a change of status
+		if result.Status != NotEvaluated {
+			if t, ok := h.history.observe(result.Name, result.Status, result.Reason, result.CheckedAt); ok {
+				h.transitionLocked(t)
+			}
+		}
+	}
+	h.observeLocked()
+	if status, _, _ := h.aggregate(); isAvailable(status) {
+		h.started = true
+	}
+	h.mutex.Unlock()
+
+	return results
+}
+
+// evaluateOnDemand runs the selected checks for a single request when
+// on-demand evaluation is enabled.
+func (h *Health) evaluateOnDemand(ctx context.Context, sel selection) {
+	h.mutex.RLock()
+	timeout := h.onDemand
+	h.mutex.RUnlock()
+
+	if timeout <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	h.evaluate(ctx, sel)
+}
+
+// runCheck runs a single check, giving up after budget or the check's own
+// timeout if they are positive. Timestamps and durations are taken from
+// clock. The check's goroutine counts as running until it returns, even if
+// it's given up on.
+func (h *Health) runCheck(ctx context.Context, clock Clock, c namedCheck, budget time.Duration) CheckResult {
+	if ctx.Err() != nil {
+		return notRun(ctx, clock, c)
+	}
+
+	checkCtx := ctx
+	if budget > 0 {
+		var cancel context.CancelFunc
+		checkCtx, cancel = context.WithTimeout(ctx, budget)
+		defer cancel()
+	}
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		checkCtx, cancel = context.WithTimeoutCause(checkCtx, c.timeout, errCheckTimeout)
+		defer cancel()
+	}
+
+	start := clock.Now()
+	result := c.result(start)
+
+	runCtx, details := withDetails(checkCtx)
+
+	done := make(chan Result, 1)
+	h.running.add()
+	go func() {
+		defer h.running.done()
+		done <- c.check(runCtx)
+	}()
+
+	// Don't wait on a check that ignores its context; it keeps running in the
+	// background but its result is discarded.
+	var reported Result
+	select {
+	case reported = <-done:
+		result.Duration = clock.Now().Sub(start)
+		switch err := reported.Err; {
+		case err == nil:
+			result.Status = Up
+		case checkCtx.Err() != nil && errors.Is(err, checkCtx.Err()):
+			result.Status, result.Reason = cutOff(checkCtx)
+		case isOverload(err):
+			result.Status = Overloaded
+			result.Reason = err.Error()
+		case isDegraded(err):
+			result.Status = Degraded
+			result.Reason = err.Error()
+		default:
+			result.Status = Down
+			result.Reason = err.Error()
+			result.Category = CategoryOf(err)
+		}
+		if reported.Status != "" {
+			result.Status = reported.Status
+		}
+		if reported.Message != "" {
+			result.Reason = reported.Message
+		}
+		result.Latency = reported.Latency
+	case <-checkCtx.Done():
+		result.Duration = clock.Now().Sub(start)
+		result.Status, result.Reason = cutOff(checkCtx)
+	}
+	if c.informational && (result.Status == Down || result.Status == TimedOut) {
+		result.Status = Degraded
+	}
+	result.Details = details.snapshot()
+	for key, value := range reported.Details {
+		if result.Details == nil {
+			result.Details = make(map[string]any, len(reported.Details))
+		}
+		result.Details[key] = value
+	}
+
+	return result
+}
+
+// result starts the result of c, checked at the given time.
+func (c namedCheck) result(at time.Time) CheckResult {
+	return CheckResult{
+		Name:          c.name,
+		Group:         c.group,
+		CheckedAt:     at,
+		Owner:         c.owner,
+		Runbook:       c.runbook,
+		Description:   c.description,
+		Informational: c.informational,
+	}
+}
+
+// notRun is the result for a check that was skipped because the deadline or
+// the evaluation timeout had already passed.
+func notRun(ctx context.Context, clock Clock, c namedCheck) CheckResult {
+	result := c.result(clock.Now())
+
+	if context.Cause(ctx) == errEvaluationTimeout {
+		result.Status = TimedOut
+		result.Reason = "evaluation timed out before the check could start"
+	} else {
+		result.Status = NotEvaluated
+		result.Reason = "deadline reached before the check could start"
+	}
+
+	return result
+}
+
+// cutOff describes a check that was started but didn't finish in time,
+// given the context it ran with.
+func cutOff(ctx context.Context) (Status, string) {
+	switch context.Cause(ctx) {
+	case errEvaluationTimeout:
+		return TimedOut, "evaluation timed out before the check finished"
+	case errCheckTimeout:
+		return TimedOut, "timeout"
+	}
+
+	return NotEvaluated, "check did not finish within its share of the deadline"
+}
@@ -0,0 +1,80 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/andres-vara/health"
+)
+
+// awsAPIError is satisfied by the AWS SDK's API errors (smithy.APIError).
+type awsAPIError interface {
+	ErrorCode() string
+}
+
+// awsThrottlingCodes are the error codes AWS services use for throttling.
+var awsThrottlingCodes = map[string]bool{
+	"Throttling":                             true,
+	"ThrottlingException":                    true,
+	"ThrottledException":                     true,
+	"RequestThrottled":                       true,
+	"RequestThrottledException":              true,
+	"RequestLimitExceeded":                   true,
+	"TooManyRequestsException":               true,
+	"ProvisionedThroughputExceededException": true,
+	"LimitExceededException":                 true,
+	"SlowDown":                               true,
+}
+
+// awsAuthCodes are the error codes AWS services use for bad or insufficient
+// credentials.
+var awsAuthCodes = map[string]bool{
+	"AccessDenied":                true,
+	"AccessDeniedException":       true,
+	"UnrecognizedClientException": true,
+	"InvalidClientTokenId":        true,
+	"InvalidSignatureException":   true,
+	"SignatureDoesNotMatch":       true,
+	"ExpiredToken":                true,
+	"ExpiredTokenException":       true,
+}
+
+// awsNotFoundCodes are the error codes AWS services use for a missing
+// resource.
+var awsNotFoundCodes = map[string]bool{
+	"ResourceNotFoundException":               true,
+	"QueueDoesNotExist":                       true,
+	"AWS.SimpleQueueService.NonExistentQueue": true,
+	"NoSuchBucket":                            true,
+}
+
+// awsError classifies err from an AWS call on resource. The class
+// ("throttled", "auth", "not_found" or "unavailable") and the AWS error
+// code are reported as the "error_class" and "error_code" details, and the
+// error is categorized to match: throttling is resource exhaustion, bad
+// credentials and missing resources are configuration errors.
+func awsError(ctx context.Context, resource string, err error) error {
+	var code string
+	var apiErr awsAPIError
+	if errors.As(err, &apiErr) {
+		code = apiErr.ErrorCode()
+		health.SetDetail(ctx, "error_code", code)
+	}
+
+	err = fmt.Errorf("%s: %w", resource, err)
+	switch {
+	case awsThrottlingCodes[code]:
+		health.SetDetail(ctx, "error_class", "throttled")
+		return health.ResourceExhaustionError(err)
+	case awsAuthCodes[code]:
+		health.SetDetail(ctx, "error_class", "auth")
+		return health.ConfigurationError(err)
+	case awsNotFoundCodes[code]:
+		health.SetDetail(ctx, "error_class", "not_found")
+		return health.ConfigurationError(err)
+	default:
+		health.SetDetail(ctx, "error_class", "unavailable")
+		return health.DependencyError(err)
+	}
+}
@@ -0,0 +1,64 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/andres-vara/health"
+)
+
+// AzureContainer reads the properties of an Azure Blob Storage container. It
+// is implemented by wrapping the Azure SDK's container client, so this
+// package doesn't depend on it. The HTTP status of failed calls tells
+// credential problems apart from outages:
+//
+//	type blobContainer struct{ *container.Client }
+//
+//	func (c blobContainer) GetProperties(ctx context.Context) (int, error) {
+//		_, err := c.Client.GetProperties(ctx, nil)
+//		var respErr *azcore.ResponseError
+//		if errors.As(err, &respErr) {
+//			return respErr.StatusCode, err
+//		}
+//		return 0, err
+//	}
+type AzureContainer interface {
+	// GetProperties returns the HTTP status of a failed call, zero when
+	// there was no response
+	GetProperties(ctx context.Context) (status int, err error)
+}
+
+// AzureBlob returns a check reading the properties of container, a cheap
+// call proving both that the storage account answers and that the
+// credentials are good. Failures are classified in the "error_class"
+// detail: "auth" for 401 and 403, which rotating or fixing credentials
+// solves, "not_found" for a missing container, "throttled" for 429 and
+// "unavailable" for server errors and calls without a response such as
+// timeouts.
+func AzureBlob(container AzureContainer) health.CheckFunc {
+	return func(ctx context.Context) error {
+		status, err := container.GetProperties(ctx)
+		if err == nil {
+			return nil
+		}
+		if status != 0 {
+			health.SetDetail(ctx, "http_status", status)
+		}
+
+		switch {
+		case status == http.StatusUnauthorized || status == http.StatusForbidden:
+			health.SetDetail(ctx, "error_class", "auth")
+			return health.ConfigurationError(fmt.Errorf("not authorized: %w", err))
+		case status == http.StatusNotFound:
+			health.SetDetail(ctx, "error_class", "not_found")
+			return health.ConfigurationError(err)
+		case status == http.StatusTooManyRequests:
+			health.SetDetail(ctx, "error_class", "throttled")
+			return health.ResourceExhaustionError(err)
+		default:
+			health.SetDetail(ctx, "error_class", "unavailable")
+			return health.DependencyError(err)
+		}
+	}
+}
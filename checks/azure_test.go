@@ -0,0 +1,50 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/andres-vara/health"
+)
+
+type fakeContainer struct {
+	status int
+	err    error
+}
+
+func (f fakeContainer) GetProperties(ctx context.Context) (int, error) {
+	return f.status, f.err
+}
+
+func TestAzureBlob(t *testing.T) {
+	failure := errors.New("request failed")
+	tests := []struct {
+		container fakeContainer
+		category  health.Category
+		class     string
+	}{
+		{container: fakeContainer{}},
+		{container: fakeContainer{401, failure}, category: health.CategoryConfiguration, class: "auth"},
+		{container: fakeContainer{403, failure}, category: health.CategoryConfiguration, class: "auth"},
+		{container: fakeContainer{404, failure}, category: health.CategoryConfiguration, class: "not_found"},
+		{container: fakeContainer{429, failure}, category: health.CategoryResourceExhaustion, class: "throttled"},
+		{container: fakeContainer{503, failure}, category: health.CategoryDependency, class: "unavailable"},
+		{container: fakeContainer{0, context.DeadlineExceeded}, category: health.CategoryDependency, class: "unavailable"},
+	}
+
+	for _, tt := range tests {
+		h := health.Handle()
+		h.Register("blob", AzureBlob(tt.container))
+		h.Evaluate(context.Background())
+		result := h.Results()[0]
+		health.Reset()
+
+		if result.Category != tt.category {
+			t.Errorf("status %d: expected category %q, got %q", tt.container.status, tt.category, result.Category)
+		}
+		if class, _ := result.Details["error_class"].(string); class != tt.class {
+			t.Errorf("status %d: expected class %q, got %q", tt.container.status, tt.class, class)
+		}
+	}
+}
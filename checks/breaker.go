@@ -0,0 +1,41 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/andres-vara/health"
+)
+
+// errBreakerOpen is reported while a circuit breaker is open
+var errBreakerOpen = errors.New("circuit breaker open")
+
+// Breaker is a circuit breaker that reports whether it is open, like
+// hystrix-go's CircuitBreaker.
+type Breaker interface {
+	IsOpen() bool
+}
+
+// BreakerState adapts a function returning a breaker's state name, such as
+// "closed", "half-open" or "open", to Breaker. It fits gobreaker:
+//
+//	checks.BreakerState(func() string { return cb.State().String() })
+type BreakerState func() string
+
+// IsOpen implements Breaker.
+func (f BreakerState) IsOpen() bool {
+	return strings.EqualFold(f(), "open")
+}
+
+// CircuitBreaker returns a check failing while b is open, so a tripped
+// breaker on a dependency surfaces in health without a check of its own. A
+// half-open breaker passes, as it is already letting requests through.
+func CircuitBreaker(b Breaker) health.CheckFunc {
+	return func(ctx context.Context) error {
+		if b.IsOpen() {
+			return health.DependencyError(errBreakerOpen)
+		}
+		return nil
+	}
+}
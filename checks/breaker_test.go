@@ -0,0 +1,31 @@
+package checks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/andres-vara/health"
+)
+
+type fakeBreaker bool
+
+func (b fakeBreaker) IsOpen() bool {
+	return bool(b)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	if err := CircuitBreaker(fakeBreaker(false))(context.Background()); err != nil {
+		t.Errorf("expected a closed breaker to pass, got %v", err)
+	}
+
+	err := CircuitBreaker(fakeBreaker(true))(context.Background())
+	if err == nil || err.Error() != "circuit breaker open" || health.CategoryOf(err) != health.CategoryDependency {
+		t.Errorf("expected an open breaker to fail as a dependency, got %v", err)
+	}
+
+	for state, open := range map[string]bool{"closed": false, "half-open": false, "open": true, "OPEN": true} {
+		if got := BreakerState(func() string { return state }).IsOpen(); got != open {
+			t.Errorf("state %q: got open=%v want %v", state, got, open)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/andres-vara/health"
+)
+
+// CassandraQuery is the lightweight query run by the Cassandra check.
+const CassandraQuery = "SELECT now() FROM system.local"
+
+// CassandraSession runs statements against Cassandra. It is implemented by
+// wrapping a gocql session, so this package doesn't depend on gocql:
+//
+//	type session struct{ *gocql.Session }
+//
+//	func (s session) Exec(ctx context.Context, stmt string) error {
+//		return s.Query(stmt).WithContext(ctx).Exec()
+//	}
+//
+// A session that also implements CassandraHosts gets per-host details.
+type CassandraSession interface {
+	// Exec runs stmt, discarding any rows
+	Exec(ctx context.Context, stmt string) error
+}
+
+// CassandraHosts reports the hosts a driver knows of, such as from a gocql
+// HostStateNotifier or the ring the application tracks.
+type CassandraHosts interface {
+	// HostStates returns whether every known host is up
+	HostStates() map[string]bool
+}
+
+// Cassandra returns a check running CassandraQuery on session. When session
+// implements CassandraHosts, the state of every host is reported as a detail
+// named after it, and the check is degraded while some are down: the
+// cluster still answers, with less room for losing another node.
+func Cassandra(session CassandraSession) health.CheckFunc {
+	return func(ctx context.Context) error {
+		if err := session.Exec(ctx, CassandraQuery); err != nil {
+			return health.DependencyError(err)
+		}
+
+		hosts, ok := session.(CassandraHosts)
+		if !ok {
+			return nil
+		}
+
+		var down []string
+		for host, up := range hosts.HostStates() {
+			if up {
+				health.SetDetail(ctx, host, "up")
+			} else {
+				health.SetDetail(ctx, host, "down")
+				down = append(down, host)
+			}
+		}
+		if len(down) > 0 {
+			slices.Sort(down)
+			return health.DegradedError(fmt.Errorf("hosts down: %s", strings.Join(down, ", ")))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,60 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/andres-vara/health"
+)
+
+type fakeSession struct {
+	err  error
+	stmt string
+}
+
+func (f *fakeSession) Exec(ctx context.Context, stmt string) error {
+	f.stmt = stmt
+	return f.err
+}
+
+type fakeRing struct {
+	fakeSession
+	hosts map[string]bool
+}
+
+func (f *fakeRing) HostStates() map[string]bool {
+	return f.hosts
+}
+
+func TestCassandra(t *testing.T) {
+	defer health.Reset()
+
+	plain := &fakeSession{}
+	ring := &fakeRing{hosts: map[string]bool{"10.0.0.1": true, "10.0.0.2": false}}
+
+	h := health.Handle()
+	h.Register("plain", Cassandra(plain))
+	h.Register("ring", Cassandra(ring))
+	h.Register("down", Cassandra(&fakeSession{err: errors.New("no hosts available")}))
+	h.Evaluate(context.Background())
+
+	results := map[string]health.CheckResult{}
+	for _, result := range h.Results() {
+		results[result.Name] = result
+	}
+
+	if r := results["plain"]; r.Status != health.Up || len(r.Details) != 0 {
+		t.Errorf("expected a plain session to pass without details, got %+v", r)
+	}
+	if plain.stmt != CassandraQuery {
+		t.Errorf("unexpected statement %q", plain.stmt)
+	}
+	r := results["ring"]
+	if r.Status != health.Degraded || r.Details["10.0.0.2"] != "down" || r.Reason != "hosts down: 10.0.0.2" {
+		t.Errorf("expected the down host to degrade the check, got %+v", r)
+	}
+	if r := results["down"]; r.Status != health.Down || r.Category != health.CategoryDependency {
+		t.Errorf("expected a failing query to be a dependency error, got %+v", r)
+	}
+}
@@ -0,0 +1,139 @@
+package checks
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+var (
+	errNoCertificate = errors.New("no certificate configured")
+	errKeyMismatch   = errors.New("private key doesn't match the certificate")
+)
+
+// CertificateOption configures a certificate check.
+type CertificateOption func(*certificateCheck)
+
+type certificateCheck struct {
+	warn       time.Duration
+	roots      *x509.CertPool
+	serverName string
+}
+
+// WithRoots sets the roots the chain is verified against, for certificates
+// issued by a private CA; the system roots by default.
+func WithRoots(roots *x509.CertPool) CertificateOption {
+	return func(c *certificateCheck) {
+		c.roots = roots
+	}
+}
+
+// WithServerName makes the check verify the certificate is valid for name.
+// With ServingCertificate, name is also the SNI the certificate is asked
+// for.
+func WithServerName(name string) CertificateOption {
+	return func(c *certificateCheck) {
+		c.serverName = name
+	}
+}
+
+// CertificateFiles returns a check on the server's own certificate, read
+// from the PEM files certFile and keyFile on every check so rotations are
+// seen. See ServingCertificate for what is verified.
+func CertificateFiles(certFile, keyFile string, warn time.Duration, opts ...CertificateOption) health.CheckFunc {
+	c := newCertificateCheck(warn, opts)
+
+	return func(ctx context.Context) error {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return health.ConfigurationError(err)
+		}
+		return c.check(ctx, &cert)
+	}
+}
+
+// ServingCertificate returns a check on the certificate config serves, from
+// GetCertificate or else the first of Certificates. It verifies that the
+// private key matches the certificate, that the chain is valid, and that
+// expiry isn't imminent, being degraded when it is less than warn away,
+// catching bad rotations before clients do. The expiry is
+// reported as the "expires_at" detail.
+func ServingCertificate(config *tls.Config, warn time.Duration, opts ...CertificateOption) health.CheckFunc {
+	c := newCertificateCheck(warn, opts)
+
+	return func(ctx context.Context) error {
+		var cert *tls.Certificate
+		switch {
+		case config.GetCertificate != nil:
+			var err error
+			cert, err = config.GetCertificate(&tls.ClientHelloInfo{ServerName: c.serverName})
+			if err != nil {
+				return health.ConfigurationError(fmt.Errorf("getting certificate: %w", err))
+			}
+		case len(config.Certificates) > 0:
+			cert = &config.Certificates[0]
+		}
+		if cert == nil {
+			return health.ConfigurationError(errNoCertificate)
+		}
+		return c.check(ctx, cert)
+	}
+}
+
+func newCertificateCheck(warn time.Duration, opts []CertificateOption) *certificateCheck {
+	c := &certificateCheck{warn: warn}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *certificateCheck) check(ctx context.Context, cert *tls.Certificate) error {
+	if len(cert.Certificate) == 0 {
+		return health.ConfigurationError(errNoCertificate)
+	}
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return health.ConfigurationError(fmt.Errorf("parsing certificate: %w", err))
+	}
+	health.SetDetail(ctx, "expires_at", leaf.NotAfter.UTC().Format(time.RFC3339))
+
+	key, ok := cert.PrivateKey.(crypto.Signer)
+	if !ok {
+		return health.ConfigurationError(errKeyMismatch)
+	}
+	public, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !public.Equal(leaf.PublicKey) {
+		return health.ConfigurationError(errKeyMismatch)
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, der := range cert.Certificate[1:] {
+		intermediate, err := x509.ParseCertificate(der)
+		if err != nil {
+			return health.ConfigurationError(fmt.Errorf("parsing chain: %w", err))
+		}
+		intermediates.AddCert(intermediate)
+	}
+	_, err = leaf.Verify(x509.VerifyOptions{
+		DNSName:       c.serverName,
+		Roots:         c.roots,
+		Intermediates: intermediates,
+		CurrentTime:   now(),
+	})
+	if err != nil {
+		return health.ConfigurationError(fmt.Errorf("verifying certificate: %w", err))
+	}
+
+	if left := leaf.NotAfter.Sub(now()); left < c.warn {
+		return health.DegradedError(fmt.Errorf("certificate expires in %v", left.Round(time.Minute)))
+	}
+	return nil
+}
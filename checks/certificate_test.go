@@ -0,0 +1,109 @@
+package checks
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+// issue creates a certificate for name signed by parent (self-signed when
+// nil), valid for the given duration.
+func issue(t *testing.T, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, valid time.Duration) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(valid),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func TestCertificate(t *testing.T) {
+	defer health.Reset()
+
+	ca, caKey := issue(t, "ca", nil, nil, 365*24*time.Hour)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+
+	leaf, leafKey := issue(t, "api.internal", ca, caKey, 365*24*time.Hour)
+	expiring, expiringKey := issue(t, "api.internal", ca, caKey, 24*time.Hour)
+	_, otherKey := issue(t, "other", ca, caKey, time.Hour)
+	selfSigned, selfSignedKey := issue(t, "api.internal", nil, nil, 365*24*time.Hour)
+
+	pair := func(cert *x509.Certificate, key *ecdsa.PrivateKey) *tls.Config {
+		return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{cert.Raw}, PrivateKey: key}}}
+	}
+
+	dir := t.TempDir()
+	keyDER, err := x509.MarshalECPrivateKey(leafKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Raw}), 0o600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+
+	getCertificate := &tls.Config{GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return &tls.Certificate{Certificate: [][]byte{leaf.Raw}, PrivateKey: leafKey}, nil
+	}}
+
+	opts := []CertificateOption{WithRoots(roots), WithServerName("api.internal")}
+	h := health.Handle()
+	h.Register("good", ServingCertificate(pair(leaf, leafKey), 30*24*time.Hour, opts...))
+	h.Register("files", CertificateFiles(certFile, keyFile, 30*24*time.Hour, opts...))
+	h.Register("get-certificate", ServingCertificate(getCertificate, 30*24*time.Hour, opts...))
+	h.Register("expiring", ServingCertificate(pair(expiring, expiringKey), 30*24*time.Hour, opts...))
+	h.Register("mismatch", ServingCertificate(pair(leaf, otherKey), time.Hour, opts...))
+	h.Register("untrusted", ServingCertificate(pair(selfSigned, selfSignedKey), time.Hour, opts...))
+	h.Register("wrong-name", ServingCertificate(pair(leaf, leafKey), time.Hour, WithRoots(roots), WithServerName("web.internal")))
+	h.Register("empty", ServingCertificate(&tls.Config{}, time.Hour))
+	h.Evaluate(context.Background())
+
+	for _, result := range h.Results() {
+		want := health.Down
+		switch result.Name {
+		case "good", "files", "get-certificate":
+			want = health.Up
+		case "expiring":
+			want = health.Degraded
+		}
+		if result.Status != want {
+			t.Errorf("%s: expected %s, got %s (%s)", result.Name, want, result.Status, result.Reason)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package checks
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/andres-vara/health"
+)
+
+// cgroupRoot is where the cgroup filesystem is mounted, a variable so tests
+// can point it at a fake tree.
+var cgroupRoot = "/sys/fs/cgroup"
+
+// cgroupUnlimited is the smallest limit cgroup v1 reports for "no limit"
+// (the page-aligned maximum int64).
+const cgroupUnlimited = 1 << 62
+
+// CgroupMemory returns a check on the container's memory use against its
+// cgroup limit, read from cgroup v2 (memory.max, memory.current) or v1
+// (memory.limit_in_bytes, memory.usage_in_bytes). Go's runtime statistics
+// don't know about the limit, and the OOM killer gives no warning, so the
+// check reports trouble ahead of it.
+//
+// Usage is the working set, as the kubelet computes it: inactive page cache
+// is left out since the kernel reclaims it before killing anything. Shares
+// are between 0 and 1: the check fails when usage reaches fail of the limit,
+// and is degraded once it reaches warn. Usage and limit
+// are reported as details. Without a cgroup or a limit the check passes.
+func CgroupMemory(warn, fail float64) health.CheckFunc {
+	return func(ctx context.Context) error {
+		usage, limit, err := cgroupMemory(cgroupRoot)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return health.InternalError(err)
+		}
+		if limit == 0 {
+			return nil
+		}
+
+		share := float64(usage) / float64(limit)
+		health.SetDetail(ctx, "memory_usage_bytes", usage)
+		health.SetDetail(ctx, "memory_limit_bytes", limit)
+
+		switch {
+		case share >= fail:
+			return health.ResourceExhaustionError(fmt.Errorf("memory at %.1f%% of the cgroup limit, failing at %.1f%%", share*100, fail*100))
+		case share >= warn:
+			return health.DegradedError(fmt.Errorf("memory at %.1f%% of the cgroup limit", share*100))
+		}
+		return nil
+	}
+}
+
+// cgroupMemory returns the working set and the memory limit of the cgroup
+// mounted at root, trying v2 then v1. The limit is zero when there is none.
+func cgroupMemory(root string) (usage, limit uint64, err error) {
+	if _, err := os.Stat(filepath.Join(root, "memory.max")); err == nil {
+		return readCgroupMemory(root, "memory.max", "memory.current", "inactive_file")
+	}
+	return readCgroupMemory(filepath.Join(root, "memory"), "memory.limit_in_bytes", "memory.usage_in_bytes", "total_inactive_file")
+}
+
+func readCgroupMemory(dir, limitFile, usageFile, inactiveKey string) (usage, limit uint64, err error) {
+	raw, err := os.ReadFile(filepath.Join(dir, limitFile))
+	if err != nil {
+		return 0, 0, err
+	}
+	if value := strings.TrimSpace(string(raw)); value != "max" {
+		limit, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("parse %s: %w", limitFile, err)
+		}
+		if limit >= cgroupUnlimited {
+			limit = 0
+		}
+	}
+
+	raw, err = os.ReadFile(filepath.Join(dir, usageFile))
+	if err != nil {
+		return 0, 0, err
+	}
+	usage, err = strconv.ParseUint(strings.TrimSpace(string(raw)), 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("parse %s: %w", usageFile, err)
+	}
+
+	inactive, err := cgroupStat(filepath.Join(dir, "memory.stat"), inactiveKey)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return 0, 0, err
+	}
+	if inactive < usage {
+		usage -= inactive
+	}
+
+	return usage, limit, nil
+}
+
+// cgroupStat returns the value of key in a memory.stat file, zero if absent.
+func cgroupStat(path, key string) (uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(scanner.Text(), " ")
+		if ok && name == key {
+			return strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+		}
+	}
+	return 0, scanner.Err()
+}
@@ -0,0 +1,71 @@
+package checks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/andres-vara/health"
+)
+
+func writeCgroupFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCgroupMemory(t *testing.T) {
+	defer func(root string) { cgroupRoot = root }(cgroupRoot)
+
+	v2 := t.TempDir()
+	writeCgroupFiles(t, v2, map[string]string{
+		"memory.max":     "1000\n",
+		"memory.current": "900\n",
+		"memory.stat":    "anon 600\ninactive_file 200\n",
+	})
+	v1 := t.TempDir()
+	writeCgroupFiles(t, filepath.Join(v1, "memory"), map[string]string{
+		"memory.limit_in_bytes": "1000\n",
+		"memory.usage_in_bytes": "950\n",
+	})
+	unlimited := t.TempDir()
+	writeCgroupFiles(t, unlimited, map[string]string{
+		"memory.max":     "max\n",
+		"memory.current": "900\n",
+	})
+
+	for _, test := range []struct {
+		name     string
+		root     string
+		category health.Category
+		status   health.Status
+	}{
+		{"v2 working set below", v2, "", health.Degraded},
+		{"v1 above", v1, health.CategoryResourceExhaustion, health.Down},
+		{"unlimited", unlimited, "", health.Up},
+		{"no cgroup", t.TempDir(), "", health.Up},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			cgroupRoot = test.root
+			r := health.New().Register("memory", CgroupMemory(0.5, 0.9)).Evaluate(context.Background())[0]
+			if r.Status != test.status {
+				t.Fatalf("expected %v, got %v (%s)", test.status, r.Status, r.Reason)
+			}
+			if r.Category != test.category {
+				t.Errorf("unexpected category %q", r.Category)
+			}
+		})
+	}
+
+	usage, limit, err := cgroupMemory(v2)
+	if err != nil || usage != 700 || limit != 1000 {
+		t.Errorf("expected 700 of 1000, got %d of %d (%v)", usage, limit, err)
+	}
+}
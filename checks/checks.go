@@ -0,0 +1,22 @@
+// Package checks provides ready-made health checks to register on a
+// health handler:
+//
+//	health.Register("payments-api", checks.HTTP("https://payments.internal/health",
+//		checks.ExpectJSONField("status", "UP"),
+//		checks.MaxLatency(500*time.Millisecond),
+//	))
+package checks
+
+import "time"
+
+// DefaultTimeout is the timeout of the checks taking one when it is zero or
+// negative.
+const DefaultTimeout = 5 * time.Second
+
+// timeoutOr returns timeout, or DefaultTimeout when it isn't positive.
+func timeoutOr(timeout time.Duration) time.Duration {
+	if timeout <= 0 {
+		return DefaultTimeout
+	}
+	return timeout
+}
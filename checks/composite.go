@@ -0,0 +1,31 @@
+package checks
+
+import (
+	"context"
+
+	"github.com/andres-vara/health"
+)
+
+// All returns a check passing when every one of checks passes. They run
+// concurrently and every failure is reported, combined with
+// health.JoinErrors so a degraded check can't hide a failing one.
+func All(checks ...health.CheckFunc) health.CheckFunc {
+	return func(ctx context.Context) error {
+		return health.JoinErrors(probe(ctx, checks)...)
+	}
+}
+
+// Any returns a check passing when at least one of checks passes, like
+// storage being healthy if either S3 or the local disk cache works. They run
+// concurrently and, when they all fail, every failure is reported.
+func Any(checks ...health.CheckFunc) health.CheckFunc {
+	return func(ctx context.Context) error {
+		errs := probe(ctx, checks)
+		for _, err := range errs {
+			if err == nil {
+				return nil
+			}
+		}
+		return health.JoinErrors(errs...)
+	}
+}
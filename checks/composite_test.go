@@ -0,0 +1,70 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/andres-vara/health"
+)
+
+func TestComposite(t *testing.T) {
+	up := func(ctx context.Context) error { return nil }
+	s3 := func(ctx context.Context) error { return health.DependencyError(errors.New("s3 unreachable")) }
+	disk := func(ctx context.Context) error { return errors.New("disk full") }
+
+	tests := []struct {
+		name   string
+		check  health.CheckFunc
+		reason string
+	}{
+		{"all passing", All(up, up), ""},
+		{"all failing one", All(up, s3), "s3 unreachable"},
+		{"all failing several", All(s3, up, disk), "s3 unreachable\ndisk full"},
+		{"any passing", Any(s3, up), ""},
+		{"any failing", Any(s3, disk), "s3 unreachable\ndisk full"},
+		{"empty all", All(), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.check(context.Background())
+			reason := ""
+			if err != nil {
+				reason = err.Error()
+			}
+			if reason != tt.reason {
+				t.Errorf("got %q want %q", reason, tt.reason)
+			}
+		})
+	}
+
+	// Categories survive the combination
+	if category := health.CategoryOf(Any(s3, disk)(context.Background())); category != health.CategoryDependency {
+		t.Errorf("unexpected category: %q", category)
+	}
+}
+
+func TestCompositeMixedFailures(t *testing.T) {
+	db := func(ctx context.Context) error { return errors.New("db down") }
+	cache := func(ctx context.Context) error { return health.DegradedError(errors.New("cache slow")) }
+
+	h := health.New()
+	h.Register("combo", All(db, cache))
+	h.Register("caches", All(cache, cache))
+	h.Register("fallback", Any(cache, db))
+	results := map[string]health.CheckResult{}
+	for _, result := range h.Evaluate(context.Background()) {
+		results[result.Name] = result
+	}
+
+	if r := results["combo"]; r.Status != health.Down || r.Reason != "db down\ncache slow" {
+		t.Errorf("a degraded check hid a failing one: %+v", r)
+	}
+	if r := results["caches"]; r.Status != health.Degraded {
+		t.Errorf("expected checks all degraded to be degraded: %+v", r)
+	}
+	if r := results["fallback"]; r.Status != health.Down {
+		t.Errorf("a degraded check hid a failing one: %+v", r)
+	}
+}
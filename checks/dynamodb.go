@@ -0,0 +1,48 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/andres-vara/health"
+)
+
+// DynamoDBTables describes DynamoDB tables. It is implemented by wrapping
+// the AWS SDK client, so this package doesn't depend on it:
+//
+//	type tables struct{ *dynamodb.Client }
+//
+//	func (t tables) DescribeTable(ctx context.Context, table string) (string, error) {
+//		out, err := t.Client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: &table})
+//		if err != nil {
+//			return "", err
+//		}
+//		return string(out.Table.TableStatus), nil
+//	}
+//
+// A cheap GetItem on a known key works too, returning "ACTIVE" when it
+// succeeds.
+type DynamoDBTables interface {
+	// DescribeTable returns the status of table, such as "ACTIVE"
+	DescribeTable(ctx context.Context, table string) (status string, err error)
+}
+
+// DynamoDB returns a check that table is reachable and serving, that is
+// ACTIVE or UPDATING. Errors are classified from their AWS error code:
+// throttling, bad credentials and a missing table are told apart from an
+// unavailable service in the "error_class" detail, as they call for
+// different fixes.
+func DynamoDB(tables DynamoDBTables, table string) health.CheckFunc {
+	return func(ctx context.Context) error {
+		status, err := tables.DescribeTable(ctx, table)
+		if err != nil {
+			return awsError(ctx, table, err)
+		}
+		health.SetDetail(ctx, "table_status", status)
+
+		if status != "ACTIVE" && status != "UPDATING" {
+			return health.DependencyError(fmt.Errorf("table %s is %s", table, status))
+		}
+		return nil
+	}
+}
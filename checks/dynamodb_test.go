@@ -0,0 +1,62 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/andres-vara/health"
+)
+
+// apiError mimics the AWS SDK's smithy.APIError.
+type apiError struct {
+	code string
+}
+
+func (e apiError) Error() string     { return "api error " + e.code }
+func (e apiError) ErrorCode() string { return e.code }
+
+type fakeTables struct {
+	status string
+	err    error
+}
+
+func (f fakeTables) DescribeTable(ctx context.Context, table string) (string, error) {
+	return f.status, f.err
+}
+
+func TestDynamoDB(t *testing.T) {
+	tests := []struct {
+		tables   fakeTables
+		category health.Category
+		class    string
+	}{
+		{tables: fakeTables{status: "ACTIVE"}},
+		{tables: fakeTables{status: "UPDATING"}},
+		{tables: fakeTables{status: "DELETING"}, category: health.CategoryDependency},
+		{
+			tables:   fakeTables{err: fmt.Errorf("operation error: %w", apiError{"ProvisionedThroughputExceededException"})},
+			category: health.CategoryResourceExhaustion,
+			class:    "throttled",
+		},
+		{tables: fakeTables{err: apiError{"AccessDeniedException"}}, category: health.CategoryConfiguration, class: "auth"},
+		{tables: fakeTables{err: apiError{"ResourceNotFoundException"}}, category: health.CategoryConfiguration, class: "not_found"},
+		{tables: fakeTables{err: errors.New("dial tcp: i/o timeout")}, category: health.CategoryDependency, class: "unavailable"},
+	}
+
+	for _, tt := range tests {
+		h := health.Handle()
+		h.Register("dynamodb", DynamoDB(tt.tables, "orders"))
+		h.Evaluate(context.Background())
+		result := h.Results()[0]
+		health.Reset()
+
+		if result.Category != tt.category {
+			t.Errorf("%+v: expected category %q, got %q (%s)", tt.tables, tt.category, result.Category, result.Reason)
+		}
+		if class, _ := result.Details["error_class"].(string); class != tt.class {
+			t.Errorf("%+v: expected class %q, got %q", tt.tables, tt.class, class)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andres-vara/health"
+)
+
+// Etcd returns a check calling the /health endpoint of every member of an
+// etcd cluster, given by its client URL, concurrently. etcd keeps serving
+// as long as a quorum (a majority) of members is healthy: the check fails
+// below it and is degraded while some members are down.
+// Each member's outcome is reported as a detail, like Quorum does. opts
+// configure the requests, such as WithHTTPClient for TLS client
+// certificates.
+func Etcd(endpoints []string, opts ...HTTPOption) health.CheckFunc {
+	opts = append([]HTTPOption{ExpectJSONField("health", "true")}, opts...)
+
+	checks := make([]health.CheckFunc, len(endpoints))
+	for i, endpoint := range endpoints {
+		checks[i] = HTTP(strings.TrimSuffix(endpoint, "/")+"/health", opts...)
+	}
+	quorum := len(endpoints)/2 + 1
+
+	return func(ctx context.Context) error {
+		healthy := 0
+		for i, err := range probe(ctx, checks) {
+			if err == nil {
+				healthy++
+				health.SetDetail(ctx, endpoints[i], string(health.Up))
+			} else {
+				health.SetDetail(ctx, endpoints[i], err.Error())
+			}
+		}
+
+		switch {
+		case healthy < quorum:
+			return health.DependencyError(fmt.Errorf("%d of %d etcd members healthy, quorum is %d", healthy, len(endpoints), quorum))
+		case healthy < len(endpoints):
+			return health.DegradedError(fmt.Errorf("%d of %d etcd members healthy", healthy, len(endpoints)))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,55 @@
+package checks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andres-vara/health"
+)
+
+func etcdMember(t *testing.T, healthy bool) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			http.NotFound(w, r)
+			return
+		}
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"health":"false","reason":"RAFT NO LEADER"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"health":"true","reason":""}`))
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestEtcd(t *testing.T) {
+	defer health.Reset()
+
+	up1, up2, down := etcdMember(t, true), etcdMember(t, true), etcdMember(t, false)
+
+	h := health.Handle()
+	h.Register("all", Etcd([]string{up1, up2}))
+	h.Register("quorum", Etcd([]string{up1, up2, down}))
+	h.Register("lost", Etcd([]string{up1, down, down + "/"}))
+	h.Evaluate(context.Background())
+
+	results := map[string]health.CheckResult{}
+	for _, result := range h.Results() {
+		results[result.Name] = result
+	}
+
+	if r := results["all"]; r.Status != health.Up {
+		t.Errorf("expected a healthy cluster to pass, got %+v", r)
+	}
+	r := results["quorum"]
+	if r.Status != health.Degraded || r.Details[up1] != string(health.Up) {
+		t.Errorf("expected one member down to degrade the check, got %+v", r)
+	}
+	if r := results["lost"]; r.Status != health.Down {
+		t.Errorf("expected losing quorum to fail, got %+v", r)
+	}
+}
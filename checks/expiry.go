@@ -0,0 +1,98 @@
+package checks
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+// now is the time source of time-based checks, a variable so tests can
+// move it.
+var now = time.Now
+
+// ExpirySource returns when a credential expires.
+type ExpirySource func(ctx context.Context) (time.Time, error)
+
+// ExpiresAt is the source of a fixed expiry, such as a license's.
+func ExpiresAt(t time.Time) ExpirySource {
+	return func(ctx context.Context) (time.Time, error) {
+		return t, nil
+	}
+}
+
+// JWTExpiry is the source of the "exp" claim of a JSON Web Token. The token
+// isn't verified: whoever consumes it does that.
+func JWTExpiry(token string) ExpirySource {
+	return func(ctx context.Context) (time.Time, error) {
+		return jwtExpiry(token)
+	}
+}
+
+// JWTFileExpiry is the source of the "exp" claim of the JSON Web Token in
+// the file at path, read on every check so rotated tokens are picked up,
+// like Kubernetes projected service account tokens.
+func JWTFileExpiry(path string) ExpirySource {
+	return func(ctx context.Context) (time.Time, error) {
+		token, err := os.ReadFile(path)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return jwtExpiry(strings.TrimSpace(string(token)))
+	}
+}
+
+func jwtExpiry(token string) (time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, errors.New("malformed token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("malformed token payload: %w", err)
+	}
+
+	var claims struct {
+		Exp *json.Number `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, fmt.Errorf("malformed token claims: %w", err)
+	}
+	if claims.Exp == nil {
+		return time.Time{}, errors.New("token without an exp claim")
+	}
+	exp, err := claims.Exp.Float64()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("malformed exp claim: %w", err)
+	}
+	return time.Unix(int64(exp), 0), nil
+}
+
+// Expiry returns a check on what, a license, API key or token, expiring at
+// the time given by expires: expired credentials are a scheduled outage
+// nobody schedules. The check is degraded once the expiry is less than
+// warn away, and fails once it has passed. The expiry
+// is reported as the "expires_at" detail.
+func Expiry(what string, expires ExpirySource, warn time.Duration) health.CheckFunc {
+	return func(ctx context.Context) error {
+		at, err := expires(ctx)
+		if err != nil {
+			return health.ConfigurationError(fmt.Errorf("reading expiry of %s: %w", what, err))
+		}
+		health.SetDetail(ctx, "expires_at", at.UTC().Format(time.RFC3339))
+
+		switch left := at.Sub(now()); {
+		case left <= 0:
+			return health.ConfigurationError(fmt.Errorf("%s expired at %s", what, at.UTC().Format(time.RFC3339)))
+		case left < warn:
+			return health.DegradedError(fmt.Errorf("%s expires in %v", what, left.Round(time.Minute)))
+		}
+		return nil
+	}
+}
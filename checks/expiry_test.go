@@ -0,0 +1,58 @@
+package checks
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+func testJWT(claims string) string {
+	encode := base64.RawURLEncoding.EncodeToString
+	return encode([]byte(`{"alg":"none"}`)) + "." + encode([]byte(claims)) + ".sig"
+}
+
+func TestExpiry(t *testing.T) {
+	defer health.Reset()
+	defer func(f func() time.Time) { now = f }(now)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return start }
+
+	token := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(token, []byte(testJWT(`{"exp":1704110400}`)+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	h := health.Handle()
+	h.Register("fresh", Expiry("license", ExpiresAt(start.AddDate(1, 0, 0)), 30*24*time.Hour))
+	h.Register("soon", Expiry("license", ExpiresAt(start.AddDate(0, 0, 7)), 30*24*time.Hour))
+	h.Register("expired", Expiry("api key", ExpiresAt(start.Add(-time.Second)), time.Hour))
+	h.Register("token", Expiry("token", JWTFileExpiry(token), 24*time.Hour))
+	h.Register("no-exp", Expiry("token", JWTExpiry(testJWT(`{"sub":"me"}`)), time.Hour))
+	h.Evaluate(context.Background())
+
+	results := map[string]health.CheckResult{}
+	for _, result := range h.Results() {
+		results[result.Name] = result
+	}
+
+	if r := results["fresh"]; r.Status != health.Up {
+		t.Errorf("expected a distant expiry to pass quietly, got %+v", r)
+	}
+	if r := results["soon"]; r.Status != health.Degraded {
+		t.Errorf("expected a close expiry to degrade the check, got %+v", r)
+	}
+	if r := results["expired"]; r.Status != health.Down || r.Category != health.CategoryConfiguration {
+		t.Errorf("expected a passed expiry to fail, got %+v", r)
+	}
+	if r := results["token"]; r.Status != health.Degraded || r.Details["expires_at"] != "2024-01-01T12:00:00Z" {
+		t.Errorf("expected the token expiry to be read and degrade the check, got %+v", r)
+	}
+	if r := results["no-exp"]; r.Status != health.Down {
+		t.Errorf("expected a token without exp to fail, got %+v", r)
+	}
+}
@@ -0,0 +1,65 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/andres-vara/health"
+)
+
+// errFlagsNotReady is reported while the flag provider isn't initialized
+var errFlagsNotReady = errors.New("feature flag provider not initialized")
+
+// FlagProvider is a feature flag SDK client, LaunchDarkly or Unleash style,
+// that reports whether it has initialized. LaunchDarkly's client fits with
+// a one-line adapter around Initialized.
+type FlagProvider interface {
+	Ready() bool
+}
+
+// FlagPinger is a FlagProvider that can also check that its service is
+// reachable.
+type FlagPinger interface {
+	FlagProvider
+	Ping(ctx context.Context) error
+}
+
+// FlagOption configures a feature flag check.
+type FlagOption func(*bool)
+
+// FlagsWarnOnly makes the feature flag check degraded instead of failing, for SDKs falling back to cached or default flags.
+func FlagsWarnOnly() FlagOption {
+	return func(warnOnly *bool) {
+		*warnOnly = true
+	}
+}
+
+// FeatureFlags returns a check that provider has initialized and, when it
+// is a FlagPinger, that its service is reachable.
+func FeatureFlags(provider FlagProvider, opts ...FlagOption) health.CheckFunc {
+	var warnOnly bool
+	for _, opt := range opts {
+		opt(&warnOnly)
+	}
+
+	return func(ctx context.Context) error {
+		err := flagsError(ctx, provider)
+		if err != nil && warnOnly {
+			return health.DegradedError(err)
+		}
+		return err
+	}
+}
+
+func flagsError(ctx context.Context, provider FlagProvider) error {
+	if !provider.Ready() {
+		return health.DependencyError(errFlagsNotReady)
+	}
+	if pinger, ok := provider.(FlagPinger); ok {
+		if err := pinger.Ping(ctx); err != nil {
+			return health.DependencyError(fmt.Errorf("feature flag provider unreachable: %w", err))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/andres-vara/health"
+)
+
+type fakeFlags struct {
+	ready bool
+}
+
+func (f fakeFlags) Ready() bool { return f.ready }
+
+type fakePingingFlags struct {
+	fakeFlags
+	err error
+}
+
+func (f fakePingingFlags) Ping(ctx context.Context) error { return f.err }
+
+func TestFeatureFlags(t *testing.T) {
+	defer health.Reset()
+
+	unreachable := fakePingingFlags{fakeFlags{true}, errors.New("connection refused")}
+
+	h := health.Handle()
+	h.Register("ready", FeatureFlags(fakeFlags{true}))
+	h.Register("starting", FeatureFlags(fakeFlags{false}))
+	h.Register("unreachable", FeatureFlags(unreachable))
+	h.Register("cached", FeatureFlags(unreachable, FlagsWarnOnly()))
+	h.Evaluate(context.Background())
+
+	results := map[string]health.CheckResult{}
+	for _, result := range h.Results() {
+		results[result.Name] = result
+	}
+
+	if r := results["ready"]; r.Status != health.Up {
+		t.Errorf("expected a ready provider to pass, got %+v", r)
+	}
+	if r := results["starting"]; r.Status != health.Down {
+		t.Errorf("expected an uninitialized provider to fail, got %+v", r)
+	}
+	if r := results["unreachable"]; r.Status != health.Down || r.Category != health.CategoryDependency {
+		t.Errorf("expected an unreachable provider to fail, got %+v", r)
+	}
+	if r := results["cached"]; r.Status != health.Degraded {
+		t.Errorf("expected the check to be degraded only, got %+v", r)
+	}
+}
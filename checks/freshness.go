@@ -0,0 +1,85 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+// Job is a periodic job, such as a cron-driven import, whose staleness is
+// reported through health. Its Check fails when the job's last successful
+// run is too old:
+//
+//	job := checks.Freshness("nightly-import", 26*time.Hour)
+//	health.Register("nightly-import", job.Check)
+//	...
+//	job.MarkCompleted()
+type Job struct {
+	name    string
+	maxAge  time.Duration
+	created time.Time
+
+	mutex     sync.Mutex
+	completed time.Time
+	lastRun   func() (time.Time, error)
+}
+
+// Freshness tracks the job called name, whose last successful run should be
+// no older than maxAge. Runs are reported with MarkCompleted, or read with
+// WithLastRun. Until the first run the job is given maxAge from its
+// creation, so a restart doesn't fail a nightly job straight away.
+func Freshness(name string, maxAge time.Duration) *Job {
+	return &Job{name: name, maxAge: maxAge, created: now()}
+}
+
+// WithLastRun makes the job read the time of its last successful run from
+// lastRun, such as a timestamp the job stores in the database, instead of
+// relying on MarkCompleted.
+func (j *Job) WithLastRun(lastRun func() (time.Time, error)) *Job {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	j.lastRun = lastRun
+	return j
+}
+
+// MarkCompleted records a successful run of the job.
+func (j *Job) MarkCompleted() {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	j.completed = now()
+}
+
+// Check fails when the job's last successful run is older than its maximum
+// age.
+func (j *Job) Check(ctx context.Context) error {
+	j.mutex.Lock()
+	completed, lastRun := j.completed, j.lastRun
+	j.mutex.Unlock()
+
+	if lastRun != nil {
+		var err error
+		if completed, err = lastRun(); err != nil {
+			return health.DependencyError(fmt.Errorf("reading last run of %s: %w", j.name, err))
+		}
+	}
+
+	since := completed
+	if completed.IsZero() {
+		since = j.created
+	} else {
+		health.SetDetail(ctx, "completed_at", completed.UTC().Format(time.RFC3339))
+	}
+
+	if age := now().Sub(since); age > j.maxAge {
+		if completed.IsZero() {
+			return health.InternalError(fmt.Errorf("%s hasn't completed in %v, max age is %v", j.name, age.Round(time.Second), j.maxAge))
+		}
+		return health.InternalError(fmt.Errorf("%s last completed %v ago, max age is %v", j.name, age.Round(time.Second), j.maxAge))
+	}
+	return nil
+}
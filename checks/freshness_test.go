@@ -0,0 +1,44 @@
+package checks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+func TestFreshness(t *testing.T) {
+	defer func(f func() time.Time) { now = f }(now)
+	clock := time.Now()
+	now = func() time.Time { return clock }
+
+	job := Freshness("nightly-import", 25*time.Hour)
+	h := health.New()
+	h.Register("nightly-import", job.Check)
+	status := func() health.Status {
+		return h.Evaluate(context.Background())[0].Status
+	}
+
+	if s := status(); s != health.Up {
+		t.Errorf("expected a new job to get its max age, got %s", s)
+	}
+	clock = clock.Add(26 * time.Hour)
+	if s := status(); s != health.Down {
+		t.Errorf("expected a job that never ran to go stale, got %s", s)
+	}
+
+	job.MarkCompleted()
+	if s := status(); s != health.Up {
+		t.Errorf("expected a completed job to be fresh, got %s", s)
+	}
+	clock = clock.Add(26 * time.Hour)
+	if s := status(); s != health.Down {
+		t.Errorf("expected an old run to be stale, got %s", s)
+	}
+
+	job.WithLastRun(func() (time.Time, error) { return clock.Add(-time.Hour), nil })
+	if s := status(); s != health.Up {
+		t.Errorf("expected the stored timestamp to be used, got %s", s)
+	}
+}
@@ -0,0 +1,260 @@
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+// maxHTTPBody caps how much of a response body is read for assertions
+const maxHTTPBody = 1 << 20
+
+// HTTPOption configures an HTTP check.
+type HTTPOption func(*httpCheck)
+
+type httpCheck struct {
+	url        string
+	client     *http.Client
+	method     string
+	header     http.Header
+	statuses   []int
+	contains   []string
+	patterns   []*regexp.Regexp
+	fields     map[string]any
+	maxLatency time.Duration
+}
+
+// WithHTTPClient sets the client used for requests; http.DefaultClient by
+// default.
+func WithHTTPClient(client *http.Client) HTTPOption {
+	return func(c *httpCheck) {
+		c.client = client
+	}
+}
+
+// WithMethod sets the request method; GET by default.
+func WithMethod(method string) HTTPOption {
+	return func(c *httpCheck) {
+		c.method = method
+	}
+}
+
+// WithHeader adds a request header.
+func WithHeader(key, value string) HTTPOption {
+	return func(c *httpCheck) {
+		c.header.Add(key, value)
+	}
+}
+
+// ExpectStatus sets the acceptable response status codes. By default any
+// 2xx status passes.
+func ExpectStatus(codes ...int) HTTPOption {
+	return func(c *httpCheck) {
+		c.statuses = append(c.statuses, codes...)
+	}
+}
+
+// ExpectBodyContains fails the check unless the body contains s.
+func ExpectBodyContains(s string) HTTPOption {
+	return func(c *httpCheck) {
+		c.contains = append(c.contains, s)
+	}
+}
+
+// ExpectBodyMatches fails the check unless the body matches pattern.
+func ExpectBodyMatches(pattern *regexp.Regexp) HTTPOption {
+	return func(c *httpCheck) {
+		c.patterns = append(c.patterns, pattern)
+	}
+}
+
+// ExpectJSONField fails the check unless the JSON body has want at path. The
+// path is a dot-separated list of object keys and array indexes, like
+// "status" or "checks.0.status"; want is compared with the decoded value,
+// any Go number matching the same JSON number.
+func ExpectJSONField(path string, want any) HTTPOption {
+	return func(c *httpCheck) {
+		c.fields[path] = jsonNumber(want)
+	}
+}
+
+// jsonNumber converts numbers to float64, as JSON numbers are decoded, and
+// returns other values as they are.
+func jsonNumber(v any) any {
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		return value.Float()
+	}
+	return v
+}
+
+// MaxLatency fails the check when the response takes longer than d, even if
+// it is otherwise fine.
+func MaxLatency(d time.Duration) HTTPOption {
+	return func(c *httpCheck) {
+		c.maxLatency = d
+	}
+}
+
+// HTTP returns a check requesting url and validating the response. Without
+// options any 2xx response passes; options add assertions on the status
+// code, the body and the latency, since a 200 with {"status":"error"} is
+// still a failure. The host may be an SRV name such as
+// _api._tcp.orders.internal (its port is then ignored), in which case the
+// check passes when any of its targets does.
+func HTTP(url string, opts ...HTTPOption) health.CheckFunc {
+	c := newHTTPCheck(url, opts)
+
+	// An SRV name as the host is resolved on every evaluation, requesting
+	// its targets in turn
+	if u, err := neturl.Parse(url); err == nil && isSRVName(u.Hostname()) {
+		return SRV(u.Hostname(), func(hostport string) health.CheckFunc {
+			target := *c
+			u := *u
+			u.Host = hostport
+			target.url = u.String()
+			return target.check
+		})
+	}
+
+	return c.check
+}
+
+func newHTTPCheck(url string, opts []HTTPOption) *httpCheck {
+	c := &httpCheck{
+		url:    url,
+		client: http.DefaultClient,
+		method: http.MethodGet,
+		header: make(http.Header),
+		fields: make(map[string]any),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *httpCheck) check(ctx context.Context) error {
+	body, err := c.fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range c.contains {
+		if !strings.Contains(string(body), s) {
+			return health.DependencyError(fmt.Errorf("body does not contain %q", s))
+		}
+	}
+	for _, pattern := range c.patterns {
+		if !pattern.Match(body) {
+			return health.DependencyError(fmt.Errorf("body does not match %s", pattern))
+		}
+	}
+
+	if len(c.fields) > 0 {
+		var document any
+		if err := json.Unmarshal(body, &document); err != nil {
+			return health.DependencyError(fmt.Errorf("body is not JSON: %w", err))
+		}
+
+		// Check the fields in a stable order, so the reason is too
+		paths := make([]string, 0, len(c.fields))
+		for path := range c.fields {
+			paths = append(paths, path)
+		}
+		slices.Sort(paths)
+
+		for _, path := range paths {
+			got, ok := lookupJSON(document, path)
+			if !ok {
+				return health.DependencyError(fmt.Errorf("body has no field %q", path))
+			}
+			if want := c.fields[path]; !reflect.DeepEqual(got, want) {
+				return health.DependencyError(fmt.Errorf("field %q is %v, expected %v", path, got, want))
+			}
+		}
+	}
+
+	return nil
+}
+
+// fetch sends the request and returns the body of a response with an
+// accepted status, within the latency limit.
+func (c *httpCheck) fetch(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, c.method, c.url, nil)
+	if err != nil {
+		return nil, health.ConfigurationError(err)
+	}
+	for key, values := range c.header {
+		req.Header[key] = values
+	}
+
+	start := time.Now()
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, health.DependencyError(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxHTTPBody))
+	if err != nil {
+		return nil, health.DependencyError(fmt.Errorf("reading response: %w", err))
+	}
+	latency := time.Since(start)
+
+	if !c.statusAccepted(resp.StatusCode) {
+		return nil, health.DependencyError(fmt.Errorf("unexpected status %d", resp.StatusCode))
+	}
+	if c.maxLatency > 0 && latency > c.maxLatency {
+		return nil, health.DependencyError(fmt.Errorf("response took %v, more than %v", latency.Round(time.Millisecond), c.maxLatency))
+	}
+
+	return body, nil
+}
+
+func (c *httpCheck) statusAccepted(code int) bool {
+	if len(c.statuses) == 0 {
+		return code >= 200 && code < 300
+	}
+	return slices.Contains(c.statuses, code)
+}
+
+// lookupJSON follows a dot-separated path through a decoded JSON document.
+func lookupJSON(document any, path string) (any, bool) {
+	current := document
+	for _, key := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			current = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
@@ -0,0 +1,93 @@
+package checks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+func TestHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.Write([]byte(`{"status":"UP","checks":[{"name":"db","status":"UP"}],"version":3}`))
+		case "/error":
+			w.Write([]byte(`{"status":"error"}`))
+		case "/teapot":
+			w.WriteHeader(http.StatusTeapot)
+		case "/slow":
+			time.Sleep(50 * time.Millisecond)
+		case "/text":
+			w.Write([]byte("all systems nominal"))
+		case "/header":
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		path   string
+		opts   []HTTPOption
+		reason string
+	}{
+		{"2xx by default", "/ok", nil, ""},
+		{"unexpected status", "/teapot", nil, "unexpected status 418"},
+		{"expected status", "/teapot", []HTTPOption{ExpectStatus(http.StatusTeapot)}, ""},
+		{"json field", "/ok", []HTTPOption{ExpectJSONField("status", "UP")}, ""},
+		{"200 with error body", "/error", []HTTPOption{ExpectJSONField("status", "UP")}, `field "status" is error, expected UP`},
+		{"nested json field", "/ok", []HTTPOption{ExpectJSONField("checks.0.status", "UP")}, ""},
+		{"json number", "/ok", []HTTPOption{ExpectJSONField("version", 3.0)}, ""},
+		{"json int", "/ok", []HTTPOption{ExpectJSONField("version", 3)}, ""},
+		{"json uint", "/ok", []HTTPOption{ExpectJSONField("version", uint8(3))}, ""},
+		{"json wrong int", "/ok", []HTTPOption{ExpectJSONField("version", 4)}, `field "version" is 3, expected 4`},
+		{"missing json field", "/ok", []HTTPOption{ExpectJSONField("checks.1.status", "UP")}, `body has no field "checks.1.status"`},
+		{"not json", "/text", []HTTPOption{ExpectJSONField("status", "UP")}, "body is not JSON"},
+		{"substring", "/text", []HTTPOption{ExpectBodyContains("nominal")}, ""},
+		{"missing substring", "/text", []HTTPOption{ExpectBodyContains("degraded")}, `body does not contain "degraded"`},
+		{"regexp", "/text", []HTTPOption{ExpectBodyMatches(regexp.MustCompile(`^all \w+`))}, ""},
+		{"regexp mismatch", "/text", []HTTPOption{ExpectBodyMatches(regexp.MustCompile(`^none`))}, "body does not match ^none"},
+		{"latency", "/slow", []HTTPOption{MaxLatency(10 * time.Millisecond)}, "more than 10ms"},
+		{"header", "/header", []HTTPOption{WithHeader("Authorization", "Bearer secret")}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := HTTP(server.URL+tt.path, tt.opts...)(context.Background())
+			if tt.reason == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.reason) {
+				t.Fatalf("expected an error containing %q, got %v", tt.reason, err)
+			}
+			if health.CategoryOf(err) != health.CategoryDependency {
+				t.Errorf("unexpected category: %q", health.CategoryOf(err))
+			}
+		})
+	}
+}
+
+func TestHTTPUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	err := HTTP(url)(context.Background())
+	if err == nil {
+		t.Fatal("expected an error for an unreachable server")
+	}
+	if health.CategoryOf(err) != health.CategoryDependency {
+		t.Errorf("unexpected category: %q", health.CategoryOf(err))
+	}
+}
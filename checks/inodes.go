@@ -0,0 +1,37 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/andres-vara/health"
+)
+
+// Inodes returns a check on the share of inodes used on the filesystem
+// holding path, since containers writing many small files run out of inodes
+// while disk space still looks fine. Shares are between 0 and 1: the check
+// fails when the used share reaches fail, and is degraded once it reaches
+// warn. The share is reported as the "inodes_used"
+// detail. Filesystems without a fixed inode count always pass.
+func Inodes(path string, warn, fail float64) health.CheckFunc {
+	return func(ctx context.Context) error {
+		total, free, err := inodeCounts(path)
+		if err != nil {
+			return health.ConfigurationError(err)
+		}
+		if total == 0 {
+			return nil
+		}
+
+		used := float64(total-free) / float64(total)
+		health.SetDetail(ctx, "inodes_used", used)
+
+		switch {
+		case used >= fail:
+			return health.ResourceExhaustionError(fmt.Errorf("%.1f%% of inodes used on %s, failing at %.1f%%", used*100, path, fail*100))
+		case used >= warn:
+			return health.DegradedError(fmt.Errorf("%.1f%% of inodes used on %s", used*100, path))
+		}
+		return nil
+	}
+}
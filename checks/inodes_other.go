@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package checks
+
+import "errors"
+
+// inodeCounts isn't supported on this platform.
+func inodeCounts(path string) (total, free uint64, err error) {
+	return 0, 0, errors.New("inode counts not supported on this platform")
+}
@@ -0,0 +1,38 @@
+package checks
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/andres-vara/health"
+)
+
+func TestInodes(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("inode counts not supported")
+	}
+
+	dir := t.TempDir()
+	total, free, err := inodeCounts(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total == 0 {
+		t.Skip("filesystem without a fixed inode count")
+	}
+	used := float64(total-free) / float64(total)
+
+	if err := Inodes(dir, 1, 1.01)(context.Background()); err != nil {
+		t.Errorf("expected the check to pass, got %v", err)
+	}
+	err = Inodes(dir, 0, used/2)(context.Background())
+	if health.CategoryOf(err) != health.CategoryResourceExhaustion {
+		t.Errorf("expected a resource exhaustion error, got %v", err)
+	}
+
+	err = Inodes(dir+"/missing", 0.8, 0.9)(context.Background())
+	if health.CategoryOf(err) != health.CategoryConfiguration {
+		t.Errorf("expected a configuration error for a missing path, got %v", err)
+	}
+}
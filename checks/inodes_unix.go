@@ -0,0 +1,18 @@
+//go:build linux || darwin
+
+package checks
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// inodeCounts returns the total and free inodes of the filesystem holding
+// path.
+func inodeCounts(path string) (total, free uint64, err error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, fmt.Errorf("statfs %s: %w", path, err)
+	}
+	return stat.Files, stat.Ffree, nil
+}
@@ -0,0 +1,72 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+// errJWKSNeverRefreshed is reported until the key set is first refreshed
+var errJWKSNeverRefreshed = errors.New("JWKS never refreshed")
+
+// JWKS tracks the refreshes of a cached JSON Web Key Set, for services
+// validating JWTs. Stale keys reject tokens signed with rotated keys,
+// which looks like an auth bug rather than a refresh problem, so its Check
+// fails when the set wasn't refreshed within its max age or when the last
+// refresh failed:
+//
+//	keys := checks.NewJWKS(time.Hour)
+//	health.Register("jwks", keys.Check)
+//	...
+//	keys.Refreshed(cache.Refresh(ctx))
+type JWKS struct {
+	maxAge time.Duration
+
+	mutex     sync.Mutex
+	refreshed time.Time
+	err       error
+}
+
+// NewJWKS tracks a key set that must be refreshed at least every maxAge.
+func NewJWKS(maxAge time.Duration) *JWKS {
+	return &JWKS{maxAge: maxAge}
+}
+
+// Refreshed records the outcome of a refresh of the key set. A failed
+// refresh keeps the time of the last successful one.
+func (k *JWKS) Refreshed(err error) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	k.err = err
+	if err == nil {
+		k.refreshed = now()
+	}
+}
+
+// Check is the health check of the key set. It reports the last successful
+// refresh as the "refreshed_at" detail.
+func (k *JWKS) Check(ctx context.Context) error {
+	k.mutex.Lock()
+	refreshed, err := k.refreshed, k.err
+	k.mutex.Unlock()
+
+	if !refreshed.IsZero() {
+		health.SetDetail(ctx, "refreshed_at", refreshed.UTC().Format(time.RFC3339))
+	}
+
+	switch {
+	case err != nil:
+		return health.DependencyError(fmt.Errorf("refreshing JWKS: %w", err))
+	case refreshed.IsZero():
+		return health.DependencyError(errJWKSNeverRefreshed)
+	}
+	if age := now().Sub(refreshed); age > k.maxAge {
+		return health.DependencyError(fmt.Errorf("JWKS not refreshed for %v, max age is %v", age.Round(time.Second), k.maxAge))
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJWKS(t *testing.T) {
+	defer func(f func() time.Time) { now = f }(now)
+	clock := time.Now()
+	now = func() time.Time { return clock }
+
+	keys := NewJWKS(time.Hour)
+	if err := keys.Check(context.Background()); err == nil {
+		t.Error("expected a never refreshed key set to fail")
+	}
+
+	keys.Refreshed(nil)
+	if err := keys.Check(context.Background()); err != nil {
+		t.Errorf("expected a fresh key set to pass, got %v", err)
+	}
+
+	keys.Refreshed(errors.New("issuer unreachable"))
+	if err := keys.Check(context.Background()); err == nil {
+		t.Error("expected a failed refresh to fail")
+	}
+
+	keys.Refreshed(nil)
+	clock = clock.Add(2 * time.Hour)
+	if err := keys.Check(context.Background()); err == nil {
+		t.Error("expected a stale key set to fail")
+	}
+}
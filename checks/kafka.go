@@ -0,0 +1,82 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/andres-vara/health"
+)
+
+// KafkaOffsets reads offsets from Kafka. It is implemented by wrapping the
+// application's own Kafka client, so this package doesn't depend on one.
+type KafkaOffsets interface {
+	// CommittedOffsets returns the offset committed by group for every
+	// partition of topic it has committed to
+	CommittedOffsets(ctx context.Context, group, topic string) (map[int32]int64, error)
+	// EndOffsets returns the log-end offset of every partition of topic
+	EndOffsets(ctx context.Context, topic string) (map[int32]int64, error)
+}
+
+// KafkaConsumer is a consumer group reading a topic.
+type KafkaConsumer struct {
+	Group string
+	Topic string
+}
+
+// KafkaLag returns a check comparing the committed offsets of consumers
+// against the log-end offsets, since a consumer connected but hopelessly
+// behind is an outage too. The lag of a consumer is the sum over the
+// topic's partitions, counting the whole log for partitions without a
+// committed offset. The check fails when any lag reaches fail, and is
+// degraded when one reaches warn. Every lag is reported as a
+// "group/topic" detail.
+func KafkaLag(offsets KafkaOffsets, warn, fail int64, consumers ...KafkaConsumer) health.CheckFunc {
+	return func(ctx context.Context) error {
+		var failed, warned error
+		for _, consumer := range consumers {
+			lag, err := kafkaLag(ctx, offsets, consumer)
+			if err != nil {
+				return health.DependencyError(err)
+			}
+
+			key := consumer.Group + "/" + consumer.Topic
+			health.SetDetail(ctx, key, lag)
+
+			switch {
+			case lag >= fail && failed == nil:
+				failed = fmt.Errorf("%s is %d messages behind, failing at %d", key, lag, fail)
+			case lag >= warn && warned == nil:
+				warned = fmt.Errorf("%s is %d messages behind, degraded at %d", key, lag, warn)
+			}
+		}
+
+		if failed != nil {
+			return health.DependencyError(failed)
+		}
+		if warned != nil {
+			return health.DegradedError(warned)
+		}
+		return nil
+	}
+}
+
+// kafkaLag sums the lag of consumer over the partitions of its topic.
+func kafkaLag(ctx context.Context, offsets KafkaOffsets, consumer KafkaConsumer) (int64, error) {
+	end, err := offsets.EndOffsets(ctx, consumer.Topic)
+	if err != nil {
+		return 0, fmt.Errorf("reading end offsets of %s: %w", consumer.Topic, err)
+	}
+	committed, err := offsets.CommittedOffsets(ctx, consumer.Group, consumer.Topic)
+	if err != nil {
+		return 0, fmt.Errorf("reading offsets of %s on %s: %w", consumer.Group, consumer.Topic, err)
+	}
+
+	var lag int64
+	for partition, endOffset := range end {
+		// Without a committed offset (or with Kafka's -1 for none) the whole
+		// log is pending
+		offset := max(committed[partition], 0)
+		lag += max(endOffset-offset, 0)
+	}
+	return lag, nil
+}
@@ -0,0 +1,54 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type fakeOffsets struct {
+	end       map[string]map[int32]int64
+	committed map[string]map[int32]int64
+	err       error
+}
+
+func (f *fakeOffsets) CommittedOffsets(ctx context.Context, group, topic string) (map[int32]int64, error) {
+	return f.committed[group+"/"+topic], f.err
+}
+
+func (f *fakeOffsets) EndOffsets(ctx context.Context, topic string) (map[int32]int64, error) {
+	return f.end[topic], f.err
+}
+
+func TestKafkaLag(t *testing.T) {
+	offsets := &fakeOffsets{
+		end: map[string]map[int32]int64{
+			"orders":   {0: 1000, 1: 2000},
+			"payments": {0: 500},
+		},
+		committed: map[string]map[int32]int64{
+			// 100 + 50 behind
+			"billing/orders": {0: 900, 1: 1950},
+			// Partition 0 was never committed
+			"audit/payments": {},
+		},
+	}
+
+	consumers := []KafkaConsumer{{"billing", "orders"}, {"audit", "payments"}}
+
+	if err := KafkaLag(offsets, 1000, 5000, consumers...)(context.Background()); err != nil {
+		t.Errorf("expected the lag to be acceptable, got %v", err)
+	}
+
+	err := KafkaLag(offsets, 100, 500, consumers...)(context.Background())
+	if err == nil || err.Error() != "audit/payments is 500 messages behind, failing at 500" {
+		t.Errorf("expected audit to fail, got %v", err)
+	}
+
+	offsets.err = errors.New("broker unavailable")
+	err = KafkaLag(offsets, 100, 500, consumers...)(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "broker unavailable") {
+		t.Errorf("expected the client error, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/andres-vara/health"
+)
+
+// Listeners returns a check dialing the process's own listeners, given as
+// the addresses they were bound to (":8080", "0.0.0.0:9090"), to confirm
+// they accept connections. It catches the health port being up while the
+// application listener was never bound. Wildcard and empty hosts are dialed
+// on the loopback interface.
+func Listeners(addrs ...string) health.CheckFunc {
+	return func(ctx context.Context) error {
+		var dialer net.Dialer
+		for _, addr := range addrs {
+			target, err := loopbackAddr(addr)
+			if err != nil {
+				return health.ConfigurationError(err)
+			}
+
+			conn, err := dialer.DialContext(ctx, "tcp", target)
+			if err != nil {
+				return health.InternalError(fmt.Errorf("listener %s not accepting connections: %w", addr, err))
+			}
+			conn.Close()
+		}
+		return nil
+	}
+}
+
+// loopbackAddr turns the address a listener was bound to into one that can
+// be dialed from the same process.
+func loopbackAddr(addr string) (string, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", fmt.Errorf("invalid listener address %q: %w", addr, err)
+	}
+
+	switch host {
+	case "", "0.0.0.0":
+		host = "127.0.0.1"
+	case "::":
+		host = "::1"
+	}
+	return net.JoinHostPort(host, port), nil
+}
@@ -0,0 +1,67 @@
+package checks
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/andres-vara/health"
+)
+
+func TestListeners(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	if err := Listeners(":"+port, listener.Addr().String())(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// A port nothing listens on any more
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := closed.Addr().String()
+	closed.Close()
+
+	err = Listeners(listener.Addr().String(), addr)(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "listener "+addr) {
+		t.Errorf("expected the closed listener to fail, got %v", err)
+	}
+	if health.CategoryOf(err) != health.CategoryInternal {
+		t.Errorf("unexpected category: %q", health.CategoryOf(err))
+	}
+
+	err = Listeners("8080")(context.Background())
+	if health.CategoryOf(err) != health.CategoryConfiguration {
+		t.Errorf("expected a configuration error for an invalid address, got %v", err)
+	}
+}
+
+func TestLoopbackAddr(t *testing.T) {
+	tests := map[string]string{
+		":8080":         "127.0.0.1:8080",
+		"0.0.0.0:8080":  "127.0.0.1:8080",
+		"[::]:8080":     "[::1]:8080",
+		"10.0.0.1:8080": "10.0.0.1:8080",
+	}
+	for addr, want := range tests {
+		if got, err := loopbackAddr(addr); err != nil || got != want {
+			t.Errorf("loopbackAddr(%q) = %q, %v; want %q", addr, got, err, want)
+		}
+	}
+}
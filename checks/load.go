@@ -0,0 +1,20 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/andres-vara/health"
+)
+
+// Load returns a check reporting the service as OVERLOADED while gauge
+// (in-flight requests, queue depth, ...) is above limit, so it answers 429
+// and clients back off instead of treating the instance as broken.
+func Load(gauge health.Gauge, limit float64) health.CheckFunc {
+	return func(ctx context.Context) error {
+		if value := gauge(); value > limit {
+			return health.OverloadError(fmt.Errorf("load %g above %g", value, limit))
+		}
+		return nil
+	}
+}
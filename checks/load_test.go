@@ -0,0 +1,26 @@
+package checks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/andres-vara/health"
+)
+
+func TestLoad(t *testing.T) {
+	inFlight := 10.0
+	h := health.Handle()
+	health.Reset()
+	defer health.Reset()
+
+	h.Register("in-flight", Load(func() float64 { return inFlight }, 100))
+	if results := h.Evaluate(context.Background()); results[0].Status != health.Up {
+		t.Errorf("expected UP under the limit, got %v", results[0].Status)
+	}
+
+	inFlight = 150
+	results := h.Evaluate(context.Background())
+	if results[0].Status != health.Overloaded || results[0].Reason != "load 150 above 100" {
+		t.Errorf("expected OVERLOADED over the limit, got %v %q", results[0].Status, results[0].Reason)
+	}
+}
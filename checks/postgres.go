@@ -0,0 +1,39 @@
+package checks
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+// postgresLagQuery returns the replication lag in seconds. On a replica it
+// is the age of the last replayed transaction, zero when everything received
+// was replayed (an idle primary doesn't make a replica stale). On a primary
+// it is the largest replay lag of its replicas from pg_stat_replication.
+const postgresLagQuery = `SELECT CASE WHEN pg_is_in_recovery() THEN
+	CASE WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0) END
+ELSE COALESCE((SELECT EXTRACT(EPOCH FROM MAX(replay_lag)) FROM pg_stat_replication), 0) END`
+
+// PostgresReplicationLag returns a check failing when Postgres replication
+// lags by more than max, for services reading from replicas. Given a replica
+// it measures how far the replica is behind; given a primary, how far its
+// slowest replica is. The lag is reported as the "replication_lag_seconds"
+// detail.
+func PostgresReplicationLag(db RowQuerier, max time.Duration) health.CheckFunc {
+	return func(ctx context.Context) error {
+		var seconds sql.NullFloat64
+		if err := db.QueryRowContext(ctx, postgresLagQuery).Scan(&seconds); err != nil {
+			return health.DependencyError(fmt.Errorf("reading replication lag: %w", err))
+		}
+		health.SetDetail(ctx, "replication_lag_seconds", seconds.Float64)
+
+		if lag := time.Duration(seconds.Float64 * float64(time.Second)); lag > max {
+			return health.DependencyError(fmt.Errorf("replication lagging by %v, more than %v", lag.Round(time.Millisecond), max))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,47 @@
+package checks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+func TestPostgresReplicationLag(t *testing.T) {
+	d := &versionDriver{}
+	sql.Register("test-postgres-lag", d)
+	db, err := sql.Open("test-postgres-lag", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	check := PostgresReplicationLag(db, 5*time.Second)
+
+	d.version = 1.5
+	if err := check(context.Background()); err != nil {
+		t.Errorf("expected a small lag to pass, got %v", err)
+	}
+	if !strings.Contains(d.query, "pg_stat_replication") {
+		t.Errorf("unexpected query: %q", d.query)
+	}
+
+	d.version = 12.0
+	if err := check(context.Background()); health.CategoryOf(err) != health.CategoryDependency {
+		t.Errorf("expected a large lag to fail, got %v", err)
+	}
+
+	d.version = nil
+	if err := check(context.Background()); err != nil {
+		t.Errorf("expected an unknown lag to pass, got %v", err)
+	}
+
+	d.err = errors.New("connection refused")
+	if err := check(context.Background()); err == nil {
+		t.Error("expected a query error to fail")
+	}
+}
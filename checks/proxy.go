@@ -0,0 +1,104 @@
+package checks
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	neturl "net/url"
+
+	"github.com/andres-vara/health"
+)
+
+// ProxyConnect returns a check that the HTTP(S) proxy at proxy accepts a
+// CONNECT to destination, a host:port. When proxy is empty, the proxy the
+// environment (HTTPS_PROXY, NO_PROXY) selects for destination is used. A
+// broken proxy otherwise shows up as baffling failures of every dependency
+// behind it. The proxy used is reported as the "proxy" detail.
+func ProxyConnect(proxy, destination string) health.CheckFunc {
+	return func(ctx context.Context) error {
+		proxyURL, err := proxyFor(proxy, destination)
+		if err != nil {
+			return health.ConfigurationError(err)
+		}
+		health.SetDetail(ctx, "proxy", proxyURL.Redacted())
+
+		conn, err := dialProxy(ctx, proxyURL)
+		if err != nil {
+			return health.DependencyError(fmt.Errorf("proxy unreachable: %w", err))
+		}
+		defer conn.Close()
+		if deadline, ok := ctx.Deadline(); ok {
+			_ = conn.SetDeadline(deadline)
+		}
+
+		req := &http.Request{
+			Method: http.MethodConnect,
+			URL:    &neturl.URL{Opaque: destination},
+			Host:   destination,
+			Header: make(http.Header),
+		}
+		if user := proxyURL.User; user != nil {
+			password, _ := user.Password()
+			credentials := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+			req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+		}
+		if err := req.Write(conn); err != nil {
+			return health.DependencyError(fmt.Errorf("proxy connect: %w", err))
+		}
+
+		resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+		if err != nil {
+			return health.DependencyError(fmt.Errorf("proxy connect: %w", err))
+		}
+		resp.Body.Close()
+
+		switch {
+		case resp.StatusCode == http.StatusProxyAuthRequired:
+			return health.ConfigurationError(fmt.Errorf("proxy refused CONNECT to %s: %s", destination, resp.Status))
+		case resp.StatusCode < 200 || resp.StatusCode > 299:
+			return health.DependencyError(fmt.Errorf("proxy refused CONNECT to %s: %s", destination, resp.Status))
+		}
+		return nil
+	}
+}
+
+// proxyFor parses proxy, or looks up the proxy for destination in the
+// environment when it is empty.
+func proxyFor(proxy, destination string) (*neturl.URL, error) {
+	if proxy != "" {
+		return neturl.Parse(proxy)
+	}
+
+	req := &http.Request{URL: &neturl.URL{Scheme: "https", Host: destination}}
+	proxyURL, err := http.ProxyFromEnvironment(req)
+	if err != nil {
+		return nil, err
+	}
+	if proxyURL == nil {
+		return nil, errors.New("no proxy configured for " + destination)
+	}
+	return proxyURL, nil
+}
+
+func dialProxy(ctx context.Context, proxy *neturl.URL) (net.Conn, error) {
+	host := proxy.Host
+	if proxy.Port() == "" {
+		port := "80"
+		if proxy.Scheme == "https" {
+			port = "443"
+		}
+		host = net.JoinHostPort(proxy.Hostname(), port)
+	}
+
+	if proxy.Scheme == "https" {
+		dialer := &tls.Dialer{Config: &tls.Config{ServerName: proxy.Hostname()}}
+		return dialer.DialContext(ctx, "tcp", host)
+	}
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, "tcp", host)
+}
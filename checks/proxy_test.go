@@ -0,0 +1,49 @@
+package checks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andres-vara/health"
+)
+
+func TestProxyConnect(t *testing.T) {
+	var auth string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Proxy-Authorization")
+		switch {
+		case r.Method != http.MethodConnect:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case r.Host == "allowed.example:443":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer proxy.Close()
+
+	if err := ProxyConnect(proxy.URL, "allowed.example:443")(context.Background()); err != nil {
+		t.Errorf("expected an accepted CONNECT to pass, got %v", err)
+	}
+
+	err := ProxyConnect(proxy.URL, "blocked.example:443")(context.Background())
+	if health.CategoryOf(err) != health.CategoryDependency {
+		t.Errorf("expected a refused CONNECT to fail, got %v", err)
+	}
+
+	withUser := "http://user:secret@" + proxy.Listener.Addr().String()
+	if err := ProxyConnect(withUser, "allowed.example:443")(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if auth != "Basic dXNlcjpzZWNyZXQ=" {
+		t.Errorf("expected proxy credentials, got %q", auth)
+	}
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	if err := ProxyConnect(closed.URL, "allowed.example:443")(context.Background()); err == nil {
+		t.Error("expected an unreachable proxy to fail")
+	}
+}
@@ -0,0 +1,47 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+// PubSubResource is a Pub/Sub topic or subscription. *pubsub.Topic and
+// *pubsub.Subscription from cloud.google.com/go/pubsub satisfy it.
+type PubSubResource interface {
+	ID() string
+	Exists(ctx context.Context) (bool, error)
+}
+
+// PubSub returns a check that topic and subscriptions exist, which also
+// proves the publisher can reach Pub/Sub. All calls share a deadline of
+// timeout (DefaultTimeout if zero), as the client retries unreachable
+// endpoints for a long time. The outcome for the topic and every
+// subscription is reported as a detail named after its ID: "ok", "missing"
+// or the error.
+func PubSub(timeout time.Duration, topic PubSubResource, subscriptions ...PubSubResource) health.CheckFunc {
+	timeout = timeoutOr(timeout)
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		var errs []error
+		for _, resource := range append([]PubSubResource{topic}, subscriptions...) {
+			exists, err := resource.Exists(ctx)
+			switch {
+			case err != nil:
+				health.SetDetail(ctx, resource.ID(), err.Error())
+				errs = append(errs, health.DependencyError(fmt.Errorf("%s: %w", resource.ID(), err)))
+			case !exists:
+				health.SetDetail(ctx, resource.ID(), "missing")
+				errs = append(errs, health.ConfigurationError(fmt.Errorf("%s does not exist", resource.ID())))
+			default:
+				health.SetDetail(ctx, resource.ID(), "ok")
+			}
+		}
+
+		return health.JoinErrors(errs...)
+	}
+}
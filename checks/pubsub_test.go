@@ -0,0 +1,67 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+type fakePubSub struct {
+	id     string
+	exists bool
+	err    error
+}
+
+func (f fakePubSub) ID() string { return f.id }
+
+func (f fakePubSub) Exists(ctx context.Context) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return f.exists, f.err
+}
+
+func TestPubSub(t *testing.T) {
+	defer health.Reset()
+
+	topic := fakePubSub{id: "orders", exists: true}
+	h := health.Handle()
+	h.Register("ok", PubSub(time.Second, topic, fakePubSub{id: "billing", exists: true}))
+	h.Register("default-timeout", PubSub(0, topic))
+	h.Register("missing", PubSub(time.Second, topic,
+		fakePubSub{id: "billing", exists: true},
+		fakePubSub{id: "shipping"}))
+	h.Register("unreachable", PubSub(time.Second, fakePubSub{id: "orders", err: errors.New("connection refused")}))
+	h.Register("mixed", PubSub(time.Second,
+		fakePubSub{id: "orders", err: health.OverloadError(errors.New("quota exceeded"))},
+		fakePubSub{id: "shipping"}))
+	h.Evaluate(context.Background())
+
+	results := map[string]health.CheckResult{}
+	for _, result := range h.Results() {
+		results[result.Name] = result
+	}
+
+	if r := results["ok"]; r.Status != health.Up || r.Details["billing"] != "ok" {
+		t.Errorf("expected existing resources to pass, got %+v", r)
+	}
+	if r := results["default-timeout"]; r.Status != health.Up {
+		t.Errorf("expected a zero timeout to use the default, got %+v", r)
+	}
+	missing := results["missing"]
+	if missing.Status != health.Down || missing.Category != health.CategoryConfiguration {
+		t.Errorf("expected a missing subscription to be a configuration error, got %+v", missing)
+	}
+	if missing.Details["billing"] != "ok" || missing.Details["shipping"] != "missing" {
+		t.Errorf("expected per-subscription details, got %v", missing.Details)
+	}
+	if r := results["unreachable"]; r.Category != health.CategoryDependency {
+		t.Errorf("expected an unreachable topic to be a dependency error, got %+v", r)
+	}
+	if r := results["mixed"]; r.Status != health.Down {
+		t.Errorf("an overloaded topic hid a missing subscription, got %+v", r)
+	}
+}
@@ -0,0 +1,61 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/andres-vara/health"
+)
+
+// Endpoint is one instance of a redundant dependency.
+type Endpoint struct {
+	Name  string
+	Check health.CheckFunc
+}
+
+// Quorum returns a check probing every endpoint concurrently and passing
+// when at least n of them are healthy, like three Redis sentinels of which
+// two must answer. Each endpoint's outcome is reported as a detail of the
+// check, "UP" or the endpoint's error.
+func Quorum(n int, endpoints ...Endpoint) health.CheckFunc {
+	return func(ctx context.Context) error {
+		checks := make([]health.CheckFunc, len(endpoints))
+		for i, endpoint := range endpoints {
+			checks[i] = endpoint.Check
+		}
+		errs := probe(ctx, checks)
+
+		healthy := 0
+		for i, err := range errs {
+			if err == nil {
+				healthy++
+				health.SetDetail(ctx, endpoints[i].Name, string(health.Up))
+			} else {
+				health.SetDetail(ctx, endpoints[i].Name, err.Error())
+			}
+		}
+
+		if healthy < n {
+			return health.DependencyError(fmt.Errorf("%d of %d endpoints healthy, quorum is %d", healthy, len(endpoints), n))
+		}
+		return nil
+	}
+}
+
+// probe runs checks concurrently and returns their errors, in order.
+func probe(ctx context.Context, checks []health.CheckFunc) []error {
+	errs := make([]error, len(checks))
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = check(ctx)
+		}()
+	}
+	wg.Wait()
+
+	return errs
+}
@@ -0,0 +1,48 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/andres-vara/health"
+)
+
+func TestQuorum(t *testing.T) {
+	up := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+
+	h := health.Handle()
+	health.Reset()
+	defer health.Reset()
+
+	h.Register("sentinels", Quorum(2,
+		Endpoint{"sentinel-1", up},
+		Endpoint{"sentinel-2", down},
+		Endpoint{"sentinel-3", up},
+	))
+	h.Register("replicas", Quorum(2,
+		Endpoint{"replica-1", up},
+		Endpoint{"replica-2", down},
+	))
+
+	// Results are sorted by name: replicas, then sentinels
+	results := h.Evaluate(context.Background())
+
+	if results[1].Status != health.Up {
+		t.Errorf("expected the quorum to be met, got %v %q", results[1].Status, results[1].Reason)
+	}
+	want := map[string]any{"sentinel-1": "UP", "sentinel-2": "connection refused", "sentinel-3": "UP"}
+	for name, outcome := range want {
+		if results[1].Details[name] != outcome {
+			t.Errorf("detail %s: got %v want %v", name, results[1].Details[name], outcome)
+		}
+	}
+
+	if results[0].Status != health.Down || results[0].Reason != "1 of 2 endpoints healthy, quorum is 2" {
+		t.Errorf("expected the quorum to be missed, got %v %q", results[0].Status, results[0].Reason)
+	}
+	if results[0].Category != health.CategoryDependency {
+		t.Errorf("unexpected category: %q", results[0].Category)
+	}
+}
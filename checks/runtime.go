@@ -0,0 +1,63 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/metrics"
+
+	"github.com/andres-vara/health"
+)
+
+// runtimeWarnShare is the share of their limit at which HeapLimit and
+// GoroutineLimit are degraded.
+const runtimeWarnShare = 0.9
+
+// heapMetric is the memory occupied by live and not yet swept heap objects,
+// HeapAlloc in runtime.MemStats, read without stopping the world.
+const heapMetric = "/memory/classes/heap/objects:bytes"
+
+// HeapLimit returns a check on the heap size, so a leaking process takes
+// itself out of rotation before the OOM killer gets it. It fails once the
+// heap reaches maxBytes and is degraded from 90% of it. The heap size is
+// reported as a detail.
+func HeapLimit(maxBytes uint64) health.CheckFunc {
+	return func(ctx context.Context) error {
+		sample := []metrics.Sample{{Name: heapMetric}}
+		metrics.Read(sample)
+		if sample[0].Value.Kind() != metrics.KindUint64 {
+			return health.InternalError(fmt.Errorf("runtime metric %s is unsupported", heapMetric))
+		}
+
+		heap := sample[0].Value.Uint64()
+		health.SetDetail(ctx, "heap_bytes", heap)
+		health.SetDetail(ctx, "heap_limit_bytes", maxBytes)
+
+		switch {
+		case heap >= maxBytes:
+			return health.ResourceExhaustionError(fmt.Errorf("heap at %d bytes, failing at %d", heap, maxBytes))
+		case float64(heap) >= runtimeWarnShare*float64(maxBytes):
+			return health.DegradedError(fmt.Errorf("heap at %d bytes, %.1f%% of the limit", heap, 100*float64(heap)/float64(maxBytes)))
+		}
+		return nil
+	}
+}
+
+// GoroutineLimit returns a check on the number of goroutines, which grows
+// without bound when goroutines leak. It fails once there are max of them
+// and is degraded from 90% of it. The number is reported as a detail.
+func GoroutineLimit(max int) health.CheckFunc {
+	return func(ctx context.Context) error {
+		n := runtime.NumGoroutine()
+		health.SetDetail(ctx, "goroutines", n)
+		health.SetDetail(ctx, "goroutine_limit", max)
+
+		switch {
+		case n >= max:
+			return health.ResourceExhaustionError(fmt.Errorf("%d goroutines, failing at %d", n, max))
+		case float64(n) >= runtimeWarnShare*float64(max):
+			return health.DegradedError(fmt.Errorf("%d goroutines, %.1f%% of the limit", n, 100*float64(n)/float64(max)))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,60 @@
+package checks
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/andres-vara/health"
+)
+
+func TestHeapLimit(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		limit  uint64
+		status health.Status
+	}{
+		{"far below", 1 << 50, health.Up},
+		{"above", 1, health.Down},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			r := health.New().Register("heap", HeapLimit(test.limit)).Evaluate(context.Background())[0]
+			if r.Status != test.status {
+				t.Fatalf("expected %v, got %v (%s)", test.status, r.Status, r.Reason)
+			}
+			if heap, _ := r.Details["heap_bytes"].(uint64); heap == 0 {
+				t.Errorf("expected the heap size in the details, got %v", r.Details)
+			}
+		})
+	}
+}
+
+func TestGoroutineLimit(t *testing.T) {
+	stop := make(chan struct{})
+	defer close(stop)
+	for range 200 {
+		go func() { <-stop }()
+	}
+	n := runtime.NumGoroutine()
+
+	for _, test := range []struct {
+		name   string
+		limit  int
+		status health.Status
+	}{
+		{"far below", n * 100, health.Up},
+		// The evaluation's own goroutines count too
+		{"near", n + 10, health.Degraded},
+		{"above", n / 2, health.Down},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			r := health.New().Register("goroutines", GoroutineLimit(test.limit)).Evaluate(context.Background())[0]
+			if r.Status != test.status {
+				t.Fatalf("expected %v, got %v (%s)", test.status, r.Status, r.Reason)
+			}
+			if r.Details["goroutines"] == nil {
+				t.Errorf("expected the goroutine count in the details, got %v", r.Details)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+// schedulerWindow is how many recent samples the scheduler latency check
+// looks at.
+const schedulerWindow = 5
+
+// SchedulerLatency returns a check on how late the Go scheduler wakes up
+// goroutines. Until ctx is done, a goroutine sleeps for period over and
+// over and measures how late it wakes up. When even the best of the last
+// few samples is at least threshold late, the delay is sustained rather
+// than a blip, a sign of GC thrash or CPU throttling that user latency
+// alarms only catch later, and the check is degraded.
+// The latest latency is reported as the "wakeup_latency" detail.
+func SchedulerLatency(ctx context.Context, period, threshold time.Duration) health.CheckFunc {
+	s := &schedulerSampler{}
+	go s.run(ctx, period)
+
+	return func(ctx context.Context) error {
+		samples := s.recent()
+		if len(samples) == 0 {
+			return nil
+		}
+		health.SetDetail(ctx, "wakeup_latency", samples[len(samples)-1].String())
+
+		if best := slices.Min(samples); len(samples) == schedulerWindow && best >= threshold {
+			return health.DegradedError(fmt.Errorf("goroutines wake up at least %v late", best.Round(time.Millisecond)))
+		}
+		return nil
+	}
+}
+
+// schedulerSampler keeps the latest wake-up latencies.
+type schedulerSampler struct {
+	mutex   sync.Mutex
+	samples []time.Duration
+}
+
+func (s *schedulerSampler) run(ctx context.Context, period time.Duration) {
+	timer := time.NewTimer(period)
+	defer timer.Stop()
+
+	for {
+		expected := time.Now().Add(period)
+		timer.Reset(period)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			s.add(max(time.Since(expected), 0))
+		}
+	}
+}
+
+func (s *schedulerSampler) add(latency time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.samples = append(s.samples, latency)
+	if len(s.samples) > schedulerWindow {
+		s.samples = s.samples[1:]
+	}
+}
+
+func (s *schedulerSampler) recent() []time.Duration {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return slices.Clone(s.samples)
+}
@@ -0,0 +1,48 @@
+package checks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+func TestSchedulerLatency(t *testing.T) {
+	defer health.Reset()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	h := health.Handle()
+	h.Register("scheduler", SchedulerLatency(ctx, time.Millisecond, time.Hour))
+
+	// Wait for samples to come in
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		h.Evaluate(context.Background())
+		result := h.Results()[0]
+		if result.Status != health.Up {
+			t.Fatalf("expected a responsive scheduler to pass, got %+v", result)
+		}
+		if result.Details["wakeup_latency"] != nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("no latency sample")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSchedulerSamplerWindow(t *testing.T) {
+	s := &schedulerSampler{}
+	for i := range schedulerWindow + 2 {
+		s.add(time.Duration(i) * time.Second)
+	}
+
+	samples := s.recent()
+	if len(samples) != schedulerWindow || samples[0] != 2*time.Second {
+		t.Errorf("expected the last %d samples, got %v", schedulerWindow, samples)
+	}
+}
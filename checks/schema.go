@@ -0,0 +1,56 @@
+package checks
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/andres-vara/health"
+)
+
+// DefaultSchemaVersionQuery reads the version from golang-migrate's
+// migration table, and from any table with a version column.
+const DefaultSchemaVersionQuery = "SELECT MAX(version) FROM schema_migrations"
+
+// RowQuerier runs a query returning a single row, like *sql.DB, *sql.Conn
+// and *sql.Tx.
+type RowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// SchemaOption configures a schema version check.
+type SchemaOption func(*string)
+
+// WithVersionQuery sets the query reading the schema version, for migration
+// tools other than golang-migrate.
+func WithVersionQuery(query string) SchemaOption {
+	return func(q *string) {
+		*q = query
+	}
+}
+
+// SchemaVersion returns a check failing while the database schema is older
+// than expected, the version of the latest migration the binary needs, as
+// happens when a rolling deploy starts the new binary before migrating.
+// Newer schemas pass, so the previous binary keeps running after a
+// migration. The version is read with DefaultSchemaVersionQuery unless
+// configured otherwise, and reported as the "schema_version" detail.
+func SchemaVersion(db RowQuerier, expected int64, opts ...SchemaOption) health.CheckFunc {
+	query := DefaultSchemaVersionQuery
+	for _, opt := range opts {
+		opt(&query)
+	}
+
+	return func(ctx context.Context) error {
+		var version sql.NullInt64
+		if err := db.QueryRowContext(ctx, query).Scan(&version); err != nil {
+			return health.DependencyError(fmt.Errorf("reading schema version: %w", err))
+		}
+		health.SetDetail(ctx, "schema_version", version.Int64)
+
+		if !version.Valid || version.Int64 < expected {
+			return health.ConfigurationError(fmt.Errorf("schema at version %d, expected at least %d", version.Int64, expected))
+		}
+		return nil
+	}
+}
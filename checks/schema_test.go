@@ -0,0 +1,116 @@
+package checks
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/andres-vara/health"
+)
+
+// versionDriver is a database driver answering every query with a single
+// version row, or an error.
+type versionDriver struct {
+	mutex   sync.Mutex
+	version driver.Value
+	err     error
+	query   string
+}
+
+func (d *versionDriver) Open(name string) (driver.Conn, error) {
+	return versionConn{d}, nil
+}
+
+type versionConn struct {
+	d *versionDriver
+}
+
+func (c versionConn) Prepare(query string) (driver.Stmt, error) {
+	c.d.mutex.Lock()
+	defer c.d.mutex.Unlock()
+
+	c.d.query = query
+	return versionStmt(c), nil
+}
+
+func (c versionConn) Close() error              { return nil }
+func (c versionConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type versionStmt versionConn
+
+func (s versionStmt) Close() error  { return nil }
+func (s versionStmt) NumInput() int { return -1 }
+func (s versionStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+
+func (s versionStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.mutex.Lock()
+	defer s.d.mutex.Unlock()
+
+	if s.d.err != nil {
+		return nil, s.d.err
+	}
+	return &versionRows{value: s.d.version}, nil
+}
+
+type versionRows struct {
+	value driver.Value
+	done  bool
+}
+
+func (r *versionRows) Columns() []string { return []string{"version"} }
+func (r *versionRows) Close() error      { return nil }
+
+func (r *versionRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.value
+	return nil
+}
+
+func TestSchemaVersion(t *testing.T) {
+	d := &versionDriver{}
+	sql.Register("test-schema-version", d)
+	db, err := sql.Open("test-schema-version", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	tests := []struct {
+		version  driver.Value
+		err      error
+		category health.Category
+	}{
+		{version: int64(42)},
+		{version: int64(43)},
+		{version: int64(41), category: health.CategoryConfiguration},
+		{version: nil, category: health.CategoryConfiguration},
+		{err: errors.New("relation does not exist"), category: health.CategoryDependency},
+	}
+
+	for _, tt := range tests {
+		d.version, d.err = tt.version, tt.err
+		err := SchemaVersion(db, 42)(context.Background())
+		if category := health.CategoryOf(err); category != tt.category {
+			t.Errorf("version %v, error %v: got %v", tt.version, tt.err, err)
+		}
+	}
+
+	if !strings.Contains(d.query, "schema_migrations") {
+		t.Errorf("unexpected default query: %q", d.query)
+	}
+
+	SchemaVersion(db, 1, WithVersionQuery("SELECT version_id FROM goose_db_version ORDER BY id DESC LIMIT 1"))(context.Background())
+	if !strings.HasPrefix(d.query, "SELECT version_id") {
+		t.Errorf("expected the custom query, got %q", d.query)
+	}
+}
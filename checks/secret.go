@@ -0,0 +1,108 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+// Secret tracks the rotation of a mounted secret or config file. Its Check
+// is degraded when the file is older than its rotation policy allows, or
+// when the application reported that reloading it after a rotation failed:
+//
+//	secret := checks.NewSecret("/etc/secrets/db", 30*24*time.Hour)
+//	health.Register("db-secret", secret.Check)
+//	...
+//	secret.Reloaded(reconnect())
+type Secret struct {
+	path   string
+	maxAge time.Duration
+
+	mutex     sync.Mutex
+	version   func() (string, error)
+	seen      string
+	rotated   time.Time
+	reloadErr error
+}
+
+// NewSecret tracks the file at path, which should be rotated at least every
+// maxAge. Rotations are told by the file's modification time, which
+// Kubernetes updates on every secret or config map change.
+func NewSecret(path string, maxAge time.Duration) *Secret {
+	return &Secret{path: path, maxAge: maxAge}
+}
+
+// WithVersion tells rotations by the version read returns changing instead
+// of by modification time, for secrets carrying a version annotation. A
+// rotation then dates from the first check seeing the new version.
+func (s *Secret) WithVersion(read func() (string, error)) *Secret {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.version = read
+	return s
+}
+
+// Reloaded records the outcome of the application reloading the secret
+// after a rotation. A failure is reported until a reload succeeds.
+func (s *Secret) Reloaded(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.reloadErr = err
+}
+
+// Check is the health check of the secret. It fails when the file can't be
+// read, and reports when it was last rotated as the "rotated_at" detail.
+func (s *Secret) Check(ctx context.Context) error {
+	rotated, err := s.rotatedAt()
+	if err != nil {
+		return health.ConfigurationError(err)
+	}
+	health.SetDetail(ctx, "rotated_at", rotated.UTC().Format(time.RFC3339))
+
+	s.mutex.Lock()
+	reloadErr := s.reloadErr
+	s.mutex.Unlock()
+
+	var warnings []string
+	if age := now().Sub(rotated); s.maxAge > 0 && age > s.maxAge {
+		warnings = append(warnings, fmt.Sprintf("%s not rotated for %v, policy is %v", s.path, age.Round(time.Minute), s.maxAge))
+	}
+	if reloadErr != nil {
+		warnings = append(warnings, fmt.Sprintf("reloading %s failed: %v", s.path, reloadErr))
+	}
+	if len(warnings) > 0 {
+		return health.DegradedError(errors.New(strings.Join(warnings, "; ")))
+	}
+	return nil
+}
+
+// rotatedAt returns when the secret was last rotated.
+func (s *Secret) rotatedAt() (time.Time, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.version == nil {
+		info, err := os.Stat(s.path)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return info.ModTime(), nil
+	}
+
+	version, err := s.version()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("reading version of %s: %w", s.path, err)
+	}
+	if s.rotated.IsZero() || version != s.seen {
+		s.seen, s.rotated = version, now()
+	}
+	return s.rotated, nil
+}
@@ -0,0 +1,83 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+func TestSecret(t *testing.T) {
+	defer health.Reset()
+	defer func(f func() time.Time) { now = f }(now)
+	clock := time.Now()
+	now = func() time.Time { return clock }
+
+	path := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(path, []byte("hunter2"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, clock, clock.Add(-40*24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	version := "1"
+	stale := NewSecret(path, 30*24*time.Hour)
+	versioned := NewSecret(path, 30*24*time.Hour).WithVersion(func() (string, error) { return version, nil })
+	missing := NewSecret(path+".missing", time.Hour)
+
+	h := health.Handle()
+	h.Register("stale", stale.Check)
+	h.Register("versioned", versioned.Check)
+	h.Register("missing", missing.Check)
+
+	degraded := func(name string) bool {
+		t.Helper()
+		h.Evaluate(context.Background())
+		for _, result := range h.Results() {
+			if result.Name == name {
+				if result.Status != health.Up && result.Status != health.Degraded {
+					t.Errorf("%s: expected UP or DEGRADED, got %s (%s)", name, result.Status, result.Reason)
+				}
+				return result.Status == health.Degraded
+			}
+		}
+		return false
+	}
+
+	if !degraded("stale") {
+		t.Error("expected a file older than the policy to degrade the check")
+	}
+	if degraded("versioned") {
+		t.Error("expected a newly seen version to count as fresh")
+	}
+
+	clock = clock.Add(31 * 24 * time.Hour)
+	if !degraded("versioned") {
+		t.Error("expected an unchanged version to age")
+	}
+	version = "2"
+	if degraded("versioned") {
+		t.Error("expected a new version to count as a rotation")
+	}
+
+	versioned.Reloaded(errors.New("bad password"))
+	if !degraded("versioned") {
+		t.Error("expected a failed reload to degrade the check")
+	}
+	versioned.Reloaded(nil)
+	if degraded("versioned") {
+		t.Error("expected a successful reload to clear the degradation")
+	}
+
+	h.Evaluate(context.Background())
+	for _, result := range h.Results() {
+		if result.Name == "missing" && result.Status != health.Down {
+			t.Errorf("expected a missing file to fail, got %+v", result)
+		}
+	}
+}
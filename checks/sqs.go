@@ -0,0 +1,67 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+
+	"github.com/andres-vara/health"
+)
+
+// sqsBacklogAttribute is the SQS attribute counting messages waiting in a
+// queue.
+const sqsBacklogAttribute = "ApproximateNumberOfMessages"
+
+// SQSQueues reads SQS queue attributes. It is implemented by wrapping the
+// AWS SDK client, so this package doesn't depend on it:
+//
+//	type queues struct{ *sqs.Client }
+//
+//	func (q queues) GetQueueAttributes(ctx context.Context, url string, names ...string) (map[string]string, error) {
+//		attributes := make([]types.QueueAttributeName, len(names))
+//		for i, name := range names {
+//			attributes[i] = types.QueueAttributeName(name)
+//		}
+//		out, err := q.Client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{QueueUrl: &url, AttributeNames: attributes})
+//		if err != nil {
+//			return nil, err
+//		}
+//		return out.Attributes, nil
+//	}
+type SQSQueues interface {
+	GetQueueAttributes(ctx context.Context, url string, names ...string) (map[string]string, error)
+}
+
+// SQS returns a check that the queues at urls are reachable. Errors are
+// classified like DynamoDB's. The backlog of every queue is reported as a
+// detail named after the queue, and with a positive backlog the check is
+// degraded once a queue has that many messages waiting, a sign its consumers don't keep up.
+func SQS(queues SQSQueues, backlog int64, urls ...string) health.CheckFunc {
+	return func(ctx context.Context) error {
+		var warned error
+		for _, url := range urls {
+			name := path.Base(url)
+
+			attributes, err := queues.GetQueueAttributes(ctx, url, sqsBacklogAttribute)
+			if err != nil {
+				return awsError(ctx, name, err)
+			}
+
+			messages, err := strconv.ParseInt(attributes[sqsBacklogAttribute], 10, 64)
+			if err != nil {
+				continue
+			}
+			health.SetDetail(ctx, name, messages)
+
+			if backlog > 0 && messages >= backlog && warned == nil {
+				warned = fmt.Errorf("%s has %d messages waiting, degraded at %d", name, messages, backlog)
+			}
+		}
+
+		if warned != nil {
+			return health.DegradedError(warned)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,54 @@
+package checks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/andres-vara/health"
+)
+
+type fakeQueues map[string]string
+
+func (f fakeQueues) GetQueueAttributes(ctx context.Context, url string, names ...string) (map[string]string, error) {
+	messages, ok := f[url]
+	if !ok {
+		return nil, apiError{"AWS.SimpleQueueService.NonExistentQueue"}
+	}
+	return map[string]string{sqsBacklogAttribute: messages}, nil
+}
+
+func TestSQS(t *testing.T) {
+	defer health.Reset()
+
+	queues := fakeQueues{
+		"https://sqs.eu-west-1.amazonaws.com/123/orders":   "12",
+		"https://sqs.eu-west-1.amazonaws.com/123/invoices": "5000",
+	}
+
+	h := health.Handle()
+	h.Register("quiet", SQS(queues, 0, "https://sqs.eu-west-1.amazonaws.com/123/invoices"))
+	h.Register("backlog", SQS(queues, 1000,
+		"https://sqs.eu-west-1.amazonaws.com/123/orders",
+		"https://sqs.eu-west-1.amazonaws.com/123/invoices"))
+	h.Register("missing", SQS(queues, 0, "https://sqs.eu-west-1.amazonaws.com/123/refunds"))
+	h.Evaluate(context.Background())
+
+	results := map[string]health.CheckResult{}
+	for _, result := range h.Results() {
+		results[result.Name] = result
+	}
+
+	if r := results["quiet"]; r.Status != health.Up {
+		t.Errorf("expected no degradation without a backlog threshold, got %+v", r)
+	}
+	backlog := results["backlog"]
+	if backlog.Status != health.Degraded {
+		t.Errorf("expected the backlog to degrade the check, got %+v", backlog)
+	}
+	if backlog.Details["orders"] != int64(12) {
+		t.Errorf("expected the orders backlog detail, got %v", backlog.Details)
+	}
+	if r := results["missing"]; r.Category != health.CategoryConfiguration || r.Details["error_class"] != "not_found" {
+		t.Errorf("expected a missing queue to be a configuration error, got %+v", r)
+	}
+}
@@ -0,0 +1,67 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/andres-vara/health"
+)
+
+// lookupSRV resolves SRV records; replaced in tests
+var lookupSRV = net.DefaultResolver.LookupSRV
+
+// isSRVName reports whether host is an SRV name like
+// _redis._tcp.cache.internal rather than a plain host.
+func isSRVName(host string) bool {
+	return strings.HasPrefix(host, "_")
+}
+
+// SRV returns a check resolving the SRV name on every evaluation, so it
+// follows service discovery instead of hard-coded hosts, and running the
+// check built by target against each resolved "host:port" in SRV order
+// (priority, then weight) until one passes:
+//
+//	checks.SRV("_api._tcp.orders.internal", func(target string) health.CheckFunc {
+//		return checks.HTTP("http://" + target + "/health")
+//	})
+//
+// When none passes, the check is only DEGRADED or OVERLOADED if every target
+// is.
+func SRV(name string, target func(hostport string) health.CheckFunc) health.CheckFunc {
+	return func(ctx context.Context) error {
+		hostports, err := resolveSRV(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		var errs []error
+		for _, hostport := range hostports {
+			err := target(hostport)(ctx)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", hostport, err))
+		}
+		return health.JoinErrors(errs...)
+	}
+}
+
+// resolveSRV returns the targets of an SRV name as "host:port", in SRV order.
+func resolveSRV(ctx context.Context, name string) ([]string, error) {
+	_, records, err := lookupSRV(ctx, "", "", name)
+	if err != nil {
+		return nil, health.DependencyError(fmt.Errorf("resolving %s: %w", name, err))
+	}
+	if len(records) == 0 {
+		return nil, health.DependencyError(fmt.Errorf("resolving %s: no targets", name))
+	}
+
+	hostports := make([]string, len(records))
+	for i, record := range records {
+		hostports[i] = net.JoinHostPort(strings.TrimSuffix(record.Target, "."), strconv.Itoa(int(record.Port)))
+	}
+	return hostports, nil
+}
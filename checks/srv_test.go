@@ -0,0 +1,101 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/andres-vara/health"
+)
+
+// fakeSRV makes SRV lookups of name resolve to addrs, in order.
+func fakeSRV(t *testing.T, name string, addrs ...string) {
+	t.Helper()
+
+	previous := lookupSRV
+	t.Cleanup(func() { lookupSRV = previous })
+
+	lookupSRV = func(ctx context.Context, service, proto, lookup string) (string, []*net.SRV, error) {
+		if lookup != name {
+			return "", nil, errors.New("no such host")
+		}
+		var records []*net.SRV
+		for _, addr := range addrs {
+			host, port, _ := net.SplitHostPort(addr)
+			p, _ := strconv.Atoi(port)
+			records = append(records, &net.SRV{Target: host + ".", Port: uint16(p)})
+		}
+		return name, records, nil
+	}
+}
+
+func TestTCPWithSRV(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dead := closed.Addr().String()
+	closed.Close()
+
+	fakeSRV(t, "_redis._tcp.cache.internal", dead, listener.Addr().String())
+	if err := TCP("_redis._tcp.cache.internal")(context.Background()); err != nil {
+		t.Errorf("expected the second target to pass, got %v", err)
+	}
+
+	fakeSRV(t, "_redis._tcp.cache.internal", dead)
+	err = TCP("_redis._tcp.cache.internal")(context.Background())
+	if err == nil || !strings.Contains(err.Error(), dead) {
+		t.Errorf("expected the dead target to be reported, got %v", err)
+	}
+
+	err = TCP("_missing._tcp.cache.internal")(context.Background())
+	if health.CategoryOf(err) != health.CategoryDependency {
+		t.Errorf("expected a dependency error for a failed lookup, got %v", err)
+	}
+}
+
+func TestHTTPWithSRV(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	fakeSRV(t, "_api._tcp.orders.internal", server.Listener.Addr().String())
+	if err := HTTP("http://_api._tcp.orders.internal/health")(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := HTTP("http://_api._tcp.orders.internal/missing")(context.Background()); err == nil {
+		t.Error("expected the options to apply to the resolved targets")
+	}
+}
+
+func TestSRVMixedFailures(t *testing.T) {
+	fakeSRV(t, "_api._tcp.orders.internal", "10.0.0.1:80", "10.0.0.2:80")
+	target := func(hostport string) health.CheckFunc {
+		return func(ctx context.Context) error {
+			if hostport == "10.0.0.1:80" {
+				return health.OverloadError(errors.New("shedding load"))
+			}
+			return errors.New("connection refused")
+		}
+	}
+
+	h := health.New()
+	h.Register("api", SRV("_api._tcp.orders.internal", target))
+	if r := h.Evaluate(context.Background())[0]; r.Status != health.Down {
+		t.Errorf("an overloaded target hid one that is down: %+v", r)
+	}
+}
@@ -0,0 +1,27 @@
+package checks
+
+import (
+	"context"
+	"net"
+
+	"github.com/andres-vara/health"
+)
+
+// TCP returns a check passing when a TCP connection to addr can be opened.
+// The address is either "host:port" or an SRV name such as
+// _redis._tcp.cache.internal, resolved on every evaluation; the check then
+// passes when any of its targets accepts connections.
+func TCP(addr string) health.CheckFunc {
+	if isSRVName(addr) {
+		return SRV(addr, TCP)
+	}
+
+	return func(ctx context.Context) error {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return health.DependencyError(err)
+		}
+		return conn.Close()
+	}
+}
@@ -0,0 +1,37 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/andres-vara/health"
+)
+
+// Threshold returns a check sampling gauge on every evaluation and comparing
+// it against thresholds, for queue depths, lag, pool utilization and the
+// like. It fails when the value reaches fail, and is degraded once it
+// reaches warn. Higher values are worse, unless fail is
+// below warn, as for free connections in a pool. The value is reported as
+// a detail named after the gauge.
+func Threshold(name string, gauge health.Gauge, warn, fail float64) health.CheckFunc {
+	// reached reports whether v is at or beyond the limit
+	reached := func(v, limit float64) bool {
+		if fail < warn {
+			return v <= limit
+		}
+		return v >= limit
+	}
+
+	return func(ctx context.Context) error {
+		value := gauge()
+		health.SetDetail(ctx, name, value)
+
+		switch {
+		case reached(value, fail):
+			return health.ResourceExhaustionError(fmt.Errorf("%s is %g, failing at %g", name, value, fail))
+		case reached(value, warn):
+			return health.DegradedError(fmt.Errorf("%s is %g, degraded at %g", name, value, warn))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,40 @@
+package checks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/andres-vara/health"
+)
+
+func TestThreshold(t *testing.T) {
+	h := health.Handle()
+	health.Reset()
+	defer health.Reset()
+
+	depth, free := 0.0, 0.0
+	h.Register("queue", Threshold("queue_depth", func() float64 { return depth }, 100, 1000))
+	h.Register("pool", Threshold("free_connections", func() float64 { return free }, 10, 2))
+
+	tests := []struct {
+		depth, free float64
+		queue, pool health.Status
+	}{
+		{depth: 10, free: 50, queue: health.Up, pool: health.Up},
+		{depth: 100, free: 10, queue: health.Degraded, pool: health.Degraded},
+		{depth: 1000, free: 2, queue: health.Down, pool: health.Down},
+	}
+
+	for _, tt := range tests {
+		depth, free = tt.depth, tt.free
+		results := h.Evaluate(context.Background())
+		pool, queue := results[0], results[1]
+
+		if queue.Status != tt.queue || pool.Status != tt.pool {
+			t.Errorf("depth %g, free %g: got queue %v pool %v", tt.depth, tt.free, queue.Status, pool.Status)
+		}
+		if queue.Details["queue_depth"] != tt.depth {
+			t.Errorf("expected the value as a detail, got %v", queue.Details)
+		}
+	}
+}
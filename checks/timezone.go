@@ -0,0 +1,30 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+// TimeZones returns a check that every named zone can be loaded with
+// time.LoadLocation. Images built from scratch or distroless lack the zone
+// database, which breaks at the first conversion instead of at startup;
+// importing time/tzdata in the binary fixes it.
+func TimeZones(names ...string) health.CheckFunc {
+	return func(ctx context.Context) error {
+		var missing []string
+		for _, name := range names {
+			if _, err := time.LoadLocation(name); err != nil {
+				missing = append(missing, name)
+			}
+		}
+
+		if len(missing) > 0 {
+			return health.ConfigurationError(fmt.Errorf("time zones not found: %s (import time/tzdata to embed them)", strings.Join(missing, ", ")))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,19 @@
+package checks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/andres-vara/health"
+)
+
+func TestTimeZones(t *testing.T) {
+	if err := TimeZones("UTC", "Local")(context.Background()); err != nil {
+		t.Errorf("expected built-in zones to load, got %v", err)
+	}
+
+	err := TimeZones("UTC", "Nowhere/Atlantis")(context.Background())
+	if health.CategoryOf(err) != health.CategoryConfiguration {
+		t.Errorf("expected a configuration error for a missing zone, got %v", err)
+	}
+}
@@ -0,0 +1,207 @@
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/andres-vara/health"
+)
+
+// VersionSource returns the version a dependency reports.
+type VersionSource func(ctx context.Context) (string, error)
+
+// HTTPVersion is the source of the version in field, a dot-separated path
+// like ExpectJSONField's, of the JSON document served at url, such as
+// RabbitMQ's /api/overview ("rabbitmq_version") or Elasticsearch's root
+// ("version.number"). opts configure the request.
+func HTTPVersion(url, field string, opts ...HTTPOption) VersionSource {
+	c := newHTTPCheck(url, opts)
+
+	return func(ctx context.Context) (string, error) {
+		body, err := c.fetch(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		var document any
+		if err := json.Unmarshal(body, &document); err != nil {
+			return "", health.DependencyError(fmt.Errorf("body is not JSON: %w", err))
+		}
+		value, ok := lookupJSON(document, field)
+		if !ok {
+			return "", health.DependencyError(fmt.Errorf("body has no field %q", field))
+		}
+		return fmt.Sprint(value), nil
+	}
+}
+
+// SupportedVersion returns a check failing when the version of a dependency
+// falls outside constraint, surfacing "the broker was upgraded over the
+// weekend" in readiness instead of in odd errors. The version is reported as
+// the "version" detail.
+//
+// A constraint is a space or comma separated list of comparisons that must
+// all hold, such as ">=3.8 <4", and alternatives may be joined with "||".
+// Comparisons use =, !=, >, >=, < and <=, plus ^ (same major version) and ~
+// (same minor version). Versions are compared on major, minor and patch,
+// with missing parts as zero and an optional "v" prefix; pre-release and
+// build suffixes are ignored, as servers use them for distribution tags.
+func SupportedVersion(source VersionSource, constraint string) health.CheckFunc {
+	ranges, err := parseConstraint(constraint)
+	if err != nil {
+		return func(ctx context.Context) error {
+			return health.ConfigurationError(err)
+		}
+	}
+
+	return func(ctx context.Context) error {
+		raw, err := source(ctx)
+		if err != nil {
+			return err
+		}
+		health.SetDetail(ctx, "version", raw)
+
+		version, err := parseVersion(raw)
+		if err != nil {
+			return health.DependencyError(err)
+		}
+		for _, r := range ranges {
+			if r.contains(version) {
+				return nil
+			}
+		}
+		return health.DependencyError(fmt.Errorf("version %s outside the supported range %s", raw, constraint))
+	}
+}
+
+// semver is a version's major, minor and patch numbers.
+type semver [3]int
+
+func (v semver) compare(other semver) int {
+	for i := range v {
+		if v[i] != other[i] {
+			if v[i] < other[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// parseVersion parses a version.
+func parseVersion(s string) (semver, error) {
+	v, _, err := parseVersionParts(s)
+	return v, err
+}
+
+// parseVersionParts parses a version, also returning how many of its parts
+// were given.
+func parseVersionParts(s string) (semver, int, error) {
+	core := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexAny(core, "-+ "); i >= 0 {
+		core = core[:i]
+	}
+
+	var v semver
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 {
+		parts = parts[:3]
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return semver{}, 0, fmt.Errorf("invalid version %q", s)
+		}
+		v[i] = n
+	}
+	return v, len(parts), nil
+}
+
+// comparison is one comparison of a constraint.
+type comparison struct {
+	op      string
+	version semver
+}
+
+func (c comparison) holds(v semver) bool {
+	switch cmp := v.compare(c.version); c.op {
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	default:
+		return cmp == 0
+	}
+}
+
+// versionRange is a set of comparisons that must all hold.
+type versionRange []comparison
+
+func (r versionRange) contains(v semver) bool {
+	for _, c := range r {
+		if !c.holds(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseConstraint(constraint string) ([]versionRange, error) {
+	var ranges []versionRange
+	for _, alternative := range strings.Split(constraint, "||") {
+		var r versionRange
+		for _, term := range strings.FieldsFunc(alternative, func(c rune) bool { return c == ' ' || c == ',' }) {
+			comparisons, err := parseComparison(term)
+			if err != nil {
+				return nil, fmt.Errorf("version constraint %q: %w", constraint, err)
+			}
+			r = append(r, comparisons...)
+		}
+		if len(r) == 0 {
+			return nil, fmt.Errorf("version constraint %q: empty range", constraint)
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// parseComparison parses a term of a constraint, expanding ^ and ~ into
+// a pair of comparisons.
+func parseComparison(term string) ([]comparison, error) {
+	op := ""
+	for _, candidate := range []string{">=", "<=", "!=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(term, candidate) {
+			op = candidate
+			break
+		}
+	}
+
+	v, parts, err := parseVersionParts(term[len(op):])
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "^":
+		upper := semver{v[0] + 1}
+		return []comparison{{">=", v}, {"<", upper}}, nil
+	case "~":
+		upper := semver{v[0], v[1] + 1}
+		if parts == 1 {
+			upper = semver{v[0] + 1}
+		}
+		return []comparison{{">=", v}, {"<", upper}}, nil
+	default:
+		return []comparison{{op, v}}, nil
+	}
+}
@@ -0,0 +1,77 @@
+package checks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andres-vara/health"
+)
+
+func TestVersionConstraints(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{">=3.8 <4", "3.12.1", true},
+		{">=3.8 <4", "4.0.0", false},
+		{">=3.8, <4", "3.7.9", false},
+		{"^3.8", "3.13", true},
+		{"^3.8", "4.0", false},
+		{"~3.8", "3.8.9", true},
+		{"~3.8", "3.9.0", false},
+		{"~3", "3.9.0", true},
+		{"=8.0.34", "8.0.34-0ubuntu0.22.04.1", true},
+		{"!=2.1.0", "v2.1.0", false},
+		{"<2 || >=3", "2.5.0", false},
+		{"<2 || >=3", "3.0.0", true},
+	}
+
+	for _, tt := range tests {
+		ranges, err := parseConstraint(tt.constraint)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.constraint, err)
+		}
+		version, err := parseVersion(tt.version)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.version, err)
+		}
+
+		got := false
+		for _, r := range ranges {
+			got = got || r.contains(version)
+		}
+		if got != tt.want {
+			t.Errorf("%q in %q: expected %v", tt.version, tt.constraint, tt.want)
+		}
+	}
+
+	for _, constraint := range []string{"", ">=x", "<2 ||"} {
+		if _, err := parseConstraint(constraint); err == nil {
+			t.Errorf("expected %q to be rejected", constraint)
+		}
+	}
+}
+
+func TestSupportedVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version":{"number":"8.11.3"}}`))
+	}))
+	defer server.Close()
+
+	source := HTTPVersion(server.URL, "version.number")
+	if err := SupportedVersion(source, "^8.10")(context.Background()); err != nil {
+		t.Errorf("expected a supported version to pass, got %v", err)
+	}
+	if err := SupportedVersion(source, ">=7 <8")(context.Background()); health.CategoryOf(err) != health.CategoryDependency {
+		t.Errorf("expected an unsupported version to fail, got %v", err)
+	}
+	if err := SupportedVersion(source, ">=oops")(context.Background()); health.CategoryOf(err) != health.CategoryConfiguration {
+		t.Errorf("expected an invalid constraint to be a configuration error, got %v", err)
+	}
+	if err := SupportedVersion(HTTPVersion(server.URL, "missing"), "^8")(context.Background()); err == nil {
+		t.Error("expected a missing field to fail")
+	}
+}
@@ -0,0 +1,266 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	neturl "net/url"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+// webSocketGUID is the key suffix the server hashes in its handshake
+// answer, from RFC 6455.
+const webSocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket frame opcodes
+const (
+	wsClose = 0x8
+	wsPing  = 0x9
+	wsPong  = 0xA
+)
+
+// WebSocketOption configures a WebSocket check.
+type WebSocketOption func(*webSocketCheck)
+
+type webSocketCheck struct {
+	url       *neturl.URL
+	timeout   time.Duration
+	header    http.Header
+	tlsConfig *tls.Config
+	ping      bool
+}
+
+// WithPing makes the check send a ping after the handshake and wait for the
+// pong, proving messages flow and not just the upgrade.
+func WithPing() WebSocketOption {
+	return func(c *webSocketCheck) {
+		c.ping = true
+	}
+}
+
+// WithWebSocketHeader adds a header to the handshake request, such as
+// credentials.
+func WithWebSocketHeader(key, value string) WebSocketOption {
+	return func(c *webSocketCheck) {
+		c.header.Add(key, value)
+	}
+}
+
+// WithWebSocketTLS sets the TLS configuration used for wss:// URLs.
+func WithWebSocketTLS(config *tls.Config) WebSocketOption {
+	return func(c *webSocketCheck) {
+		c.tlsConfig = config
+	}
+}
+
+// WebSocket returns a check performing a WebSocket handshake with the ws://
+// or wss:// url, and optionally a ping/pong round trip, within timeout
+// (DefaultTimeout if zero). A plain 200 on the upgrade path doesn't prove
+// the WebSocket path works: proxies and load balancers in between regularly
+// break the upgrade.
+func WebSocket(url string, timeout time.Duration, opts ...WebSocketOption) health.CheckFunc {
+	c := &webSocketCheck{timeout: timeoutOr(timeout), header: make(http.Header)}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	parsed, err := neturl.Parse(url)
+	if err == nil && parsed.Scheme != "ws" && parsed.Scheme != "wss" {
+		err = fmt.Errorf("unsupported scheme %q", parsed.Scheme)
+	}
+	if err != nil {
+		return func(ctx context.Context) error {
+			return health.ConfigurationError(fmt.Errorf("websocket url %q: %w", url, err))
+		}
+	}
+	c.url = parsed
+
+	return c.check
+}
+
+func (c *webSocketCheck) check(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return health.DependencyError(err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	reader := bufio.NewReader(conn)
+	if err := c.handshake(conn, reader); err != nil {
+		return health.DependencyError(fmt.Errorf("websocket handshake: %w", err))
+	}
+
+	if c.ping {
+		if err := pingPong(conn, reader); err != nil {
+			return health.DependencyError(fmt.Errorf("websocket ping: %w", err))
+		}
+	}
+
+	_ = writeFrame(conn, wsClose, nil)
+	return nil
+}
+
+func (c *webSocketCheck) dial(ctx context.Context) (net.Conn, error) {
+	host := c.url.Host
+	if c.url.Port() == "" {
+		port := "80"
+		if c.url.Scheme == "wss" {
+			port = "443"
+		}
+		host = net.JoinHostPort(c.url.Hostname(), port)
+	}
+
+	if c.url.Scheme == "wss" {
+		config := c.tlsConfig.Clone()
+		if config == nil {
+			config = &tls.Config{}
+		}
+		if config.ServerName == "" {
+			config.ServerName = c.url.Hostname()
+		}
+		dialer := &tls.Dialer{Config: config}
+		return dialer.DialContext(ctx, "tcp", host)
+	}
+
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, "tcp", host)
+}
+
+func (c *webSocketCheck) handshake(conn net.Conn, reader *bufio.Reader) error {
+	nonce := make([]byte, 16)
+	_, _ = rand.Read(nonce)
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	url := *c.url
+	url.Scheme = "http"
+	req, err := http.NewRequest(http.MethodGet, url.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header = c.header.Clone()
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if err := req.Write(conn); err != nil {
+		return err
+	}
+
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		resp.Body.Close()
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	sum := sha1.Sum([]byte(key + webSocketGUID))
+	if resp.Header.Get("Sec-WebSocket-Accept") != base64.StdEncoding.EncodeToString(sum[:]) {
+		return errors.New("invalid Sec-WebSocket-Accept")
+	}
+	return nil
+}
+
+// pingPong sends a ping and reads frames until the matching pong.
+func pingPong(conn net.Conn, reader *bufio.Reader) error {
+	payload := []byte("health")
+	if err := writeFrame(conn, wsPing, payload); err != nil {
+		return err
+	}
+
+	for {
+		opcode, data, err := readFrame(reader)
+		if err != nil {
+			return err
+		}
+		switch {
+		case opcode == wsPong && bytes.Equal(data, payload):
+			return nil
+		case opcode == wsClose:
+			return errors.New("connection closed by the server")
+		}
+	}
+}
+
+// writeFrame writes a final, masked frame, as clients must. Payloads are
+// short control payloads, under 126 bytes.
+func writeFrame(w io.Writer, opcode byte, payload []byte) error {
+	frame := []byte{0x80 | opcode, 0x80 | byte(len(payload))}
+
+	mask := make([]byte, 4)
+	_, _ = rand.Read(mask)
+	frame = append(frame, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+
+	_, err := w.Write(frame)
+	return err
+}
+
+// readFrame reads a frame sent by the server, returning its opcode and
+// payload.
+func readFrame(r *bufio.Reader) (byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(r, extended[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(r, extended[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+	if length > maxHTTPBody {
+		return 0, nil, fmt.Errorf("frame of %d bytes too large", length)
+	}
+
+	var mask []byte
+	if header[1]&0x80 != 0 {
+		mask = make([]byte, 4)
+		if _, err := io.ReadFull(r, mask); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	if mask != nil {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+
+	return header[0] & 0x0F, payload, nil
+}
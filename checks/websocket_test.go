@@ -0,0 +1,87 @@
+package checks
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// webSocketServer answers the handshake, then pongs pings unless mute.
+func webSocketServer(t *testing.T, mute bool) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "websocket" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		conn, rw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		sum := sha1.Sum([]byte(r.Header.Get("Sec-WebSocket-Key") + webSocketGUID))
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+			"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n")
+		rw.Flush()
+
+		for {
+			opcode, payload, err := readFrame(rw.Reader)
+			if err != nil || opcode == wsClose {
+				return
+			}
+			if opcode == wsPing && !mute {
+				rw.Write(append([]byte{0x80 | wsPong, byte(len(payload))}, payload...))
+				rw.Flush()
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestWebSocket(t *testing.T) {
+	url := webSocketServer(t, false)
+	if err := WebSocket(url, time.Second)(context.Background()); err != nil {
+		t.Errorf("expected the handshake to pass, got %v", err)
+	}
+	if err := WebSocket(url, time.Second, WithPing())(context.Background()); err != nil {
+		t.Errorf("expected the ping to pass, got %v", err)
+	}
+	if err := WebSocket(url, 0)(context.Background()); err != nil {
+		t.Errorf("expected a zero timeout to use the default, got %v", err)
+	}
+
+	mute := webSocketServer(t, true)
+	if err := WebSocket(mute, 100*time.Millisecond, WithPing())(context.Background()); err == nil {
+		t.Error("expected a missing pong to fail")
+	}
+
+	// A plain 200 on the path isn't an upgrade
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer plain.Close()
+	if err := WebSocket("ws"+strings.TrimPrefix(plain.URL, "http"), time.Second)(context.Background()); err == nil {
+		t.Error("expected a plain 200 to fail")
+	}
+
+	if err := WebSocket("http://example.com", time.Second)(context.Background()); err == nil {
+		t.Error("expected a non-websocket url to fail")
+	}
+}
+
+func TestReadFrameUnmasks(t *testing.T) {
+	var buf strings.Builder
+	if err := writeFrame(&buf, wsPing, []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	opcode, payload, err := readFrame(bufio.NewReader(strings.NewReader(buf.String())))
+	if err != nil || opcode != wsPing || string(payload) != "hello" {
+		t.Errorf("unexpected frame %x %q %v", opcode, payload, err)
+	}
+}
@@ -0,0 +1,100 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+// Writable returns a check that dir, typically on an NFS or EFS volume, is
+// present and writable, by writing, fsyncing and removing a small probe
+// file in it. Point dir at a directory that only exists on the volume so an
+// unmounted volume fails the check instead of writing to the host.
+//
+// A hung network mount blocks file calls forever rather than failing them,
+// so the probe runs in the background and the check fails once it takes
+// longer than timeout (DefaultTimeout if zero). No new probe starts while
+// one is still stuck.
+func Writable(dir string, timeout time.Duration) health.CheckFunc {
+	timeout = timeoutOr(timeout)
+	var (
+		mutex   sync.Mutex
+		running *writeProbe
+	)
+
+	return func(ctx context.Context) error {
+		mutex.Lock()
+		if running == nil {
+			running = &writeProbe{done: make(chan struct{})}
+			go running.run(dir)
+		}
+		probe := running
+		mutex.Unlock()
+
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		select {
+		case <-probe.done:
+			mutex.Lock()
+			if running == probe {
+				running = nil
+			}
+			mutex.Unlock()
+			return probe.err
+		case <-timer.C:
+			return health.DependencyError(fmt.Errorf("writing to %s blocked for more than %v", dir, timeout))
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// writeProbe is one attempt at writing to a directory, possibly still
+// running.
+type writeProbe struct {
+	done chan struct{}
+	err  error
+}
+
+func (p *writeProbe) run(dir string) {
+	defer close(p.done)
+	if err := probeWrite(dir); err != nil {
+		p.err = health.DependencyError(err)
+	}
+}
+
+// probeWrite is the probe run by Writable, a variable so tests can simulate
+// a hung mount.
+var probeWrite = writeProbeFile
+
+// writeProbeFile writes, fsyncs and removes a probe file in dir.
+func writeProbeFile(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	f, err := os.CreateTemp(dir, ".health-probe-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.WriteString("health probe\n"); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,64 @@
+package checks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWritable(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := Writable(dir, time.Second)(context.Background()); err != nil {
+		t.Errorf("expected a writable directory to pass, got %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected the probe file to be removed, found %v", entries)
+	}
+
+	if err := Writable(filepath.Join(dir, "missing"), time.Second)(context.Background()); err == nil {
+		t.Error("expected a missing directory to fail")
+	}
+
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := Writable(file, time.Second)(context.Background()); err == nil {
+		t.Error("expected a regular file to fail")
+	}
+}
+
+func TestWritableHung(t *testing.T) {
+	release := make(chan struct{})
+	calls := 0
+	probeWrite = func(dir string) error {
+		calls++
+		<-release
+		return nil
+	}
+	defer func() { probeWrite = writeProbeFile }()
+
+	check := Writable(t.TempDir(), 10*time.Millisecond)
+	if err := check(context.Background()); err == nil {
+		t.Fatal("expected a hung write to fail the check")
+	}
+	// The stuck probe is waited on again rather than piling up new ones
+	if err := check(context.Background()); err == nil {
+		t.Fatal("expected a still hung write to fail the check")
+	}
+
+	close(release)
+	if err := check(context.Background()); err != nil {
+		t.Errorf("expected the check to pass once the write went through, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected a single probe, got %d", calls)
+	}
+}
@@ -0,0 +1,310 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEvaluateResults(t *testing.T) {
+	h := &Health{status: Up}
+	h.Register("db", func(ctx context.Context) error { return nil })
+	h.Register("cache", func(ctx context.Context) error { return errors.New("connection refused") })
+
+	results := h.Evaluate(context.Background())
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	// Results are sorted by name
+	if results[0].Status != Down || results[0].Reason != "connection refused" {
+		t.Errorf("cache check: got %v %q", results[0].Status, results[0].Reason)
+	}
+	if results[1].Status != Up {
+		t.Errorf("db check: got %v want %v", results[1].Status, Up)
+	}
+
+	status, reason, _ := h.overall()
+	if status != Down {
+		t.Errorf("overall status: got %v want %v", status, Down)
+	}
+	if reason != "cache: connection refused" {
+		t.Errorf("overall reason: got %q", reason)
+	}
+}
+
+// TestRegisterDefaultHandler covers the package-level flow: checks
+// registered by name are aggregated into the overall status and reported one
+// by one in the JSON body.
+func TestRegisterDefaultHandler(t *testing.T) {
+	Reset()
+	defer Reset()
+	defer handler.WithJSON(handler.useJSON)
+	handler.WithJSON(true)
+
+	Register("database", func(ctx context.Context) error { return nil })
+	Register("cache", func(ctx context.Context) error { return errors.New("connection refused") })
+	Evaluate(context.Background())
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/health", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d", rr.Code)
+	}
+
+	var body responseBody
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Status != string(Down) || body.Reason != "cache: connection refused" {
+		t.Errorf("unexpected overall status %q %q", body.Status, body.Reason)
+	}
+	if len(body.Checks) != 2 || body.Checks[0].Name != "cache" || body.Checks[1].Status != Up {
+		t.Errorf("unexpected checks %+v", body.Checks)
+	}
+}
+
+func TestRegisterReplacesCheck(t *testing.T) {
+	h := &Health{status: Up}
+	h.Register("db", func(ctx context.Context) error { return errors.New("old") })
+	h.Register("db", func(ctx context.Context) error { return nil })
+
+	results := h.Evaluate(context.Background())
+	if len(results) != 1 || results[0].Status != Up {
+		t.Errorf("expected a single passing check, got %+v", results)
+	}
+}
+
+func TestRequiredAtStartup(t *testing.T) {
+	h := &Health{status: Up}
+	upstreamErr := errors.New("connection refused")
+	h.Register("upstream", func(ctx context.Context) error { return upstreamErr }, RequiredAtStartup())
+	h.Register("db", func(ctx context.Context) error { return nil })
+
+	// Before the service has been UP, the check blocks readiness
+	h.Evaluate(context.Background())
+	if status, _, _ := h.overall(); status != Down {
+		t.Fatalf("expected DOWN before warm-up, got %v", status)
+	}
+
+	upstreamErr = nil
+	h.Evaluate(context.Background())
+	if status, _, _ := h.overall(); status != Up {
+		t.Fatalf("expected UP once the check passed, got %v", status)
+	}
+
+	// Afterwards its failures only degrade the service
+	upstreamErr = errors.New("connection refused")
+	results := h.Evaluate(context.Background())
+	if results[1].Status != Degraded || results[1].Reason != "connection refused" {
+		t.Errorf("expected the check result to be DEGRADED, got %+v", results[1])
+	}
+	status, reason, _ := h.overall()
+	if status != Degraded || !strings.Contains(reason, "connection refused") {
+		t.Errorf("expected DEGRADED with the check's reason after warm-up, got %v %q", status, reason)
+	}
+}
+
+func TestEvaluateDeadlineBudget(t *testing.T) {
+	h := &Health{status: Up}
+
+	// The first check hangs and ignores its context; it must only consume
+	// its share of the budget.
+	h.Register("hanging", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+	h.Register("quick", func(ctx context.Context) error { return nil })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	results := h.Evaluate(ctx)
+	if elapsed := time.Since(start); elapsed > 400*time.Millisecond {
+		t.Errorf("evaluation blew past the deadline: took %v", elapsed)
+	}
+
+	if results[0].Status != NotEvaluated {
+		t.Errorf("hanging check: got %v want %v", results[0].Status, NotEvaluated)
+	}
+	if results[1].Status != Up {
+		t.Errorf("quick check: got %v want %v", results[1].Status, Up)
+	}
+
+	// Not evaluated checks must not take the service down
+	if status, _, _ := h.overall(); status != Up {
+		t.Errorf("overall status: got %v want %v", status, Up)
+	}
+}
+
+func TestMaxConcurrency(t *testing.T) {
+	h := New(WithMaxConcurrency(3))
+
+	var mutex sync.Mutex
+	running, peak := 0, 0
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		h.Register(name, func(ctx context.Context) error {
+			mutex.Lock()
+			running++
+			peak = max(peak, running)
+			mutex.Unlock()
+
+			time.Sleep(50 * time.Millisecond)
+
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+			return nil
+		})
+	}
+
+	start := time.Now()
+	results := h.Evaluate(context.Background())
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Errorf("checks didn't run concurrently: took %v", elapsed)
+	}
+	if peak != 3 {
+		t.Errorf("expected at most 3 checks at once, got %d", peak)
+	}
+	for _, r := range results {
+		if r.Status != Up {
+			t.Errorf("%s: got %v want %v", r.Name, r.Status, Up)
+		}
+	}
+}
+
+func TestEvaluateExpiredDeadline(t *testing.T) {
+	h := &Health{status: Up}
+	called := false
+	h.Register("db", func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := h.Evaluate(ctx)
+	if called {
+		t.Error("check should not run once the deadline has passed")
+	}
+	if results[0].Status != NotEvaluated {
+		t.Errorf("got %v want %v", results[0].Status, NotEvaluated)
+	}
+}
+
+func TestOnDemandEvaluation(t *testing.T) {
+	h := &Health{status: Up}
+	h.Register("db", func(ctx context.Context) error { return errors.New("down") })
+
+	// Without on-demand evaluation the checks are never run
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest("GET", "/health", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("got %v want %v", rr.Code, http.StatusOK)
+	}
+
+	h.WithOnDemand(time.Second)
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest("GET", "/health", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("got %v want %v", rr.Code, http.StatusServiceUnavailable)
+	}
+	if body := rr.Body.String(); body != "DOWN: db: down" {
+		t.Errorf("unexpected body: %q", body)
+	}
+}
+
+func TestEvaluationTimeout(t *testing.T) {
+	h := &Health{status: Up}
+	h.WithEvaluationTimeout(100 * time.Millisecond)
+
+	block := make(chan struct{})
+	defer close(block)
+
+	h.Register("fast", func(ctx context.Context) error { return nil })
+	h.Register("hanging", func(ctx context.Context) error {
+		<-block
+		return nil
+	})
+	h.Register("never-started", func(ctx context.Context) error { return nil })
+
+	start := time.Now()
+	results := h.Evaluate(context.Background())
+	if elapsed := time.Since(start); elapsed > 300*time.Millisecond {
+		t.Errorf("evaluation blocked past its timeout: took %v", elapsed)
+	}
+
+	expected := []Status{Up, TimedOut, TimedOut}
+	for i, want := range expected {
+		if results[i].Status != want {
+			t.Errorf("%s: got %v want %v", results[i].Name, results[i].Status, want)
+		}
+	}
+
+	// The partial results are published and the timed out checks fail the service
+	status, reason, published := h.overall()
+	if len(published) != 3 {
+		t.Fatalf("expected 3 published results, got %d", len(published))
+	}
+	if status != Down {
+		t.Errorf("overall status: got %v want %v", status, Down)
+	}
+	if reason != "hanging: evaluation timed out before the check finished" {
+		t.Errorf("unexpected reason: %q", reason)
+	}
+}
+
+func TestCheckTimeout(t *testing.T) {
+	h := &Health{status: Up}
+
+	block := make(chan struct{})
+	defer close(block)
+
+	h.RegisterWithTimeout("db", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, 20*time.Millisecond)
+	h.RegisterWithTimeout("hanging", func(ctx context.Context) error {
+		<-block
+		return nil
+	}, 20*time.Millisecond)
+	h.Register("z-after", func(ctx context.Context) error { return nil })
+
+	start := time.Now()
+	results := h.Evaluate(context.Background())
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("timed out checks stalled the evaluation: took %v", elapsed)
+	}
+
+	for i, want := range []Status{TimedOut, TimedOut, Up} {
+		if results[i].Status != want {
+			t.Errorf("%s: got %v want %v", results[i].Name, results[i].Status, want)
+		}
+	}
+	if results[0].Reason != "timeout" || results[1].Reason != "timeout" {
+		t.Errorf("expected timeout reasons, got %q and %q", results[0].Reason, results[1].Reason)
+	}
+}
+
+func TestSetDetail(t *testing.T) {
+	h := &Health{status: Up}
+	h.Register("replication", func(ctx context.Context) error {
+		SetDetail(ctx, "lag_seconds", 1.5)
+		return nil
+	})
+
+	results := h.Evaluate(context.Background())
+	if results[0].Details["lag_seconds"] != 1.5 {
+		t.Errorf("unexpected details: %v", results[0].Details)
+	}
+
+	// Outside of a running check it does nothing
+	SetDetail(context.Background(), "ignored", true)
+}
@@ -0,0 +1,143 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultMaxBodySize is the largest remote health response the client reads.
+const DefaultMaxBodySize = 1 << 20
+
+var (
+	// ErrBodyTooLarge is returned for remote responses above the size limit
+	ErrBodyTooLarge = errors.New("health: response body too large")
+	// ErrUnparseable is returned for remote responses that aren't in any
+	// known health format
+	ErrUnparseable = errors.New("health: unparseable response body")
+)
+
+// RemoteStatus is the health reported by another service.
+type RemoteStatus struct {
+	Status Status        `json:"status"`
+	Reason string        `json:"reason,omitempty"`
+	Checks []CheckResult `json:"checks,omitempty"`
+	Groups []GroupReport `json:"groups,omitempty"`
+}
+
+// Client fetches the health of remote services exposing this package's
+// handlers (or anything close enough to them).
+type Client struct {
+	// HTTPClient is used for requests; http.DefaultClient if nil
+	HTTPClient *http.Client
+	// MaxBodySize caps how much of a response is read; DefaultMaxBodySize
+	// if zero
+	MaxBodySize int64
+}
+
+// Fetch gets the health of the service at url. A body that can't be parsed
+// doesn't fail the call: the status is then derived from the HTTP status code
+// and the parse error is used as the reason. An error is only returned when
+// the request itself fails.
+func (c *Client) Fetch(ctx context.Context, url string) (RemoteStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return RemoteStatus{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return RemoteStatus{}, err
+	}
+	defer resp.Body.Close()
+
+	status, err := ParseStatus(resp.Header.Get("Content-Type"), resp.Body, c.MaxBodySize)
+	if err != nil {
+		status = RemoteStatus{Status: Down, Reason: err.Error()}
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			status.Status = Up
+		}
+	}
+
+	return status, nil
+}
+
+// ParseStatus parses a health response body in the JSON or plain text format
+// produced by this package. It is deliberately forgiving: unknown JSON fields
+// are ignored and the format is sniffed when the content type is missing or
+// wrong. It never reads more than limit bytes (DefaultMaxBodySize if limit is
+// zero or negative) and returns ErrBodyTooLarge for bigger bodies.
+func ParseStatus(contentType string, body io.Reader, limit int64) (RemoteStatus, error) {
+	if limit <= 0 {
+		limit = DefaultMaxBodySize
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return RemoteStatus{}, err
+	}
+	if int64(len(data)) > limit {
+		return RemoteStatus{}, ErrBodyTooLarge
+	}
+
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return RemoteStatus{}, fmt.Errorf("%w: empty body", ErrUnparseable)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "application/json" || data[0] == '{' {
+		status, err := parseJSONStatus(data)
+		if err == nil || data[0] == '{' {
+			return status, err
+		}
+	}
+
+	return parseTextStatus(data)
+}
+
+func parseJSONStatus(data []byte) (RemoteStatus, error) {
+	var status RemoteStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return RemoteStatus{}, fmt.Errorf("%w: %v", ErrUnparseable, err)
+	}
+
+	status.Status = normalizeStatus(string(status.Status))
+	if status.Status == "" {
+		return RemoteStatus{}, fmt.Errorf("%w: missing status", ErrUnparseable)
+	}
+
+	return status, nil
+}
+
+// parseTextStatus parses the "STATUS: reason" plain text format.
+func parseTextStatus(data []byte) (RemoteStatus, error) {
+	if !utf8.Valid(data) {
+		return RemoteStatus{}, fmt.Errorf("%w: body is not valid UTF-8", ErrUnparseable)
+	}
+
+	word, reason, _ := strings.Cut(string(data), ":")
+	status := normalizeStatus(word)
+	if status == "" || strings.ContainsAny(string(status), " \t\r\n") {
+		return RemoteStatus{}, fmt.Errorf("%w: no status word", ErrUnparseable)
+	}
+
+	return RemoteStatus{Status: status, Reason: strings.TrimSpace(reason)}, nil
+}
+
+func normalizeStatus(s string) Status {
+	return Status(strings.ToUpper(strings.TrimSpace(s)))
+}
@@ -0,0 +1,125 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      Status
+		reason      string
+		err         error
+	}{
+		{"json", "application/json", `{"status":"DOWN","reason":"db"}`, Down, "db", nil},
+		{"json unknown fields", "application/json", `{"status":"UP","version":"1.2.3","extra":{"a":1}}`, Up, "", nil},
+		{"json wrong content type", "text/plain", `{"status":"UP"}`, Up, "", nil},
+		{"json lower case", "application/json; charset=utf-8", `{"status":"down"}`, Down, "", nil},
+		{"json missing status", "application/json", `{"reason":"x"}`, "", "", ErrUnparseable},
+		{"json truncated", "application/json", `{"status":"UP","rea`, "", "", ErrUnparseable},
+		{"text", "text/plain", "DOWN: Database connection failed", Down, "Database connection failed", nil},
+		{"text without reason", "", "UP: ", Up, "", nil},
+		{"text legacy", "", "OK", "OK", "", nil},
+		{"text mislabelled as json", "application/json", "UP: ", Up, "", nil},
+		{"html", "text/html", "<html><body>502 Bad Gateway</body></html>", "", "", ErrUnparseable},
+		{"empty", "application/json", "", "", "", ErrUnparseable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := ParseStatus(tt.contentType, strings.NewReader(tt.body), 0)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v want %v", err, tt.err)
+			}
+			if status.Status != tt.status || status.Reason != tt.reason {
+				t.Errorf("got %q %q want %q %q", status.Status, status.Reason, tt.status, tt.reason)
+			}
+		})
+	}
+}
+
+func TestParseStatusSizeLimit(t *testing.T) {
+	body := `{"status":"UP","reason":"` + strings.Repeat("x", 100) + `"}`
+
+	if _, err := ParseStatus("application/json", strings.NewReader(body), 64); !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("got %v want %v", err, ErrBodyTooLarge)
+	}
+	if _, err := ParseStatus("application/json", strings.NewReader(body), int64(len(body))); err != nil {
+		t.Errorf("body at the limit should parse: %v", err)
+	}
+}
+
+func TestClientFetch(t *testing.T) {
+	h := &Health{status: Up, useJSON: true}
+	h.Register("db", func(ctx context.Context) error { return errors.New("refused") })
+	h.Evaluate(context.Background())
+
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	client := &Client{}
+	status, err := client.Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != Down || len(status.Checks) != 1 || status.Checks[0].Name != "db" {
+		t.Errorf("unexpected status: %+v", status)
+	}
+}
+
+func TestClientFetchGarbage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte("<html>bad gateway</html>"))
+	}))
+	defer server.Close()
+
+	status, err := (&Client{}).Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != Down || status.Reason == "" {
+		t.Errorf("status should fall back to the HTTP code: %+v", status)
+	}
+}
+
+func FuzzParseStatus(f *testing.F) {
+	f.Add("application/json", []byte(`{"status":"UP","checks":[{"name":"db","status":"DOWN","duration":12}]}`))
+	f.Add("application/json", []byte(`{"status":"DOWN","reason":"x","unknown":[1,2,{"a":null}]}`))
+	f.Add("text/plain", []byte("DOWN: reason: with: colons"))
+	f.Add("", []byte("\xff\xfe"))
+	f.Add("application/json", []byte(`{"status":`))
+	f.Add("text/html; charset=", []byte("<html>"))
+
+	f.Fuzz(func(t *testing.T, contentType string, body []byte) {
+		const limit = 4096
+
+		status, err := ParseStatus(contentType, bytes.NewReader(body), limit)
+		if err != nil {
+			if status.Status != "" {
+				t.Errorf("status %q returned along with error %v", status.Status, err)
+			}
+			return
+		}
+
+		if len(body) > limit {
+			t.Errorf("body of %d bytes parsed despite the %d byte limit", len(body), limit)
+		}
+		if status.Status == "" {
+			t.Error("parsed status is empty")
+		}
+		if !utf8.ValidString(string(status.Status)) {
+			t.Errorf("parsed status is not valid UTF-8: %q", status.Status)
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package health
+
+import "time"
+
+// Clock is the source of time used by the health package: check timestamps
+// and durations, scheduler intervals and anything else that reads the time
+// goes through it. It defaults to the real wall clock and can be swapped (for
+// example with healthtest.Clock) to make time-dependent behavior
+// deterministic in tests. Context deadlines are the one exception, since the
+// context package always runs on real time.
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks at intervals, like time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
+
+// WithClock sets the clock used by the handler. The uptime is then counted
+// from when it was set, on that clock.
+func (h *Health) WithClock(clock Clock) *Health {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.clock = clock
+	h.clockStart = time.Time{}
+	if clock != nil {
+		h.clockStart = clock.Now()
+	}
+	return h
+}
+
+// getClock returns the configured clock, falling back to real time.
+func (h *Health) getClock() Clock {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	return h.clockLocked()
+}
+
+// clockLocked is getClock for callers already holding the mutex.
+func (h *Health) clockLocked() Clock {
+	if h.clock == nil {
+		return realClock{}
+	}
+	return h.clock
+}
@@ -0,0 +1,65 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stepClock is a minimal fake clock that only moves when advanced.
+type stepClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func (c *stepClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+func (c *stepClock) NewTicker(d time.Duration) Ticker {
+	return realClock{}.NewTicker(d)
+}
+
+func (c *stepClock) advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func TestCheckTimesUseClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := &stepClock{now: start}
+
+	h := &Health{status: Up}
+	h.WithClock(clock)
+	h.Register("slow", func(ctx context.Context) error {
+		clock.advance(3 * time.Second)
+		return nil
+	})
+
+	results := h.Evaluate(context.Background())
+	if !results[0].CheckedAt.Equal(start) {
+		t.Errorf("checked at: got %v want %v", results[0].CheckedAt, start)
+	}
+	if results[0].Duration != 3*time.Second {
+		t.Errorf("duration: got %v want %v", results[0].Duration, 3*time.Second)
+	}
+}
+
+func TestDefaultClock(t *testing.T) {
+	h := &Health{status: Up}
+	if _, ok := h.getClock().(realClock); !ok {
+		t.Errorf("expected the real clock by default, got %T", h.getClock())
+	}
+
+	h.WithClock(&stepClock{})
+	h.WithClock(nil)
+	if _, ok := h.getClock().(realClock); !ok {
+		t.Errorf("expected a nil clock to fall back to real time, got %T", h.getClock())
+	}
+}
@@ -0,0 +1,163 @@
+package health
+
+import (
+	"html/template"
+	"net/http"
+	"time"
+)
+
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Health: {{.Status}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+td, th { padding: 0.3em 1em; text-align: left; border-bottom: 1px solid #ddd; }
+.UP { color: #2e7d32; }
+.DOWN, .TIMED_OUT { color: #c62828; }
+.OVERLOADED { color: #ef6c00; }
+.DEGRADED { color: #f9a825; }
+.NOT_EVALUATED { color: #757575; }
+.trend { font-family: monospace; letter-spacing: -0.1em; }
+</style>
+</head>
+<body>
+<h1 class="{{.Class}}">{{.Status}}</h1>
+{{if .Reason}}<p>{{.Reason}}</p>{{end}}
+{{if .Checks}}{{template "checks" .Checks}}{{end}}
+{{range .Groups}}<h2>{{.Name}}: <span class="{{.Class}}">{{.Status}}</span></h2>
+{{template "checks" .Checks}}
+{{end}}{{with .Availability}}<p>Availability: {{printf "%.2f" .FiveMinutes}}% (5m), {{printf "%.2f" .OneHour}}% (1h), {{printf "%.2f" .OneDay}}% (24h)</p>{{end}}
+{{if .Debug}}<p>Debug: {{range $name, $path := .Debug}}<a href="{{$path}}">{{$name}}</a> {{end}}</p>{{end}}
+<p><small>Generated at {{.GeneratedAt}}</small></p>
+</body>
+</html>
+{{define "checks"}}<table>
+<tr><th>Check</th><th>Status</th><th>Reason</th><th>Owner</th><th>Duration</th><th>Last hour</th><th>Checked at</th></tr>
+{{range .}}<tr><td>{{.Name}}{{if .Description}}<br><small>{{.Description}}</small>{{end}}</td><td class="{{.Class}}">{{.Status}}</td><td>{{.Reason}}</td><td>{{.Owner}}{{if .Runbook}} <a href="{{.Runbook}}">runbook</a>{{end}}</td><td>{{.Duration}}</td><td class="trend">{{range .Trend}}<span class="{{.Class}}" title="{{.Title}}">{{.Bar}}</span>{{end}}</td><td>{{.CheckedAt}}</td></tr>
+{{end}}</table>
+{{end}}`))
+
+type dashboardCheck struct {
+	Name        string
+	Description string
+	Status      string
+	Class       Status
+	Reason      string
+	Owner       string
+	Runbook     string
+	Duration    time.Duration
+	CheckedAt   string
+	// Trend is the sparkline of the check's recent samples
+	Trend []dashboardSample
+}
+
+type dashboardSample struct {
+	Bar   string
+	Class Status
+	Title string
+}
+
+type dashboardGroup struct {
+	Name   string
+	Status string
+	Class  Status
+	Checks []dashboardCheck
+}
+
+type dashboardData struct {
+	Status       string
+	Class        Status
+	Reason       string
+	Checks       []dashboardCheck
+	Groups       []dashboardGroup
+	Availability *AvailabilityReport
+	Debug        map[string]string
+	GeneratedAt  string
+}
+
+// DashboardHandler serves an HTML dashboard of the default handler.
+func DashboardHandler() http.Handler {
+	return handler.DashboardHandler()
+}
+
+// DashboardHandler serves a human-readable HTML dashboard with the overall
+// status, every check result and the availability. Status words and reasons
+// go through the translator, if any, and debug links are only shown to admin
+// callers. It always answers 200, as it's meant for people, not probes.
+func (h *Health) DashboardHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.evaluateOnDemand(r.Context(), selection{})
+
+		report := h.report(r)
+		data := h.dashboardData(h.snapshotOf(report))
+		data.Debug = report.Debug
+		h.addTrends(data.Checks)
+		for _, group := range data.Groups {
+			h.addTrends(group.Checks)
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = dashboardTemplate.Execute(w, data)
+	})
+}
+
+// dashboardData prepares snapshot for display, translating status words and
+// reasons.
+func (h *Health) dashboardData(snapshot StatusSnapshot) dashboardData {
+	h.mutex.RLock()
+	translator := h.translator
+	h.mutex.RUnlock()
+	if translator == nil {
+		translator = MapTranslator{}
+	}
+
+	data := dashboardData{
+		Status:       translator.Status(snapshot.Status),
+		Class:        snapshot.Status,
+		Reason:       translator.Reason(snapshot.Reason),
+		Availability: snapshot.Availability,
+		GeneratedAt:  snapshot.TakenAt.Format(time.RFC3339),
+	}
+	data.Checks = dashboardChecks(translator, snapshot.Checks)
+	for _, group := range snapshot.Groups {
+		data.Groups = append(data.Groups, dashboardGroup{
+			Name:   group.Name,
+			Status: translator.Status(group.Status),
+			Class:  group.Status,
+			Checks: dashboardChecks(translator, group.Checks),
+		})
+	}
+	return data
+}
+
+// addTrends draws the sparklines of checks.
+func (h *Health) addTrends(checks []dashboardCheck) {
+	for i := range checks {
+		checks[i].Trend = sparkline(h.Samples(checks[i].Name))
+	}
+}
+
+// dashboardChecks translates results for display.
+func dashboardChecks(translator Translator, results []CheckResult) []dashboardCheck {
+	var checks []dashboardCheck
+	for _, result := range results {
+		check := dashboardCheck{
+			Name:        result.Name,
+			Description: result.Description,
+			Status:      translator.Status(result.Status),
+			Class:       result.Status,
+			Reason:      translator.Reason(result.Reason),
+			Owner:       result.Owner,
+			Runbook:     result.Runbook,
+			Duration:    result.Duration,
+		}
+		if !result.CheckedAt.IsZero() {
+			check.CheckedAt = result.CheckedAt.Format(time.RFC3339)
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
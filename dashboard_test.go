@@ -0,0 +1,88 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDashboard(t *testing.T) {
+	h := &Health{status: Up}
+	h.Register("db", func(ctx context.Context) error { return errors.New("<refused>") })
+	h.Evaluate(context.Background())
+
+	rr := httptest.NewRecorder()
+	h.DashboardHandler().ServeHTTP(rr, httptest.NewRequest("GET", "/health/dashboard", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("dashboard should always answer 200, got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+
+	body := rr.Body.String()
+	for _, want := range []string{`<h1 class="DOWN">DOWN</h1>`, "<td>db</td>", "&lt;refused&gt;"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("dashboard is missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestDashboardOwnership(t *testing.T) {
+	h := &Health{status: Up}
+	h.Register("db", func(ctx context.Context) error { return errors.New("refused") },
+		WithOwner("payments-team"),
+		WithRunbook("https://wiki.internal/runbooks/db"),
+		WithDescription("primary Postgres"))
+	h.Evaluate(context.Background())
+
+	if r := h.Results()[0]; r.Owner != "payments-team" || r.Runbook != "https://wiki.internal/runbooks/db" || r.Description != "primary Postgres" {
+		t.Errorf("expected the metadata in the result, got %+v", r)
+	}
+
+	rr := httptest.NewRecorder()
+	h.DashboardHandler().ServeHTTP(rr, httptest.NewRequest("GET", "/health/dashboard", nil))
+
+	body := rr.Body.String()
+	for _, want := range []string{
+		"<td>db<br><small>primary Postgres</small></td>",
+		`<td>payments-team <a href="https://wiki.internal/runbooks/db">runbook</a></td>`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("dashboard is missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestDashboardTranslatedAndGated(t *testing.T) {
+	h := &Health{status: Down, reason: "maintenance"}
+	h.WithTranslator(german).
+		WithDebugLinks(DefaultDebugLinks()).
+		WithAdminAuth(BearerToken("s3cret"))
+
+	get := func(token string) string {
+		r := httptest.NewRequest("GET", "/health/dashboard", nil)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		h.DashboardHandler().ServeHTTP(rr, r)
+		return rr.Body.String()
+	}
+
+	public := get("")
+	if !strings.Contains(public, "GESTÖRT") || !strings.Contains(public, "Wartungsarbeiten") {
+		t.Errorf("dashboard not translated:\n%s", public)
+	}
+	if strings.Contains(public, "/debug/pprof/") {
+		t.Error("debug links shown without auth")
+	}
+
+	if admin := get("s3cret"); !strings.Contains(admin, `<a href="/debug/pprof/">pprof</a>`) {
+		t.Errorf("debug links missing for admin:\n%s", admin)
+	}
+}
@@ -0,0 +1,48 @@
+package health
+
+import (
+	"errors"
+	"net/http"
+)
+
+type degradedError struct {
+	err error
+}
+
+func (e *degradedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *degradedError) Unwrap() error {
+	return e.err
+}
+
+// DegradedError marks err as a non-critical failure: the check is then
+// reported as DEGRADED instead of DOWN, and so is the service unless a
+// check failed. It returns nil for a nil err.
+func DegradedError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &degradedError{err: err}
+}
+
+// isDegraded reports whether err was marked with DegradedError.
+func isDegraded(err error) bool {
+	var degraded *degradedError
+	return errors.As(err, &degraded)
+}
+
+// WithDegradedStatusCode sets the HTTP status code of DEGRADED responses:
+// 200 (the default, zero restores it) keeps the instance in the load
+// balancer, 429 makes it back off like an overloaded one.
+func (h *Health) WithDegradedStatusCode(code int) *Health {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if code == 0 {
+		code = http.StatusOK
+	}
+	h.degradedCode = code
+	return h
+}
@@ -0,0 +1,86 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDegraded(t *testing.T) {
+	h := New(WithJSON(true))
+	h.Register("db", func(ctx context.Context) error { return nil })
+	h.Register("cache", func(ctx context.Context) error {
+		return DegradedError(errors.New("connection refused"))
+	})
+	h.Evaluate(context.Background())
+
+	serve := func() (int, responseBody) {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest("GET", "/health", nil))
+		var body responseBody
+		if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		return rr.Code, body
+	}
+
+	code, body := serve()
+	if code != http.StatusOK || body.Status != string(Degraded) || body.Reason != "cache: connection refused" {
+		t.Errorf("unexpected response %d %+v", code, body)
+	}
+	if len(body.Degraded) != 1 || body.Degraded[0] != "cache" {
+		t.Errorf("expected the degraded checks to be listed, got %v", body.Degraded)
+	}
+
+	h.WithDegradedStatusCode(http.StatusTooManyRequests)
+	if code, _ := serve(); code != http.StatusTooManyRequests {
+		t.Errorf("expected the configured status code, got %d", code)
+	}
+
+	// A failure is worse than a degradation
+	h.Register("queue", func(ctx context.Context) error { return errors.New("unreachable") })
+	h.Evaluate(context.Background())
+	if code, body := serve(); code != http.StatusServiceUnavailable || body.Status != string(Down) {
+		t.Errorf("expected a failure to take the service down, got %d %+v", code, body)
+	}
+
+	if DegradedError(nil) != nil {
+		t.Error("expected DegradedError(nil) to be nil")
+	}
+}
+
+func TestInformational(t *testing.T) {
+	h := New(WithJSON(true))
+	h.Register("db", func(ctx context.Context) error { return nil })
+	h.Register("recommendations", func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}, Informational())
+	results := h.Evaluate(context.Background())
+
+	if r := results[1]; r.Status != Degraded || !r.Informational {
+		t.Errorf("expected the informational check to be degraded, got %+v", r)
+	}
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest("GET", "/health", nil))
+	var body responseBody
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if rr.Code != http.StatusOK || body.Status != string(Degraded) {
+		t.Errorf("expected the service to stay in rotation, got %d %+v", rr.Code, body)
+	}
+	if len(body.Warnings) != 1 || body.Warnings[0] != "recommendations: connection refused" {
+		t.Errorf("expected the failure in the warnings, got %v", body.Warnings)
+	}
+
+	// A critical failure still takes the service down
+	h.Register("db", func(ctx context.Context) error { return errors.New("timeout") })
+	h.Evaluate(context.Background())
+	if status, _, _ := h.overall(); status != Down {
+		t.Errorf("expected a critical failure to take the service down, got %v", status)
+	}
+}
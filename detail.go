@@ -0,0 +1,87 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// Detail is how much health responses tell.
+type Detail int
+
+const (
+	// DetailFull, the default, gives the reason in plain text and every
+	// check's result in the JSON report
+	DetailFull Detail = iota
+	// DetailMinimal only gives the status, so public load balancer probes
+	// don't learn internal dependency names. Requests with ?verbose=1 still
+	// get the full JSON report, see WithDetail.
+	DetailMinimal
+)
+
+// WithDetail sets how much health responses tell by default. Whatever the
+// level, a request with ?verbose=1 gets the full JSON report, with every
+// check's result, duration, timestamp and reason. When WithAdminAuth is set
+// only admin callers can ask for it.
+func (h *Health) WithDetail(level Detail) *Health {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.detail = level
+	return h
+}
+
+// WithDetail sets how much the responses of New's handler tell. See
+// (*Health).WithDetail.
+func WithDetail(level Detail) Option {
+	return func(h *Health) {
+		h.WithDetail(level)
+	}
+}
+
+// verbose reports whether r asks for the full report with ?verbose and is
+// allowed to get it.
+func (h *Health) verbose(r *http.Request) bool {
+	if r == nil || r.URL == nil || r.URL.RawQuery == "" {
+		return false
+	}
+
+	query := r.URL.Query()
+	if !query.Has("verbose") {
+		return false
+	}
+	if v := query.Get("verbose"); v != "" {
+		if on, err := strconv.ParseBool(v); err != nil || !on {
+			return false
+		}
+	}
+
+	h.mutex.RLock()
+	restricted := h.adminAuth != nil
+	h.mutex.RUnlock()
+
+	return !restricted || h.isAdmin(r)
+}
+
+// minimal reports whether r only gets the status.
+func (h *Health) minimal(r *http.Request) bool {
+	h.mutex.RLock()
+	detail := h.detail
+	h.mutex.RUnlock()
+
+	return detail == DetailMinimal && !h.verbose(r)
+}
+
+// renderJSON renders the JSON response for r, the full report unless it
+// only gets the status.
+func (h *Health) renderJSON(r *http.Request) (Status, []byte) {
+	if h.minimal(r) {
+		status, _, _ := h.overall()
+		body, _ := json.Marshal(responseBody{Status: string(status)})
+		return status, body
+	}
+
+	report := h.report(r)
+	body, _ := json.Marshal(report)
+	return Status(report.Status), body
+}
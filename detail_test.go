@@ -0,0 +1,55 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDetail(t *testing.T) {
+	h := New(WithDetail(DetailMinimal))
+	h.Register("internal-db", func(ctx context.Context) error { return errors.New("connection refused") })
+	h.Evaluate(context.Background())
+
+	serve := func(target string, admin bool) (int, string) {
+		r := httptest.NewRequest("GET", target, nil)
+		if admin {
+			r.Header.Set("Authorization", "Bearer secret")
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, r)
+		return rr.Code, rr.Body.String()
+	}
+
+	code, body := serve("/health", false)
+	if code != 503 || body != "DOWN: " {
+		t.Errorf("minimal text response leaks details: %d %q", code, body)
+	}
+
+	h.WithJSON(true)
+	if _, body := serve("/health", false); body != `{"status":"DOWN"}` {
+		t.Errorf("minimal JSON response leaks details: %q", body)
+	}
+
+	h.WithJSON(false)
+	_, body = serve("/health?verbose=1", false)
+	var report responseBody
+	if err := json.Unmarshal([]byte(body), &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Checks) != 1 || report.Checks[0].Reason != "connection refused" {
+		t.Errorf("verbose response should have the full JSON report, got %q", body)
+	}
+
+	// With admin auth only admins get the verbose report
+	h.WithAdminAuth(BearerToken("secret"))
+	if _, body := serve("/health?verbose=1", false); strings.Contains(body, "internal-db") {
+		t.Errorf("verbose response given to a non-admin: %q", body)
+	}
+	if _, body := serve("/health?verbose=true", true); !strings.Contains(body, "internal-db") {
+		t.Errorf("verbose response refused to an admin: %q", body)
+	}
+}
@@ -0,0 +1,48 @@
+package health
+
+import (
+	"context"
+	"maps"
+	"sync"
+)
+
+type detailsKey struct{}
+
+// details collects what a running check reports with SetDetail.
+type details struct {
+	mutex  sync.Mutex
+	values map[string]any
+}
+
+// SetDetail attaches a detail to the result of the check running with ctx,
+// rendered in the check's JSON result. It works whether the check passes or
+// fails, and does nothing when ctx doesn't belong to a running check.
+func SetDetail(ctx context.Context, key string, value any) {
+	d, ok := ctx.Value(detailsKey{}).(*details)
+	if !ok {
+		return
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.values == nil {
+		d.values = make(map[string]any)
+	}
+	d.values[key] = value
+}
+
+// withDetails returns a context collecting the details set by a check.
+func withDetails(ctx context.Context) (context.Context, *details) {
+	d := &details{}
+	return context.WithValue(ctx, detailsKey{}, d), d
+}
+
+// snapshot returns a copy of the details set so far, since a check that
+// ignores its context may keep setting them after its result is taken.
+func (d *details) snapshot() map[string]any {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return maps.Clone(d.values)
+}
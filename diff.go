@@ -0,0 +1,174 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultDiffAgo is how far back DiffHandler compares with by default.
+	defaultDiffAgo = 15 * time.Minute
+	// latencyChangeShare is how much a check's latency must change, as a
+	// share of its older latency, for the check to be reported as changed.
+	latencyChangeShare = 0.5
+)
+
+// DiffReport is what changed between two snapshots.
+type DiffReport struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// Status is set when the overall status changed
+	Status *StatusChange `json:"status,omitempty"`
+	// Checks are the checks in both snapshots whose status changed, or whose
+	// latency changed by half or more, by name
+	Checks []CheckDiff `json:"checks,omitempty"`
+	// Added and Removed name the checks only in the newer, or only in the
+	// older snapshot
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// StatusChange is a change of the overall status.
+type StatusChange struct {
+	From Status `json:"from"`
+	To   Status `json:"to"`
+}
+
+// CheckDiff is how a check changed between two snapshots.
+type CheckDiff struct {
+	Name string `json:"name"`
+	From Status `json:"from"`
+	To   Status `json:"to"`
+	// Reason is the check's reason in the newer snapshot
+	Reason string `json:"reason,omitempty"`
+	// LatencyDelta is how much slower (or faster, when negative) the check
+	// got, see Sample; zero when either latency is unknown
+	LatencyDelta time.Duration `json:"latency_delta,omitempty"`
+}
+
+// Diff compares snapshot a with the newer snapshot b, for instance to post
+// what changed during an incident.
+func Diff(a, b StatusSnapshot) DiffReport {
+	report := DiffReport{From: a.TakenAt, To: b.TakenAt}
+	if a.Status != b.Status {
+		report.Status = &StatusChange{From: a.Status, To: b.Status}
+	}
+
+	before := indexChecks(a)
+	after := indexChecks(b)
+	for name, old := range before {
+		current, ok := after[name]
+		if !ok {
+			report.Removed = append(report.Removed, name)
+			continue
+		}
+		d := CheckDiff{
+			Name:   name,
+			From:   old.Status,
+			To:     current.Status,
+			Reason: current.Reason,
+		}
+		latencyChanged := false
+		if was, is := latencyOf(old), latencyOf(current); was != 0 && is != 0 {
+			d.LatencyDelta = is - was
+			latencyChanged = d.LatencyDelta.Abs() >= time.Duration(latencyChangeShare*float64(was))
+		}
+		if d.From != d.To || latencyChanged {
+			report.Checks = append(report.Checks, d)
+		}
+	}
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			report.Added = append(report.Added, name)
+		}
+	}
+
+	slices.SortFunc(report.Checks, func(a, b CheckDiff) int { return strings.Compare(a.Name, b.Name) })
+	slices.Sort(report.Added)
+	slices.Sort(report.Removed)
+	return report
+}
+
+// indexChecks maps the name of every check of snapshot, grouped or not, to
+// its result.
+func indexChecks(snapshot StatusSnapshot) map[string]CheckResult {
+	checks := make(map[string]CheckResult, len(snapshot.Checks))
+	for _, result := range snapshot.Checks {
+		checks[result.Name] = result
+	}
+	for _, group := range snapshot.Groups {
+		for _, result := range group.Checks {
+			checks[result.Name] = result
+		}
+	}
+	return checks
+}
+
+// latencyOf is the latency measured by the check itself, or else how long
+// it took. It is zero when unknown.
+func latencyOf(result CheckResult) time.Duration {
+	if result.Latency != 0 {
+		return result.Latency
+	}
+	return result.Duration
+}
+
+// snapshotAt rebuilds the health at the given time from the history, and
+// the latencies from the samples (left zero for checks without a sample
+// then). Only the statuses are known that far back, not the reasons.
+func (h *Health) snapshotAt(at time.Time) StatusSnapshot {
+	snapshot := StatusSnapshot{TakenAt: at}
+
+	statuses := h.history.statusesAt(at)
+	snapshot.Status = statuses[""]
+	delete(statuses, "")
+
+	minute := at.Truncate(sampleInterval)
+	for name, status := range statuses {
+		result := CheckResult{Name: name, Status: status, CheckedAt: at}
+		for _, s := range h.Samples(name) {
+			if s.At.Equal(minute) {
+				result.Duration = s.Latency
+			}
+		}
+		snapshot.Checks = append(snapshot.Checks, result)
+	}
+	slices.SortFunc(snapshot.Checks, func(a, b CheckResult) int { return strings.Compare(a.Name, b.Name) })
+	return snapshot
+}
+
+// DiffHandler compares the default handler's health with an earlier one.
+// See (*Health).DiffHandler.
+func DiffHandler() http.Handler {
+	return handler.DiffHandler()
+}
+
+// DiffHandler serves the Diff between the health ?ago (15m by default) and
+// now as JSON, handy to paste in an incident channel:
+//
+//	http.Handle("/health/diff", health.DiffHandler())
+//
+// The earlier health is rebuilt from the history, so it's only as far back
+// as the history goes, and latencies are only known for the last hour.
+func (h *Health) DiffHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ago := defaultDiffAgo
+		if v := r.URL.Query().Get("ago"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				http.Error(w, "ago must be a positive duration such as 30m", http.StatusBadRequest)
+				return
+			}
+			ago = d
+		}
+
+		now := h.snapshot()
+		then := h.snapshotAt(now.TakenAt.Add(-ago))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Diff(then, now))
+	})
+}
@@ -0,0 +1,94 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := StatusSnapshot{
+		Status:  Up,
+		TakenAt: at,
+		Checks: []CheckResult{
+			{Name: "db", Status: Up, Duration: 10 * time.Millisecond},
+			{Name: "cache", Status: Up, Duration: time.Millisecond},
+			{Name: "search", Status: Up, Duration: 100 * time.Millisecond},
+			{Name: "unsampled", Status: Up},
+			{Name: "legacy", Status: Up},
+		},
+	}
+	b := StatusSnapshot{
+		Status:  Down,
+		TakenAt: at.Add(time.Minute),
+		Checks: []CheckResult{
+			// Jitter and unknown latencies aren't changes
+			{Name: "cache", Status: Up, Duration: time.Millisecond + 3*time.Microsecond},
+			{Name: "search", Status: Up, Duration: 300 * time.Millisecond},
+			{Name: "unsampled", Status: Up, Duration: time.Second},
+		},
+		Groups: []GroupReport{{Name: "storage", Checks: []CheckResult{
+			{Name: "db", Status: Down, Reason: "connection refused", Duration: 2 * time.Second},
+			{Name: "queue", Status: Up},
+		}}},
+	}
+
+	want := DiffReport{
+		From:   a.TakenAt,
+		To:     b.TakenAt,
+		Status: &StatusChange{From: Up, To: Down},
+		Checks: []CheckDiff{
+			{Name: "db", From: Up, To: Down, Reason: "connection refused", LatencyDelta: 1990 * time.Millisecond},
+			{Name: "search", From: Up, To: Up, LatencyDelta: 200 * time.Millisecond},
+		},
+		Added:   []string{"queue"},
+		Removed: []string{"legacy"},
+	}
+	if got := Diff(a, b); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+}
+
+func TestDiffHandler(t *testing.T) {
+	clock := &stepClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	h := New(WithClock(clock))
+
+	var dbErr error
+	h.Register("db", func(ctx context.Context) error { return dbErr })
+	h.Evaluate(context.Background())
+
+	clock.advance(20 * time.Minute)
+	dbErr = errors.New("connection refused")
+	h.Register("queue", func(ctx context.Context) error { return nil })
+	h.Evaluate(context.Background())
+
+	rr := httptest.NewRecorder()
+	h.DiffHandler().ServeHTTP(rr, httptest.NewRequest("GET", "/health/diff?ago=10m", nil))
+
+	var report DiffReport
+	if err := json.Unmarshal(rr.Body.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Status == nil || report.Status.From != Up || report.Status.To != Down {
+		t.Errorf("expected the overall status change, got %+v", report.Status)
+	}
+	if len(report.Checks) != 1 || report.Checks[0].Name != "db" || report.Checks[0].To != Down {
+		t.Errorf("expected db's change, got %+v", report.Checks)
+	}
+	if len(report.Added) != 1 || report.Added[0] != "queue" {
+		t.Errorf("expected queue to be new, got %v", report.Added)
+	}
+
+	for _, ago := range []string{"yesterday", "0s"} {
+		rr = httptest.NewRecorder()
+		h.DiffHandler().ServeHTTP(rr, httptest.NewRequest("GET", "/health/diff?ago="+ago, nil))
+		if rr.Code != 400 {
+			t.Errorf("expected 400 for ago=%s, got %d", ago, rr.Code)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Target is a dependency instance found through service discovery.
+type Target struct {
+	// Name is unique among the targets of a Discoverer, and becomes the
+	// name of the target's check
+	Name string
+	// Addr is the instance's "host:port"
+	Addr   string
+	Labels map[string]string
+}
+
+// Discoverer lists the current instances of some dependencies, like the
+// endpoints of labeled Kubernetes services or the instances of a Consul
+// service.
+type Discoverer interface {
+	Discover(ctx context.Context) ([]Target, error)
+}
+
+// Discover keeps checks in sync with service discovery on the default
+// handler. See (*Health).Discover.
+func Discover(ctx context.Context, d Discoverer, interval time.Duration, build func(Target) CheckFunc) error {
+	return handler.Discover(ctx, d, interval, build)
+}
+
+// Discover lists the targets of d immediately and then every interval, in a
+// background goroutine until ctx is done, registering a check built by
+// build for every new target and unregistering the checks of targets that
+// went away. When discovery fails the checks are left as they are. It
+// returns an error, without starting anything, when the interval isn't
+// positive.
+func (h *Health) Discover(ctx context.Context, d Discoverer, interval time.Duration, build func(Target) CheckFunc) error {
+	if interval <= 0 {
+		return fmt.Errorf("health: discovery interval must be positive, got %s", interval)
+	}
+
+	ticker := h.getClock().NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+
+		registered := make(map[string]bool)
+		h.syncDiscovered(ctx, d, build, registered)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				h.syncDiscovered(ctx, d, build, registered)
+			}
+		}
+	}()
+	return nil
+}
+
+// syncDiscovered registers the checks of new targets and unregisters the
+// ones of targets gone since the last sync. registered holds the names of
+// the checks registered by previous syncs.
+func (h *Health) syncDiscovered(ctx context.Context, d Discoverer, build func(Target) CheckFunc, registered map[string]bool) {
+	targets, err := d.Discover(ctx)
+	if err != nil {
+		return
+	}
+
+	current := make(map[string]bool, len(targets))
+	for _, target := range targets {
+		current[target.Name] = true
+		if !registered[target.Name] {
+			h.Register(target.Name, build(target))
+			registered[target.Name] = true
+		}
+	}
+
+	for name := range registered {
+		if !current[name] {
+			h.Unregister(name)
+			delete(registered, name)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/andres-vara/health"
+)
+
+// Consul discovers the instances of a service registered in Consul, through
+// its health API. Instances failing their Consul checks are kept, so their
+// health checks fail rather than silently disappear.
+type Consul struct {
+	// Address is the Consul agent's URL; http://127.0.0.1:8500 if empty
+	Address string
+	// Service is the name of the service
+	Service string
+	// Tag, if set, restricts discovery to instances with the tag
+	Tag string
+	// Token is sent as the ACL token, if set
+	Token string
+	// HTTPClient is used for requests; http.DefaultClient if nil
+	HTTPClient *http.Client
+}
+
+type consulEntry struct {
+	Node struct {
+		Node    string
+		Address string
+	}
+	Service struct {
+		ID      string
+		Address string
+		Port    int
+		Meta    map[string]string
+	}
+}
+
+// Discover implements health.Discoverer. Targets are named after the
+// service and the instance ID.
+func (c *Consul) Discover(ctx context.Context) ([]health.Target, error) {
+	address := c.Address
+	if address == "" {
+		address = "http://127.0.0.1:8500"
+	}
+
+	query := url.Values{}
+	if c.Tag != "" {
+		query.Set("tag", c.Tag)
+	}
+	endpoint := address + "/v1/health/service/" + url.PathEscape(c.Service) + "?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.Token != "" {
+		req.Header.Set("X-Consul-Token", c.Token)
+	}
+
+	var entries []consulEntry
+	if err := getJSON(c.HTTPClient, req, &entries); err != nil {
+		return nil, fmt.Errorf("consul: %w", err)
+	}
+
+	targets := make([]health.Target, 0, len(entries))
+	for _, entry := range entries {
+		// The service address defaults to the node's
+		host := entry.Service.Address
+		if host == "" {
+			host = entry.Node.Address
+		}
+		targets = append(targets, health.Target{
+			Name:   c.Service + "/" + entry.Service.ID,
+			Addr:   net.JoinHostPort(host, strconv.Itoa(entry.Service.Port)),
+			Labels: entry.Service.Meta,
+		})
+	}
+	return targets, nil
+}
+
+// getJSON sends req and decodes its JSON response into v.
+func getJSON(client *http.Client, req *http.Request, v any) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
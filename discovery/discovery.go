@@ -0,0 +1,23 @@
+// Package discovery finds dependency instances through Consul or the
+// Kubernetes API, to keep health checks in sync with the environment:
+//
+//	health.Discover(ctx, &discovery.Kubernetes{LabelSelector: "health.check=true"},
+//		time.Minute, discovery.TCP)
+package discovery
+
+import (
+	"github.com/andres-vara/health"
+	"github.com/andres-vara/health/checks"
+)
+
+// TCP builds a check connecting to the target.
+func TCP(target health.Target) health.CheckFunc {
+	return checks.TCP(target.Addr)
+}
+
+// HTTP returns a builder of checks requesting path on the target.
+func HTTP(path string, opts ...checks.HTTPOption) func(health.Target) health.CheckFunc {
+	return func(target health.Target) health.CheckFunc {
+		return checks.HTTP("http://"+target.Addr+path, opts...)
+	}
+}
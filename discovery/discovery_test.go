@@ -0,0 +1,84 @@
+package discovery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConsul(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/health/service/redis" || r.URL.Query().Get("tag") != "primary" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("X-Consul-Token") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`[
+			{"Node": {"Node": "n1", "Address": "10.0.0.1"}, "Service": {"ID": "redis-1", "Address": "", "Port": 6379}},
+			{"Node": {"Node": "n2", "Address": "10.0.0.2"}, "Service": {"ID": "redis-2", "Address": "10.0.1.2", "Port": 6380, "Meta": {"zone": "b"}}}
+		]`))
+	}))
+	defer server.Close()
+
+	consul := &Consul{Address: server.URL, Service: "redis", Tag: "primary", Token: "secret"}
+	targets, err := consul.Discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(targets) != 2 {
+		t.Fatalf("expected 2 targets, got %+v", targets)
+	}
+	if targets[0].Name != "redis/redis-1" || targets[0].Addr != "10.0.0.1:6379" {
+		t.Errorf("unexpected first target: %+v", targets[0])
+	}
+	if targets[1].Addr != "10.0.1.2:6380" || targets[1].Labels["zone"] != "b" {
+		t.Errorf("unexpected second target: %+v", targets[1])
+	}
+
+	consul.Token = ""
+	if _, err := consul.Discover(context.Background()); err == nil {
+		t.Error("expected an error for a failed request")
+	}
+}
+
+func TestKubernetes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/namespaces/shop/endpoints" || r.URL.Query().Get("labelSelector") != "health.check=true" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"items": [{
+			"metadata": {"name": "payments", "labels": {"health.check": "true"}},
+			"subsets": [{
+				"addresses": [{"ip": "10.1.0.1"}],
+				"notReadyAddresses": [{"ip": "10.1.0.2"}],
+				"ports": [{"name": "metrics", "port": 9090}, {"name": "http", "port": 8080}]
+			}]
+		}]}`))
+	}))
+	defer server.Close()
+
+	k := &Kubernetes{
+		APIServer:     server.URL,
+		Token:         "token",
+		Namespace:     "shop",
+		LabelSelector: "health.check=true",
+		Port:          "http",
+		HTTPClient:    server.Client(),
+	}
+	targets, err := k.Discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(targets) != 2 || targets[0].Name != "payments/10.1.0.1:8080" || targets[1].Addr != "10.1.0.2:8080" {
+		t.Errorf("unexpected targets: %+v", targets)
+	}
+}
@@ -0,0 +1,171 @@
+package discovery
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andres-vara/health"
+)
+
+// serviceAccountDir is where Kubernetes mounts the pod's service account
+const serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// Kubernetes discovers the addresses of the Endpoints objects matching a
+// label selector, through the API server. With its zero value it uses the
+// pod's service account, which needs to be allowed to list endpoints.
+// Addresses that aren't ready are kept, so their health checks fail rather
+// than silently disappear.
+type Kubernetes struct {
+	// APIServer is the API server's URL; the in-cluster one if empty
+	APIServer string
+	// Token authenticates requests; the service account's if empty
+	Token string
+	// Namespace to look in; the pod's if empty
+	Namespace string
+	// LabelSelector picks the Endpoints, like "health.check=true"
+	LabelSelector string
+	// Port is the name of the port to check, when endpoints have several;
+	// the first one if empty
+	Port string
+	// HTTPClient is used for requests; one trusting the service account's
+	// CA if nil
+	HTTPClient *http.Client
+
+	mutex sync.Mutex
+	// client is the default client, built on first use
+	client *http.Client
+}
+
+type endpointsList struct {
+	Items []struct {
+		Metadata struct {
+			Name   string            `json:"name"`
+			Labels map[string]string `json:"labels"`
+		} `json:"metadata"`
+		Subsets []struct {
+			Addresses         []endpointAddress `json:"addresses"`
+			NotReadyAddresses []endpointAddress `json:"notReadyAddresses"`
+			Ports             []struct {
+				Name string `json:"name"`
+				Port int    `json:"port"`
+			} `json:"ports"`
+		} `json:"subsets"`
+	} `json:"items"`
+}
+
+type endpointAddress struct {
+	IP string `json:"ip"`
+}
+
+// Discover implements health.Discoverer. Targets are named after the
+// Endpoints object and the address.
+func (k *Kubernetes) Discover(ctx context.Context) ([]health.Target, error) {
+	apiServer, token, namespace, client, err := k.config()
+	if err != nil {
+		return nil, fmt.Errorf("kubernetes: %w", err)
+	}
+
+	endpoint := apiServer + "/api/v1/namespaces/" + url.PathEscape(namespace) + "/endpoints?" +
+		url.Values{"labelSelector": {k.LabelSelector}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	var list endpointsList
+	if err := getJSON(client, req, &list); err != nil {
+		return nil, fmt.Errorf("kubernetes: %w", err)
+	}
+
+	var targets []health.Target
+	for _, item := range list.Items {
+		for _, subset := range item.Subsets {
+			port := 0
+			for _, p := range subset.Ports {
+				if k.Port == "" || p.Name == k.Port {
+					port = p.Port
+					break
+				}
+			}
+			if port == 0 {
+				continue
+			}
+
+			for _, address := range append(subset.Addresses, subset.NotReadyAddresses...) {
+				addr := net.JoinHostPort(address.IP, strconv.Itoa(port))
+				targets = append(targets, health.Target{
+					Name:   item.Metadata.Name + "/" + addr,
+					Addr:   addr,
+					Labels: item.Metadata.Labels,
+				})
+			}
+		}
+	}
+	return targets, nil
+}
+
+// config fills in the in-cluster defaults.
+func (k *Kubernetes) config() (apiServer, token, namespace string, client *http.Client, err error) {
+	apiServer = k.APIServer
+	if apiServer == "" {
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if host == "" || port == "" {
+			return "", "", "", nil, errors.New("not running in a cluster and no API server given")
+		}
+		apiServer = "https://" + net.JoinHostPort(host, port)
+	}
+
+	token = k.Token
+	if token == "" {
+		data, err := os.ReadFile(serviceAccountDir + "/token")
+		if err != nil {
+			return "", "", "", nil, err
+		}
+		token = strings.TrimSpace(string(data))
+	}
+
+	namespace = k.Namespace
+	if namespace == "" {
+		data, err := os.ReadFile(serviceAccountDir + "/namespace")
+		if err != nil {
+			return "", "", "", nil, err
+		}
+		namespace = strings.TrimSpace(string(data))
+	}
+
+	client, err = k.httpClient()
+	return apiServer, token, namespace, client, err
+}
+
+// httpClient returns the client to use, building the default one trusting
+// the service account's CA once.
+func (k *Kubernetes) httpClient() (*http.Client, error) {
+	if k.HTTPClient != nil {
+		return k.HTTPClient, nil
+	}
+
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if k.client == nil {
+		ca, err := os.ReadFile(serviceAccountDir + "/ca.crt")
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(ca)
+		k.client = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+	}
+	return k.client, nil
+}
@@ -0,0 +1,70 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type fakeDiscoverer struct {
+	targets []Target
+	err     error
+}
+
+func (d *fakeDiscoverer) Discover(ctx context.Context) ([]Target, error) {
+	return d.targets, d.err
+}
+
+func TestSyncDiscovered(t *testing.T) {
+	h := &Health{status: Up}
+	h.Register("static", func(ctx context.Context) error { return nil })
+
+	d := &fakeDiscoverer{targets: []Target{{Name: "redis/1"}, {Name: "redis/2"}}}
+	build := func(target Target) CheckFunc {
+		return func(ctx context.Context) error { return nil }
+	}
+	registered := map[string]bool{}
+
+	names := func() []string {
+		var names []string
+		for _, c := range h.checks {
+			names = append(names, c.name)
+		}
+		return names
+	}
+
+	h.syncDiscovered(context.Background(), d, build, registered)
+	if got := names(); len(got) != 3 {
+		t.Errorf("expected the discovered checks to be registered, got %v", got)
+	}
+
+	// A failed discovery changes nothing
+	d.err = errors.New("consul unreachable")
+	h.syncDiscovered(context.Background(), d, build, registered)
+	if got := names(); len(got) != 3 {
+		t.Errorf("expected the checks to be kept, got %v", got)
+	}
+
+	d.err = nil
+	d.targets = []Target{{Name: "redis/2"}, {Name: "redis/3"}}
+	h.Evaluate(context.Background())
+	h.syncDiscovered(context.Background(), d, build, registered)
+	if got := names(); len(got) != 3 || got[0] != "redis/2" || got[1] != "redis/3" {
+		t.Errorf("expected redis/1 replaced by redis/3, got %v", got)
+	}
+	for _, result := range h.Results() {
+		if result.Name == "redis/1" {
+			t.Error("the result of an unregistered check is still reported")
+		}
+	}
+}
+
+func TestDiscoverInvalidInterval(t *testing.T) {
+	h := New()
+	d := &fakeDiscoverer{targets: []Target{{Name: "redis/1"}}}
+	build := func(Target) CheckFunc { return func(ctx context.Context) error { return nil } }
+
+	if err := h.Discover(context.Background(), d, 0, build); err == nil {
+		t.Error("expected an error for a zero interval")
+	}
+}
@@ -0,0 +1,94 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// drainPollInterval is how often DrainAndWait checks for in-flight requests.
+const drainPollInterval = 10 * time.Millisecond
+
+// TrackInFlight counts the requests in flight through next on the default
+// handler. See (*Health).TrackInFlight.
+func TrackInFlight(next http.Handler) http.Handler {
+	return handler.TrackInFlight(next)
+}
+
+// TrackInFlight is a middleware counting the application requests in flight
+// through next, for DrainAndWait to wait on.
+func (h *Health) TrackInFlight(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		done := h.RequestStarted()
+		defer done()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequestStarted counts a request or unit of work in flight on the default
+// handler. See (*Health).RequestStarted.
+func RequestStarted() (done func()) {
+	return handler.RequestStarted()
+}
+
+// RequestStarted counts a request or unit of work in flight until done is
+// called, for work TrackInFlight doesn't see, such as queue consumers.
+func (h *Health) RequestStarted() (done func()) {
+	h.inFlight.Add(1)
+	return func() {
+		h.inFlight.Add(-1)
+	}
+}
+
+// InFlight returns the number of requests in flight on the default handler.
+func InFlight() int64 {
+	return handler.InFlight()
+}
+
+// InFlight returns the number of requests in flight.
+func (h *Health) InFlight() int64 {
+	return h.inFlight.Load()
+}
+
+// DrainAndWait drains the default handler. See
+// (*Health).DrainAndWait.
+func DrainAndWait(ctx context.Context) error {
+	return handler.DrainAndWait(ctx)
+}
+
+// DrainAndWait is the first step of a graceful shutdown: it takes the
+// service DOWN so load balancers stop sending it traffic, then waits for the
+// requests in flight to finish, polling on the handler's clock. Middlewares
+// such as ShttpMiddleware reject new requests from then on, until the status
+// is set again. It returns the context's error when the deadline comes
+// first, with requests still in flight.
+func (h *Health) DrainAndWait(ctx context.Context) error {
+	h.mutex.Lock()
+	h.status = Down
+	h.reason = "draining"
+	h.draining = true
+	h.observeLocked()
+	h.mutex.Unlock()
+
+	ticker := h.getClock().NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for h.inFlight.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+		}
+	}
+	return nil
+}
+
+// isDraining reports whether DrainAndWait was called since the status was
+// last set.
+func (h *Health) isDraining() bool {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	return h.draining
+}
@@ -0,0 +1,45 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDrainAndWait(t *testing.T) {
+	h := &Health{status: Up}
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	app := h.TrackInFlight(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}))
+	go app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	<-started
+
+	if n := h.InFlight(); n != 1 {
+		t.Fatalf("expected 1 request in flight, got %d", n)
+	}
+
+	// The deadline comes before the request finishes
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := h.DrainAndWait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the deadline to be reached, got %v", err)
+	}
+	if status, reason, _ := h.overall(); status != Down || reason != "draining" {
+		t.Errorf("expected the service to be DOWN while draining, got %s %q", status, reason)
+	}
+
+	close(release)
+	if err := h.DrainAndWait(context.Background()); err != nil {
+		t.Errorf("expected the drain to complete, got %v", err)
+	}
+	if n := h.InFlight(); n != 0 {
+		t.Errorf("expected no request in flight, got %d", n)
+	}
+}
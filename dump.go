@@ -0,0 +1,121 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// dumpTransitions is how many of the latest transitions a dump lists.
+const dumpTransitions = 20
+
+// DumpOnSignal dumps the default handler's health on signals. See
+// (*Health).DumpOnSignal.
+func DumpOnSignal(ctx context.Context, w io.Writer, sigs ...os.Signal) {
+	handler.DumpOnSignal(ctx, w, sigs...)
+}
+
+// DumpOnSignal writes a health dump to w (os.Stderr if nil) every time the
+// process receives one of sigs, until ctx is done, the way the Go runtime
+// dumps goroutines on SIGQUIT but without killing the process:
+//
+//	go health.DumpOnSignal(ctx, nil, syscall.SIGUSR2)
+//
+// and then kill -USR2 <pid>.
+func (h *Health) DumpOnSignal(ctx context.Context, w io.Writer, sigs ...os.Signal) {
+	if w == nil {
+		w = os.Stderr
+	}
+
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, sigs...)
+	defer signal.Stop(received)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-received:
+			_ = h.WriteDump(w)
+		}
+	}
+}
+
+// WriteDump writes the detailed health of the handler in a human readable
+// form: the overall status, every check with its details, the latest
+// transitions and the runtime vitals.
+func (h *Health) WriteDump(w io.Writer) error {
+	status, reason, results := h.overall()
+	transitions := h.history.Transitions()
+	stats := readRuntimeStats()
+
+	h.mutex.RLock()
+	runID := h.runID
+	h.mutex.RUnlock()
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	p := func(format string, args ...any) {
+		fmt.Fprintf(tw, format, args...)
+	}
+
+	p("health dump at %s\n", h.getClock().Now().UTC().Format(time.RFC3339))
+	p("status: %s", status)
+	if reason != "" {
+		p(": %s", reason)
+	}
+	p("\n")
+	if runID != "" {
+		p("run: %s\n", runID)
+	}
+
+	p("\nchecks (%d):\n", len(results))
+	for _, r := range results {
+		name := r.Name
+		if r.Group != "" {
+			name = r.Group + "/" + r.Name
+		}
+		p("  %s\t%s\t%v\t%s\n", name, r.Status, r.Duration.Round(time.Microsecond), r.Reason)
+		if r.Owner != "" || r.Runbook != "" {
+			p("    owner: %s, runbook: %s\n", r.Owner, r.Runbook)
+		}
+
+		keys := make([]string, 0, len(r.Details))
+		for key := range r.Details {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			p("    %s=%v\n", key, r.Details[key])
+		}
+	}
+
+	if len(transitions) > dumpTransitions {
+		transitions = transitions[len(transitions)-dumpTransitions:]
+	}
+	p("\ntransitions (latest %d):\n", len(transitions))
+	for _, t := range transitions {
+		check := t.Check
+		if check == "" {
+			check = "(overall)"
+		}
+		p("  %s\t%s\t%s -> %s\t%s\n", t.At.UTC().Format(time.RFC3339), check, t.From, t.To, t.Reason)
+	}
+
+	p("\nruntime:\n")
+	p("  go\t%s\n", stats.GoVersion)
+	p("  goroutines\t%d\n", stats.Goroutines)
+	p("  heap\t%d bytes in use, %d reserved\n", stats.HeapAlloc, stats.HeapSys)
+	p("  gc\t%d cycles, %v paused\n", stats.NumGC, stats.GCPauseTotal)
+	if stats.OpenFDs > 0 {
+		p("  open fds\t%d\n", stats.OpenFDs)
+	}
+	p("%s\n", strings.Repeat("-", 40))
+
+	return tw.Flush()
+}
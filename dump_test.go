@@ -0,0 +1,39 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWriteDump(t *testing.T) {
+	h := New()
+	h.SetHealthy()
+	h.Register("db", func(ctx context.Context) error {
+		SetDetail(ctx, "pool", 10)
+		return errors.New("connection refused")
+	})
+	h.Evaluate(context.Background())
+	h.Evaluate(context.Background())
+
+	var buf bytes.Buffer
+	if err := h.WriteDump(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	dump := buf.String()
+	for _, want := range []string{
+		"status: DOWN: db: connection refused",
+		"checks (1):",
+		"pool=10",
+		"(overall)",
+		"UP -> DOWN",
+		"goroutines",
+	} {
+		if !strings.Contains(dump, want) {
+			t.Errorf("expected %q in the dump:\n%s", want, dump)
+		}
+	}
+}
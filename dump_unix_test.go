@@ -0,0 +1,65 @@
+//go:build unix
+
+package health
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mutex sync.Mutex
+	buf   bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.buf.String()
+}
+
+func TestDumpOnSignal(t *testing.T) {
+	// Keep the signal from killing the test binary before DumpOnSignal
+	// starts listening
+	guard := make(chan os.Signal, 1)
+	signal.Notify(guard, syscall.SIGUSR2)
+	defer signal.Stop(guard)
+
+	h := New()
+	var buf syncBuffer
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		h.DumpOnSignal(ctx, &buf, syscall.SIGUSR2)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(buf.String(), "health dump at") {
+		if time.Now().After(deadline) {
+			t.Fatal("no dump on signal")
+		}
+		_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	<-done
+}
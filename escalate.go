@@ -0,0 +1,137 @@
+package health
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// DefaultEscalations are the delays after which sustained downtime is
+// escalated, unless others are given to Escalate.
+var DefaultEscalations = []time.Duration{5 * time.Minute, 30 * time.Minute}
+
+// Escalation is a tiered notification about an outage of the overall status
+// (with an empty Check) or of a check.
+type Escalation struct {
+	Check  string
+	Status Status
+	Reason string
+	// Level is 0 when the status goes down, then 1, 2... as each escalation
+	// delay is reached; recoveries keep the level reached, to tell whoever
+	// was paged
+	Level int
+	// Recovered is set on the final notification, once the status is UP
+	// (or DEGRADED) again
+	Recovered bool
+	// Since is when the outage started and Downtime how long it has lasted,
+	// its total duration once recovered
+	Since    time.Time
+	Downtime time.Duration
+}
+
+// EscalationNotifier is told about outages as they start, escalate and
+// recover.
+type EscalationNotifier interface {
+	NotifyEscalation(ctx context.Context, e Escalation) error
+}
+
+// EscalationFunc adapts a function to the EscalationNotifier interface.
+type EscalationFunc func(ctx context.Context, e Escalation) error
+
+// NotifyEscalation calls f(ctx, e).
+func (f EscalationFunc) NotifyEscalation(ctx context.Context, e Escalation) error {
+	return f(ctx, e)
+}
+
+// Escalate escalates the default handler's outages. See
+// (*Health).Escalate.
+func Escalate(ctx context.Context, n EscalationNotifier, after ...time.Duration) {
+	handler.Escalate(ctx, n, after...)
+}
+
+// Escalate tells n about outages until ctx is done: at level 0 as soon as a
+// status goes down (DEGRADED doesn't count), at the next level each time it
+// has stayed down for one of the after delays (DefaultEscalations if none),
+// and with Recovered set and the total downtime once it is UP (or DEGRADED)
+// again. Durations are computed from the transitions' timestamps. Outages
+// are tracked through silences, but escalations during one aren't sent.
+func (h *Health) Escalate(ctx context.Context, n EscalationNotifier, after ...time.Duration) {
+	if len(after) == 0 {
+		after = DefaultEscalations
+	}
+	after = slices.Clone(after)
+	slices.Sort(after)
+
+	transitions := make(chan Transition, maxPendingNotifications)
+	q := newQueue(maxPendingNotifications, func(t Transition) {
+		select {
+		case transitions <- t:
+		case <-ctx.Done():
+		}
+	})
+	h.mutex.Lock()
+	h.escalations = append(h.escalations, q)
+	h.mutex.Unlock()
+	defer func() {
+		h.mutex.Lock()
+		h.escalations = slices.DeleteFunc(h.escalations, func(other *queue[Transition]) bool { return other == q })
+		h.mutex.Unlock()
+	}()
+
+	// Escalations are checked often enough to be late by a tenth of the
+	// shortest delay at most.
+	ticker := h.getClock().NewTicker(max(after[0]/10, time.Millisecond))
+	defer ticker.Stop()
+
+	outages := make(map[string]*Escalation)
+	send := func(e Escalation, at time.Time) {
+		h.mutex.Lock()
+		silenced := h.silencedLocked(Transition{Check: e.Check, To: e.Status, Reason: e.Reason, At: at})
+		h.mutex.Unlock()
+		if silenced {
+			return
+		}
+
+		sendCtx, cancel := context.WithTimeout(ctx, defaultNotifyTimeout)
+		_ = n.NotifyEscalation(sendCtx, e)
+		cancel()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case t := <-transitions:
+			outage, down := outages[t.Check]
+			switch {
+			case isAvailable(t.To) && down:
+				outage.Recovered = true
+				outage.Status, outage.Reason = t.To, t.Reason
+				outage.Downtime = t.At.Sub(outage.Since)
+				delete(outages, t.Check)
+				send(*outage, t.At)
+			case !isAvailable(t.To) && !down:
+				outage = &Escalation{Check: t.Check, Status: t.To, Reason: t.Reason, Since: t.At}
+				outages[t.Check] = outage
+				send(*outage, t.At)
+			case !isAvailable(t.To):
+				outage.Status, outage.Reason = t.To, t.Reason
+			}
+
+		case <-ticker.C():
+			now := h.getClock().Now()
+			for _, outage := range outages {
+				downtime := now.Sub(outage.Since)
+				if outage.Level >= len(after) || downtime < after[outage.Level] {
+					continue
+				}
+				for outage.Level < len(after) && downtime >= after[outage.Level] {
+					outage.Level++
+				}
+				outage.Downtime = downtime
+				send(*outage, now)
+			}
+		}
+	}
+}
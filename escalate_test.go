@@ -0,0 +1,124 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestEscalate(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := &stepClock{now: start}
+	h := New(WithClock(clock))
+	h.SetHealthy()
+
+	got := make(chan Escalation, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go h.Escalate(ctx, EscalationFunc(func(ctx context.Context, e Escalation) error {
+		got <- e
+		return nil
+	}), 30*time.Millisecond, 10*time.Millisecond)
+
+	next := func() Escalation {
+		t.Helper()
+		select {
+		case e := <-got:
+			return e
+		case <-time.After(time.Second):
+			t.Fatal("no escalation")
+			return Escalation{}
+		}
+	}
+
+	// Wait for the escalation to be set up before going down
+	waitEscalations(h, 1)
+
+	h.SetUnhealthy("disk full")
+	if e := next(); e.Level != 0 || e.Recovered || e.Reason != "disk full" || !e.Since.Equal(start) {
+		t.Errorf("unexpected first notification %+v", e)
+	}
+
+	clock.advance(15 * time.Millisecond)
+	if e := next(); e.Level != 1 || e.Downtime != 15*time.Millisecond {
+		t.Errorf("unexpected first escalation %+v", e)
+	}
+	clock.advance(time.Minute)
+	if e := next(); e.Level != 2 {
+		t.Errorf("unexpected second escalation %+v", e)
+	}
+
+	clock.advance(time.Minute)
+	h.SetHealthy()
+	e := next()
+	if !e.Recovered || e.Status != Up || e.Downtime != 2*time.Minute+15*time.Millisecond {
+		t.Errorf("unexpected recovery %+v", e)
+	}
+
+	select {
+	case e := <-got:
+		t.Errorf("unexpected notification %+v", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestEscalateThroughSilence(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := &stepClock{now: start}
+	h := New(WithClock(clock))
+	h.SetHealthy()
+
+	got := make(chan Escalation, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.Escalate(ctx, EscalationFunc(func(ctx context.Context, e Escalation) error {
+			got <- e
+			return nil
+		}), 10*time.Millisecond)
+	}()
+	waitEscalations(h, 1)
+
+	h.SetUnhealthy("disk full")
+	select {
+	case e := <-got:
+		if e.Level != 0 {
+			t.Errorf("unexpected first notification %+v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no notification")
+	}
+
+	// The recovery happens during a silence
+	h.SilenceBetween(start.Add(time.Millisecond), start.Add(time.Second), nil)
+	clock.advance(2 * time.Millisecond)
+	h.SetHealthy()
+
+	// but still ends the outage, so nothing is escalated once it expires
+	clock.advance(time.Minute)
+	select {
+	case e := <-got:
+		t.Errorf("unexpected notification %+v", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	<-done
+	if len(h.escalations) != 0 {
+		t.Error("escalation left behind once done")
+	}
+}
+
+// waitEscalations waits for n calls to Escalate to be set up.
+func waitEscalations(h *Health, n int) {
+	for {
+		h.mutex.RLock()
+		added := len(h.escalations) >= n
+		h.mutex.RUnlock()
+		if added {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -34,7 +35,7 @@ func main() {
 	// Start server
 	server := &http.Server{
 		Addr:    ":8080",
-		Handler: router,
+		Handler: health.TrackInFlight(router),
 	}
 
 	// Start the server in a goroutine
@@ -58,11 +59,13 @@ func main() {
 
 	log.Println("Shutting down server...")
 	
-	// Simulate unhealthy state during shutdown
-	health.SetUnhealthy("Server is shutting down")
-	
-	// Give existing connections time to complete
-	time.Sleep(time.Second)
+	// Report unhealthy and let in-flight requests complete
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := health.DrainAndWait(ctx); err != nil {
+		log.Printf("Requests still in flight: %d", health.InFlight())
+	}
+	_ = server.Shutdown(ctx)
 	
 	log.Println("Server stopped")
 } 
\ No newline at end of file
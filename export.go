@@ -0,0 +1,67 @@
+package health
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ExportFormat is a format History.Export can write.
+type ExportFormat string
+
+const (
+	// ExportCSV writes a header line followed by one line per transition
+	ExportCSV ExportFormat = "csv"
+	// ExportJSONLines writes one JSON object per transition and line
+	ExportJSONLines ExportFormat = "jsonl"
+)
+
+// Export writes the transitions recorded at or after since, oldest first, so
+// an incident timeline can be attached to a postmortem straight from the
+// instance. A zero since exports the whole history.
+func (hist *History) Export(w io.Writer, format ExportFormat, since time.Time) error {
+	var transitions []Transition
+	for _, t := range hist.Transitions() {
+		if !t.At.Before(since) {
+			transitions = append(transitions, t)
+		}
+	}
+
+	switch format {
+	case ExportCSV:
+		return exportCSV(w, transitions)
+	case ExportJSONLines:
+		return exportJSONLines(w, transitions)
+	default:
+		return fmt.Errorf("health: unknown export format %q", format)
+	}
+}
+
+func exportCSV(w io.Writer, transitions []Transition) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"at", "check", "from", "to", "reason"}); err != nil {
+		return err
+	}
+
+	for _, t := range transitions {
+		record := []string{t.At.Format(time.RFC3339Nano), t.Check, string(t.From), string(t.To), t.Reason}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func exportJSONLines(w io.Writer, transitions []Transition) error {
+	encoder := json.NewEncoder(w)
+	for _, t := range transitions {
+		if err := encoder.Encode(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package health
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportCSV(t *testing.T) {
+	h, clock, set := newToggleHandler()
+	set(true)
+	clock.advance(time.Minute)
+	set(false)
+
+	var buf bytes.Buffer
+	if err := h.History().Export(&buf, ExportCSV, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "at,check,from,to,reason\n" +
+		"2024-01-01T00:01:00Z,db,UP,DOWN,refused\n" +
+		"2024-01-01T00:01:00Z,,UP,DOWN,db: refused\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestExportJSONLinesSince(t *testing.T) {
+	h, clock, set := newToggleHandler()
+	set(true)
+	clock.advance(time.Minute)
+	set(false)
+	clock.advance(time.Minute)
+	since := clock.Now()
+	set(true)
+
+	var buf bytes.Buffer
+	if err := h.History().Export(&buf, ExportJSONLines, since); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected the 2 transitions since the recovery, got %d:\n%s", len(lines), buf.String())
+	}
+
+	var transition Transition
+	if err := json.Unmarshal([]byte(lines[0]), &transition); err != nil {
+		t.Fatal(err)
+	}
+	if transition.Check != "db" || transition.To != Up || !transition.At.Equal(since) {
+		t.Errorf("unexpected transition: %+v", transition)
+	}
+}
+
+func TestExportUnknownFormat(t *testing.T) {
+	h, _, _ := newToggleHandler()
+	if err := h.History().Export(&bytes.Buffer{}, "xml", time.Time{}); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
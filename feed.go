@@ -0,0 +1,88 @@
+package health
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// feedSize is the number of transitions, newest first, in the Atom feed.
+const feedSize = 50
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID      string     `xml:"id"`
+	Title   string     `xml:"title"`
+	Updated string     `xml:"updated"`
+	Author  atomAuthor `xml:"author"`
+	Content string     `xml:"content,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+// FeedHandler serves the default handler's transitions as an Atom feed. See
+// (*Health).FeedHandler.
+func FeedHandler() http.Handler {
+	return handler.FeedHandler()
+}
+
+// FeedHandler serves the latest transitions as an Atom feed, newest first,
+// so teams can follow a service's health changes in a feed reader or a
+// chat RSS integration without any webhook plumbing. The feed is named
+// after the request's host.
+func (h *Health) FeedHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		transitions := h.history.Transitions()
+
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		self := scheme + "://" + r.Host + r.URL.RequestURI()
+
+		feed := atomFeed{
+			ID:      self,
+			Title:   "Health of " + r.Host,
+			Updated: h.getClock().Now().UTC().Format(time.RFC3339),
+			Link:    atomLink{Rel: "self", Href: self},
+		}
+		if len(transitions) > 0 {
+			feed.Updated = transitions[len(transitions)-1].At.UTC().Format(time.RFC3339)
+		}
+
+		for i := len(transitions) - 1; i >= 0 && len(feed.Entries) < feedSize; i-- {
+			t := transitions[i]
+			subject := t.Check
+			if subject == "" {
+				subject = "service"
+			}
+			feed.Entries = append(feed.Entries, atomEntry{
+				ID:      fmt.Sprintf("%s#%d-%s", self, t.At.UnixNano(), t.Check),
+				Title:   fmt.Sprintf("%s is %s (was %s)", subject, t.To, t.From),
+				Updated: t.At.UTC().Format(time.RFC3339Nano),
+				Author:  atomAuthor{Name: r.Host},
+				Content: t.Reason,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+		_, _ = w.Write([]byte(xml.Header))
+		_ = xml.NewEncoder(w).Encode(feed)
+	})
+}
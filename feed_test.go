@@ -0,0 +1,41 @@
+package health
+
+import (
+	"encoding/xml"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFeedHandler(t *testing.T) {
+	h := &Health{status: Up}
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	h.history.observe("db", Up, "", start)
+	h.history.observe("db", Down, "connection refused", start.Add(time.Minute))
+	h.history.observe("", Up, "", start)
+	h.history.observe("", Down, "db: connection refused", start.Add(2*time.Minute))
+
+	rr := httptest.NewRecorder()
+	h.FeedHandler().ServeHTTP(rr, httptest.NewRequest("GET", "http://orders.internal/health/feed", nil))
+
+	if ct := rr.Header().Get("Content-Type"); ct != "application/atom+xml; charset=utf-8" {
+		t.Errorf("unexpected content type %q", ct)
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal(rr.Body.Bytes(), &feed); err != nil {
+		t.Fatal(err)
+	}
+	if feed.Title != "Health of orders.internal" || feed.Updated != "2024-01-01T12:02:00Z" {
+		t.Errorf("unexpected feed %q updated %q", feed.Title, feed.Updated)
+	}
+	if len(feed.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(feed.Entries))
+	}
+	if feed.Entries[0].Title != "service is DOWN (was UP)" || feed.Entries[1].Title != "db is DOWN (was UP)" {
+		t.Errorf("unexpected entries %+v", feed.Entries)
+	}
+	if feed.Entries[1].Content != "connection refused" {
+		t.Errorf("expected the reason as content, got %q", feed.Entries[1].Content)
+	}
+}
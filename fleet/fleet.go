@@ -0,0 +1,186 @@
+// Package fleet runs a tiny health aggregation service: instances push their
+// status to it with health.PushTo, and it tracks the freshness of every
+// instance and serves a fleet summary with per-instance drill-down, a
+// self-hosted status backend built from the health package's primitives:
+//
+//	server := fleet.NewServer(30 * time.Second)
+//	http.ListenAndServe(":9000", server)
+//
+//	// in every instance
+//	go health.PushTo(ctx, "http://fleet:9000/instances/"+hostname, 10*time.Second)
+//
+// Endpoints:
+//
+//   - POST /instances/{name} records the status of an instance
+//   - GET /instances/{name} returns its last report
+//   - GET /instances (or /) returns the fleet summary
+package fleet
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+// Instance is the last report of an instance.
+type Instance struct {
+	Name       string               `json:"name"`
+	Status     health.Status        `json:"status"`
+	Reason     string               `json:"reason,omitempty"`
+	Checks     []health.CheckResult `json:"checks,omitempty"`
+	ReportedAt time.Time            `json:"reported_at"`
+	// Stale is set when the instance hasn't reported within the max age;
+	// its status is then DOWN
+	Stale bool `json:"stale,omitempty"`
+}
+
+// Summary is the health of the whole fleet.
+type Summary struct {
+	// Status is UP when every instance is UP and fresh, DEGRADED when some
+	// are degraded but none is worse, DOWN otherwise
+	Status health.Status `json:"status"`
+	// Counts is the number of instances in every status
+	Counts    map[health.Status]int `json:"counts"`
+	Stale     int                   `json:"stale"`
+	Instances []Instance            `json:"instances"`
+}
+
+// Server receives pushed statuses and serves the fleet's health.
+type Server struct {
+	maxAge time.Duration
+	mux    *http.ServeMux
+	// now is the time source, a field so tests can move it
+	now func() time.Time
+
+	mutex     sync.RWMutex
+	instances map[string]Instance
+}
+
+// NewServer returns a server considering instances stale, and DOWN, when
+// they haven't reported for maxAge.
+func NewServer(maxAge time.Duration) *Server {
+	s := &Server{maxAge: maxAge, now: time.Now, instances: make(map[string]Instance)}
+
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("POST /instances/{name}", s.receive)
+	s.mux.HandleFunc("GET /instances/{name}", s.serveInstance)
+	s.mux.HandleFunc("DELETE /instances/{name}", s.forget)
+	s.mux.HandleFunc("GET /instances", s.serveSummary)
+	s.mux.HandleFunc("GET /{$}", s.serveSummary)
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Summary returns the fleet's health, instances sorted by name.
+func (s *Server) Summary() Summary {
+	s.mutex.RLock()
+	instances := make([]Instance, 0, len(s.instances))
+	for _, instance := range s.instances {
+		instances = append(instances, s.freshness(instance))
+	}
+	s.mutex.RUnlock()
+
+	slices.SortFunc(instances, func(a, b Instance) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	summary := Summary{Status: health.Up, Counts: make(map[health.Status]int), Instances: instances}
+	for _, instance := range instances {
+		summary.Counts[instance.Status]++
+		if instance.Stale {
+			summary.Stale++
+		}
+		switch {
+		case instance.Status == health.Degraded:
+			if summary.Status == health.Up {
+				summary.Status = health.Degraded
+			}
+		case instance.Status != health.Up:
+			summary.Status = health.Down
+		}
+	}
+	return summary
+}
+
+// Instance returns the last report of the named instance.
+func (s *Server) Instance(name string) (Instance, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	instance, ok := s.instances[name]
+	return s.freshness(instance), ok
+}
+
+// freshness marks instance stale, and DOWN, when its report is too old.
+func (s *Server) freshness(instance Instance) Instance {
+	if s.now().Sub(instance.ReportedAt) > s.maxAge {
+		instance.Stale = true
+		instance.Status = health.Down
+		instance.Reason = "no report since " + instance.ReportedAt.UTC().Format(time.RFC3339)
+	}
+	return instance
+}
+
+func (s *Server) receive(w http.ResponseWriter, r *http.Request) {
+	status, err := health.ParseStatus(r.Header.Get("Content-Type"), r.Body, 0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	name := r.PathValue("name")
+	s.mutex.Lock()
+	s.instances[name] = Instance{
+		Name:       name,
+		Status:     status.Status,
+		Reason:     status.Reason,
+		Checks:     status.Checks,
+		ReportedAt: s.now(),
+	}
+	s.mutex.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) forget(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	delete(s.instances, r.PathValue("name"))
+	s.mutex.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) serveInstance(w http.ResponseWriter, r *http.Request) {
+	instance, ok := s.Instance(r.PathValue("name"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, instance.Status, instance)
+}
+
+func (s *Server) serveSummary(w http.ResponseWriter, r *http.Request) {
+	summary := s.Summary()
+	writeJSON(w, summary.Status, summary)
+}
+
+// writeJSON writes v with the status code matching status, so the fleet
+// endpoints can be probed like health endpoints.
+func writeJSON(w http.ResponseWriter, status health.Status, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if status == health.Up || status == health.Degraded {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(v)
+}
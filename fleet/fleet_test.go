@@ -0,0 +1,109 @@
+package fleet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+func TestServer(t *testing.T) {
+	now := time.Now()
+	server := NewServer(time.Minute)
+	server.now = func() time.Time { return now }
+
+	post := func(name, body string) int {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/instances/"+name, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		server.ServeHTTP(rr, req)
+		return rr.Code
+	}
+	get := func(path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, httptest.NewRequest("GET", path, nil))
+		return rr
+	}
+
+	if code := post("orders-1", `{"status":"UP"}`); code != http.StatusNoContent {
+		t.Fatalf("unexpected push status %d", code)
+	}
+	if code := post("orders-2", `not a report`); code != http.StatusBadRequest {
+		t.Errorf("expected an invalid report to be rejected, got %d", code)
+	}
+	if rr := get("/instances"); rr.Code != http.StatusOK {
+		t.Errorf("expected a healthy fleet to answer 200, got %d", rr.Code)
+	}
+
+	now = now.Add(30 * time.Second)
+	post("orders-2", `{"status":"DOWN","reason":"db: timeout","checks":[{"name":"db","status":"DOWN"}]}`)
+
+	rr := get("/instances/orders-2")
+	var instance Instance
+	if err := json.Unmarshal(rr.Body.Bytes(), &instance); err != nil {
+		t.Fatal(err)
+	}
+	if rr.Code != http.StatusServiceUnavailable || instance.Reason != "db: timeout" || len(instance.Checks) != 1 {
+		t.Errorf("unexpected drill-down %d %+v", rr.Code, instance)
+	}
+	if rr := get("/instances/missing"); rr.Code != http.StatusNotFound {
+		t.Errorf("expected an unknown instance to be a 404, got %d", rr.Code)
+	}
+
+	// orders-1 stops reporting
+	now = now.Add(45 * time.Second)
+	summary := server.Summary()
+	if summary.Status != health.Down || summary.Stale != 1 || summary.Counts[health.Down] != 2 {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+	if !summary.Instances[0].Stale || summary.Instances[0].Name != "orders-1" {
+		t.Errorf("expected orders-1 to be stale, got %+v", summary.Instances[0])
+	}
+}
+
+func TestPush(t *testing.T) {
+	server := NewServer(time.Minute)
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	defer health.Reset()
+
+	h := health.Handle()
+	h.Register("db", func(ctx context.Context) error { return errors.New("refused") })
+	h.Evaluate(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.PushTo(ctx, ts.URL+"/instances/orders-1", time.Hour)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if instance, ok := server.Instance("orders-1"); ok {
+			if instance.Status != health.Down || len(instance.Checks) != 1 {
+				t.Errorf("unexpected pushed instance %+v", instance)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("nothing pushed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+}
+
+func TestPushInvalidInterval(t *testing.T) {
+	h := health.New()
+	if err := h.PushTo(context.Background(), "http://fleet.invalid/instances/orders-1", 0); err == nil {
+		t.Error("expected an error for a zero interval")
+	}
+}
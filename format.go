@@ -0,0 +1,32 @@
+package health
+
+import "net/http"
+
+// Format is the body format of health responses.
+type Format int
+
+const (
+	// FormatText is the terse "STATUS: reason" plain text
+	FormatText Format = iota
+	// FormatJSON is the JSON report
+	FormatJSON
+)
+
+// FormatHandler serves the default handler's health in format. See
+// (*Health).FormatHandler.
+func FormatHandler(format Format) http.Handler {
+	return handler.FormatHandler(format)
+}
+
+// FormatHandler serves the health like the handler itself, but always in
+// format, whatever WithJSON sets. Routes can then answer in different
+// formats from the same checks without affecting each other:
+//
+//	mux.Handle("/health", h.FormatHandler(health.FormatText))
+//	mux.Handle("/health/json", h.FormatHandler(health.FormatJSON))
+func (h *Health) FormatHandler(format Format) http.Handler {
+	useJSON := format == FormatJSON
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.serve(w, r, useJSON)
+	})
+}
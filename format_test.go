@@ -0,0 +1,48 @@
+package health
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFormatHandler(t *testing.T) {
+	h := New()
+	text := h.FormatHandler(FormatText)
+	jsonHandler := h.FormatHandler(FormatJSON)
+
+	// The handler's own format doesn't affect the others
+	h.WithJSON(true)
+
+	rr := httptest.NewRecorder()
+	text.ServeHTTP(rr, httptest.NewRequest("GET", "/health", nil))
+	if rr.Body.String() != "UP: " {
+		t.Errorf("expected plain text, got %q", rr.Body)
+	}
+
+	h.WithJSON(false)
+
+	rr = httptest.NewRecorder()
+	jsonHandler.ServeHTTP(rr, httptest.NewRequest("GET", "/health/json", nil))
+	if rr.Header().Get("Content-Type") != "application/json" || !strings.HasPrefix(rr.Body.String(), `{"status":"UP"`) {
+		t.Errorf("expected JSON, got %q", rr.Body)
+	}
+}
+
+func TestHealthHandlerKeepsItsFormat(t *testing.T) {
+	defer handler.WithJSON(handler.jsonFormat())
+	Reset()
+
+	handler.WithJSON(false)
+	serve := HealthHandler()
+	handler.WithJSON(true)
+
+	rr := httptest.NewRecorder()
+	if err := serve(context.Background(), rr, httptest.NewRequest("GET", "/health", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if rr.Body.String() != "UP: " {
+		t.Errorf("expected the format HealthHandler was built with, got %q", rr.Body)
+	}
+}
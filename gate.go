@@ -0,0 +1,79 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// errGateClosed is reported by a gate that hasn't been opened yet
+var errGateClosed = errors.New("waiting to be opened")
+
+// Gate is a push-style check: it keeps the service DOWN until the
+// application opens it, for instance once caches or models are preloaded.
+type Gate struct {
+	clock   func() Clock
+	created time.Time
+
+	mutex   sync.Mutex
+	open    bool
+	maxWait time.Duration
+}
+
+// NewGate registers a gate on the default handler. See
+// (*Health).NewGate.
+func NewGate(name string) *Gate {
+	return handler.NewGate(name)
+}
+
+// NewGate registers a check named name that fails until the returned gate
+// is opened.
+func (h *Health) NewGate(name string) *Gate {
+	g := &Gate{clock: h.getClock, created: h.getClock().Now()}
+	h.Register(name, g.check)
+	return g
+}
+
+// WithMaxWait stops the gate from blocking readiness once d has passed
+// since it was created: it is then degraded until opened, instead of keeping the service DOWN forever.
+func (g *Gate) WithMaxWait(d time.Duration) *Gate {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.maxWait = d
+	return g
+}
+
+// Open opens the gate, letting its check pass.
+func (g *Gate) Open() {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.open = true
+}
+
+// IsOpen reports whether the gate was opened.
+func (g *Gate) IsOpen() bool {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	return g.open
+}
+
+func (g *Gate) check(ctx context.Context) error {
+	g.mutex.Lock()
+	open, maxWait := g.open, g.maxWait
+	g.mutex.Unlock()
+
+	if open {
+		return nil
+	}
+
+	waited := g.clock().Now().Sub(g.created)
+	if maxWait > 0 && waited >= maxWait {
+		return DegradedError(fmt.Errorf("not opened after %v", maxWait))
+	}
+	return errGateClosed
+}
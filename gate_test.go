@@ -0,0 +1,40 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGate(t *testing.T) {
+	h := &Health{status: Up}
+	warm := h.NewGate("cache-warm")
+
+	h.Evaluate(context.Background())
+	if status, reason, _ := h.overall(); status != Down || reason != "cache-warm: waiting to be opened" {
+		t.Errorf("expected the closed gate to keep the service DOWN, got %v %q", status, reason)
+	}
+
+	warm.Open()
+	h.Evaluate(context.Background())
+	if status, _, _ := h.overall(); status != Up || !warm.IsOpen() {
+		t.Errorf("expected the open gate to pass, got %v", status)
+	}
+}
+
+func TestGateMaxWait(t *testing.T) {
+	clock := &stepClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	h := &Health{status: Up}
+	h.WithClock(clock)
+	h.NewGate("models-loaded").WithMaxWait(time.Minute)
+
+	if results := h.Evaluate(context.Background()); results[0].Status != Down {
+		t.Errorf("expected the gate to block before its max wait, got %v", results[0].Status)
+	}
+
+	clock.advance(time.Minute)
+	results := h.Evaluate(context.Background())
+	if results[0].Status != Degraded || results[0].Reason != "not opened after 1m0s" {
+		t.Errorf("expected the gate to be degraded, got %v %q", results[0].Status, results[0].Reason)
+	}
+}
@@ -0,0 +1,75 @@
+package health
+
+import (
+	"runtime"
+	"runtime/metrics"
+	"sync"
+)
+
+// Gauge samples a numeric measurement, such as a queue depth or a pool's
+// utilization.
+type Gauge func() float64
+
+var (
+	gaugesMutex sync.RWMutex
+	gauges      = map[string]Gauge{
+		"mem.heap_bytes":     runtimeMetric("/memory/classes/heap/objects:bytes"),
+		"mem.sys_bytes":      runtimeMetric("/memory/classes/total:bytes"),
+		"runtime.goroutines": func() float64 { return float64(runtime.NumGoroutine()) },
+	}
+)
+
+// runtimeMetric is a gauge reading a runtime metric. Unlike
+// runtime.ReadMemStats, reading metrics doesn't stop the world, so every
+// expression check can sample memory gauges each cycle.
+func runtimeMetric(name string) Gauge {
+	return func() float64 {
+		sample := []metrics.Sample{{Name: name}}
+		metrics.Read(sample)
+		if sample[0].Value.Kind() != metrics.KindUint64 {
+			return 0
+		}
+		return float64(sample[0].Value.Uint64())
+	}
+}
+
+// RegisterGauge exposes a gauge by name to the expression checks of every
+// handler, such as those of the healthcel module. The package provides
+// mem.heap_bytes, mem.sys_bytes and runtime.goroutines.
+func RegisterGauge(name string, gauge Gauge) {
+	gaugesMutex.Lock()
+	defer gaugesMutex.Unlock()
+
+	gauges[name] = gauge
+}
+
+// RegisterGauge exposes a gauge to this handler's expression checks only,
+// taking precedence over a global gauge of the same name.
+func (h *Health) RegisterGauge(name string, gauge Gauge) *Health {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.gauges == nil {
+		h.gauges = make(map[string]Gauge)
+	}
+	h.gauges[name] = gauge
+	return h
+}
+
+// ReadGauge samples a gauge by name, looking on the handler first, then
+// among the global gauges. It reports false for an unknown gauge.
+func (h *Health) ReadGauge(name string) (float64, bool) {
+	h.mutex.RLock()
+	gauge, ok := h.gauges[name]
+	h.mutex.RUnlock()
+
+	if !ok {
+		gaugesMutex.RLock()
+		gauge, ok = gauges[name]
+		gaugesMutex.RUnlock()
+	}
+	if !ok {
+		return 0, false
+	}
+	return gauge(), true
+}
@@ -0,0 +1,27 @@
+package health
+
+import "testing"
+
+func TestReadGauge(t *testing.T) {
+	h := New()
+	h.RegisterGauge("queue.depth", func() float64 { return 10 })
+
+	if v, ok := h.ReadGauge("queue.depth"); !ok || v != 10 {
+		t.Errorf("unexpected handler gauge: %v %v", v, ok)
+	}
+	if v, ok := h.ReadGauge("mem.heap_bytes"); !ok || v <= 0 {
+		t.Errorf("expected the built-in heap gauge, got %v %v", v, ok)
+	}
+	if _, ok := h.ReadGauge("nope.gauge"); ok {
+		t.Error("expected an unknown gauge not to be found")
+	}
+
+	// Handler gauges take precedence over global ones
+	h.RegisterGauge("runtime.goroutines", func() float64 { return -1 })
+	if v, _ := h.ReadGauge("runtime.goroutines"); v != -1 {
+		t.Errorf("expected the handler's gauge, got %v", v)
+	}
+	if v, _ := New().ReadGauge("runtime.goroutines"); v <= 0 {
+		t.Errorf("expected the global gauge on another handler, got %v", v)
+	}
+}
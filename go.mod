@@ -2,7 +2,6 @@ module github.com/andres-vara/health
 
 go 1.24.0
 
-require (
-	github.com/andres-vara/shttp v0.0.1 // indirect
-	github.com/andres-vara/slogr v0.0.3 // indirect
-)
+require github.com/andres-vara/shttp v0.0.1
+
+require github.com/andres-vara/slogr v0.0.3 // indirect
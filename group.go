@@ -0,0 +1,45 @@
+package health
+
+// GroupReport is a section of the JSON report holding the checks of a group
+// declared with WithGroup.
+type GroupReport struct {
+	Name string `json:"name"`
+	// Status is DOWN when any check of the group failed
+	Status Status        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// groupResults splits results, sorted by group then name, into the
+// ungrouped ones and a section per group. Without groups it returns results
+// as they are.
+func groupResults(results []CheckResult) ([]CheckResult, []GroupReport) {
+	// Ungrouped checks sort first
+	i := 0
+	for i < len(results) && results[i].Group == "" {
+		i++
+	}
+	if i == len(results) {
+		return results, nil
+	}
+
+	ungrouped := results[:i]
+	if len(ungrouped) == 0 {
+		ungrouped = nil
+	}
+
+	var groups []GroupReport
+	for _, result := range results[i:] {
+		if len(groups) == 0 || groups[len(groups)-1].Name != result.Group {
+			groups = append(groups, GroupReport{Name: result.Group, Status: Up})
+		}
+		group := &groups[len(groups)-1]
+		group.Checks = append(group.Checks, result)
+		if result.Failed() {
+			group.Status = Down
+		} else if result.Status == Degraded && group.Status == Up {
+			group.Status = Degraded
+		}
+	}
+
+	return ungrouped, groups
+}
@@ -0,0 +1,70 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGroupedReport(t *testing.T) {
+	h := &Health{status: Up, useJSON: true}
+	h.Register("s3", func(ctx context.Context) error { return errors.New("unreachable") }, WithGroup("storage"))
+	h.Register("queue", func(ctx context.Context) error { return nil })
+	h.Register("disk", func(ctx context.Context) error { return nil }, WithGroup("storage"))
+	h.Register("db", func(ctx context.Context) error { return nil }, WithGroup("database"))
+	h.Register("auth", func(ctx context.Context) error { return nil })
+	h.Evaluate(context.Background())
+
+	// Whatever the registration order, results are sorted by group then name
+	var names []string
+	for _, result := range h.Results() {
+		names = append(names, result.Group+"/"+result.Name)
+	}
+	if got := strings.Join(names, " "); got != "/auth /queue database/db storage/disk storage/s3" {
+		t.Errorf("unexpected order: %s", got)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+
+	var body responseBody
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Checks) != 2 || body.Checks[0].Name != "auth" {
+		t.Errorf("unexpected ungrouped checks: %+v", body.Checks)
+	}
+	if len(body.Groups) != 2 {
+		t.Fatalf("expected 2 groups, got %+v", body.Groups)
+	}
+	if g := body.Groups[0]; g.Name != "database" || g.Status != Up || len(g.Checks) != 1 {
+		t.Errorf("unexpected database group: %+v", g)
+	}
+	if g := body.Groups[1]; g.Name != "storage" || g.Status != Down || len(g.Checks) != 2 {
+		t.Errorf("unexpected storage group: %+v", g)
+	}
+
+	// Identical states render identically
+	again := httptest.NewRecorder()
+	h.ServeHTTP(again, httptest.NewRequest("GET", "/health", nil))
+	if again.Body.String() != rec.Body.String() {
+		t.Error("rendering the same state twice gave different bodies")
+	}
+
+	page := httptest.NewRecorder()
+	h.DashboardHandler().ServeHTTP(page, httptest.NewRequest("GET", "/", nil))
+	if !strings.Contains(page.Body.String(), "<h2>storage: ") {
+		t.Errorf("dashboard has no storage section:\n%s", page.Body.String())
+	}
+}
+
+func TestGroupResultsWithoutGroups(t *testing.T) {
+	results := []CheckResult{{Name: "a"}, {Name: "b"}}
+	checks, groups := groupResults(results)
+	if len(checks) != 2 || groups != nil {
+		t.Errorf("expected the results untouched, got %+v %+v", checks, groups)
+	}
+}
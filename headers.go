@@ -0,0 +1,111 @@
+package health
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// WithRetryAfter sets the Retry-After delay sent with 503 and 429
+// responses, so clients and load balancers back off before probing again.
+// Zero, the default, derives it from the interval given to Start, if any (or
+// a second for 429); a negative delay disables the header.
+func (h *Health) WithRetryAfter(d time.Duration) *Health {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.retryAfter = d
+	return h
+}
+
+// overloadRetryAfter is the Retry-After of 429 responses when neither
+// WithRetryAfter nor Start gave one, since shedding load is meant to be brief
+const overloadRetryAfter = time.Second
+
+// statusCode maps a status to the HTTP status code of health responses.
+func (h *Health) statusCode(status Status) int {
+	switch status {
+	case Up:
+		return http.StatusOK
+	case Degraded:
+		h.mutex.RLock()
+		defer h.mutex.RUnlock()
+
+		if h.degradedCode != 0 {
+			return h.degradedCode
+		}
+		return http.StatusOK
+	case Overloaded:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusServiceUnavailable
+	}
+}
+
+// WithStatusHeader makes health responses, and the responses going through
+// StatusHeaders, carry the overall status in the named header, and when it
+// last changed (RFC 3339) in the same header suffixed with "-Changed". Edge
+// proxies can then route on health without parsing bodies. An empty name
+// disables the headers.
+func (h *Health) WithStatusHeader(name string) *Health {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.statusHeader = name
+	return h
+}
+
+// StatusHeaders wraps next so that all its responses carry the headers set
+// with WithStatusHeader.
+func (h *Health) StatusHeaders(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.writeStatusHeader(w.Header())
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeStatusHeader stamps the overall status and when it last changed, if
+// WithStatusHeader was set.
+func (h *Health) writeStatusHeader(header http.Header) {
+	h.mutex.RLock()
+	name := h.statusHeader
+	var status Status
+	if name != "" {
+		status, _, _ = h.aggregate()
+	}
+	h.mutex.RUnlock()
+
+	if name == "" {
+		return
+	}
+
+	header.Set(name, string(status))
+	if changed := h.history.changedAt(""); !changed.IsZero() {
+		header.Set(name+"-Changed", changed.UTC().Format(time.RFC3339))
+	}
+}
+
+// writeHeaders sets the headers describing the health state on a response
+// with the given status code.
+func (h *Health) writeHeaders(header http.Header, statusCode int) {
+	h.writeStatusHeader(header)
+
+	if statusCode != http.StatusServiceUnavailable && statusCode != http.StatusTooManyRequests {
+		return
+	}
+
+	h.mutex.RLock()
+	retryAfter := h.retryAfter
+	if retryAfter == 0 {
+		retryAfter = h.interval
+	}
+	h.mutex.RUnlock()
+
+	if retryAfter == 0 && statusCode == http.StatusTooManyRequests {
+		retryAfter = overloadRetryAfter
+	}
+
+	if retryAfter > 0 {
+		header.Set("Retry-After", strconv.Itoa(seconds(retryAfter)))
+	}
+}
@@ -0,0 +1,84 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	h := &Health{status: Down}
+
+	retryAfter := func() string {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+		return rec.Header().Get("Retry-After")
+	}
+
+	if got := retryAfter(); got != "" {
+		t.Errorf("expected no Retry-After by default, got %q", got)
+	}
+
+	// Derived from the scheduling interval
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	h.Start(ctx, 30*time.Second)
+	if got := retryAfter(); got != "30" {
+		t.Errorf("expected the interval as Retry-After, got %q", got)
+	}
+
+	h.WithRetryAfter(1500 * time.Millisecond)
+	if got := retryAfter(); got != "2" {
+		t.Errorf("expected the configured Retry-After rounded up, got %q", got)
+	}
+
+	h.WithRetryAfter(-1)
+	if got := retryAfter(); got != "" {
+		t.Errorf("expected Retry-After disabled, got %q", got)
+	}
+
+	// Never sent while UP
+	h.WithRetryAfter(time.Minute)
+	h.mutex.Lock()
+	h.status = Up
+	h.mutex.Unlock()
+	if got := retryAfter(); got != "" {
+		t.Errorf("expected no Retry-After while UP, got %q", got)
+	}
+}
+
+func TestStatusHeader(t *testing.T) {
+	h, clock, set := newToggleHandler()
+
+	rec := httptest.NewRecorder()
+	set(true)
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+	if got := rec.Header().Get("X-Health-Status"); got != "" {
+		t.Errorf("expected no status header by default, got %q", got)
+	}
+
+	h.WithStatusHeader("X-Health-Status")
+	clock.advance(time.Minute)
+	set(false)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+	if got := rec.Header().Get("X-Health-Status"); got != "DOWN" {
+		t.Errorf("unexpected status header: %q", got)
+	}
+	if got := rec.Header().Get("X-Health-Status-Changed"); got != "2024-01-01T00:01:00Z" {
+		t.Errorf("unexpected change header: %q", got)
+	}
+
+	// Application responses going through the middleware get them too
+	app := h.StatusHeaders(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest("GET", "/orders", nil))
+	if rec.Code != http.StatusTeapot || rec.Header().Get("X-Health-Status") != "DOWN" {
+		t.Errorf("unexpected middleware response: %d %v", rec.Code, rec.Header())
+	}
+}
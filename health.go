@@ -2,9 +2,11 @@ package health
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
 	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
 
 	"github.com/andres-vara/shttp"
 )
@@ -23,7 +25,21 @@ type Status string
 var (
 	Up Status = "UP"
 	Down Status = "DOWN"
-	handler  = &healthHandler{
+	// NotEvaluated is reported for checks that could not run within the
+	// evaluation deadline.
+	NotEvaluated Status = "NOT_EVALUATED"
+	// TimedOut is reported for checks that were still pending or running
+	// when the evaluation timeout expired.
+	TimedOut Status = "TIMED_OUT"
+	// Overloaded is reported when the service is shedding load: it answers
+	// 429 rather than 503, so retry policies back off instead of treating the
+	// instance as broken.
+	Overloaded Status = "OVERLOADED"
+	// Degraded is reported when something non-critical fails (a cache, a
+	// slow dependency): the service still works, so by default it answers
+	// 200 and stays in the load balancer.
+	Degraded Status = "DEGRADED"
+	handler  = &Health{
 		status: Up,
 		useJSON: false,
 	}
@@ -32,23 +48,120 @@ var (
 type responseBody struct {
 	Status string `json:"status"`
 	Reason string `json:"reason,omitempty"`
+	Checks []CheckResult `json:"checks,omitempty"`
+	Groups []GroupReport `json:"groups,omitempty"`
+	// Degraded names the degraded checks
+	Degraded []string `json:"degraded,omitempty"`
+	// Warnings are the "name: reason" of the degraded informational checks
+	Warnings []string `json:"warnings,omitempty"`
+	// RunID identifies the last evaluation cycle
+	RunID string `json:"run_id,omitempty"`
+
+	Availability *AvailabilityReport `json:"availability,omitempty"`
+	Runtime      *RuntimeStats       `json:"runtime,omitempty"`
+	Requests     *RequestStats       `json:"requests,omitempty"`
+	Debug        map[string]string   `json:"debug,omitempty"`
 }
 
-type healthHandler struct {
+// Health is a health handler with its own status, checks, history and
+// endpoints. The package-level functions operate on a default one, returned
+// by Handle; New makes independent ones, for several servers or tenants in
+// one process.
+type Health struct {
 	status Status
 	reason string
 
+	checks   []namedCheck
+	results  []CheckResult
+	// samples are the recent per-minute samples of every check
+	samples  map[string][]Sample
+	liveness []livenessProbe
+	// runID identifies the last evaluation cycle
+	runID    string
+	onDemand time.Duration
+
+	providers map[string]ProviderFactory
+	gauges    map[string]Gauge
+
+	evaluationTimeout time.Duration
+	// maxConcurrency is how many checks run at once, one if zero
+	maxConcurrency int
+	// interval is the one given to Start, zero when not scheduled
+	interval   time.Duration
+	retryAfter time.Duration
+	// detail is how much responses tell by default
+	detail Detail
+	// degradedCode is the HTTP status code of DEGRADED responses, 200 if
+	// zero
+	degradedCode int
+
+	statusHeader string
+
+	h2c bool
+
+	clock Clock
+	// clockStart is when the clock was set, for the uptime; processStart
+	// is used for the real clock
+	clockStart time.Time
+
+	maxReasonLength int
+	translator      Translator
+	textTemplate    *template.Template
+
+	history History
+	// probes are the sources of recent health requests
+	probes probeLog
+
+	includeRuntime bool
+
+	adminAuth  func(r *http.Request) bool
+	debugLinks map[string]string
+
+	aggregator Aggregator
+
+	notifiers []*queue[Transition]
+	sinks     []*queue[Event]
+	silences  []*silence
+	// escalations get every transition, silenced or not, for Escalate to
+	// track outages through silences
+	escalations []*queue[Transition]
+
+	// inFlight counts the application requests in flight, for draining
+	inFlight atomic.Int64
+	// draining is set by DrainAndWait until the status is set again
+	draining bool
+	// running counts the scheduler goroutines and the checks running, for
+	// Stop to wait on, and stops cancels the schedulers
+	running goroutines
+	stops   []context.CancelFunc
+	// generation counts the resets, for evaluations spanning one to drop
+	// their results rather than record them for checks gone
+	generation uint64
+
+	// started is set once the service has been seen available (UP or
+	// DEGRADED), by an evaluation or by the startup probe
+	started bool
+
 	useJSON bool
 	mutex sync.RWMutex
 }
 
 // ServeHTTP implements the http.Handler interface for standard HTTP servers
-func (h *healthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	statusCode, body, useJSON := h.getStatus()
+func (h *Health) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, h.jsonFormat())
+}
+
+// serve answers r in JSON or plain text.
+func (h *Health) serve(w http.ResponseWriter, r *http.Request, useJSON bool) {
+	h.recordProbe(r)
+	h.evaluateOnDemand(r.Context(), h.detailedSelection(r, useJSON))
+
+	statusCode, body, useJSON := h.render(r, useJSON)
 
 	if useJSON {
 		w.Header().Set("Content-Type", "application/json")
 	}
+	h.writeHeaders(w.Header(), statusCode)
 
 	w.WriteHeader(statusCode)
 
@@ -56,21 +169,30 @@ func (h *healthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 // HealthHandler returns a handler compatible with shttp.Handler interface
-// for use with the shttp package. This uses the default format (plain text or JSON)
-// based on the current settings of the health handler.
+// for use with the shttp package. It answers in the format (plain text or
+// JSON) the health handler has when HealthHandler is called, so configuring
+// another route's format later doesn't affect it.
 func HealthHandler() shttp.Handler {
+	useJSON := handler.jsonFormat()
+
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		handler.recordProbe(r)
+
+		// Run the checks first when on-demand evaluation is enabled
+		handler.evaluateOnDemand(ctx, handler.detailedSelection(r, useJSON))
+
 		// Get status information
-		statusCode, body, useJSON := handler.getStatus()
+		statusCode, body, useJSON := handler.render(r, useJSON)
 
 		// Set appropriate content type
 		if useJSON {
 			w.Header().Set("Content-Type", "application/json")
 		}
+		handler.writeHeaders(w.Header(), statusCode)
 
 		// Forward any request ID from context to response headers for traceability
-		if requestID, ok := ctx.Value("request_id").(string); ok && requestID != "" {
-			w.Header().Set("X-Request-ID", requestID)
+		if id := requestID(ctx); id != "" {
+			w.Header().Set(RequestIDHeader, id)
 		}
 
 		// Set status code and write response
@@ -85,118 +207,362 @@ func HealthHandler() shttp.Handler {
 // regardless of the current handler configuration.
 func JSONHealthHandler() shttp.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		handler.recordProbe(r)
+
+		// Run the checks first when on-demand evaluation is enabled
+		handler.evaluateOnDemand(ctx, parseSelection(r))
+
 		// Get the current status but force JSON format
-		handler.mutex.RLock()
-		status := handler.status
-		reason := handler.reason
-		handler.mutex.RUnlock()
-		
-		// Create JSON response
-		body, _ := json.Marshal(responseBody{
-			Status: string(status),
-			Reason: reason,
-		})
+		status, body := handler.renderJSON(r)
 		
 		// Set appropriate headers
 		w.Header().Set("Content-Type", "application/json")
 		
 		// Forward any request ID from context
-		if requestID, ok := ctx.Value("request_id").(string); ok && requestID != "" {
-			w.Header().Set("X-Request-ID", requestID)
+		if id := requestID(ctx); id != "" {
+			w.Header().Set(RequestIDHeader, id)
 		}
 		
 		// Set status code
-		statusCode := http.StatusOK
-		if status == Down {
-			statusCode = http.StatusServiceUnavailable
-		}
+		code := handler.statusCode(status)
+		handler.writeHeaders(w.Header(), code)
 		
-		w.WriteHeader(statusCode)
+		w.WriteHeader(code)
 		_, _ = w.Write(body)
 		
 		return nil
 	}
 }
 
-func (h *healthHandler) GetResponseStatusCodeAndBody() (int, []byte) {
-	statusCode, body, _ := h.getStatus()
+func (h *Health) GetResponseStatusCodeAndBody() (int, []byte) {
+	statusCode, body, _ := h.getStatus(nil)
 	return statusCode, body
 }
 
-func (h *healthHandler) getStatus() (int, []byte, bool) {
+// getStatus renders the response for r, which may be nil when there's no
+// request (and thus no admin caller).
+func (h *Health) getStatus(r *http.Request) (int, []byte, bool) {
+	return h.render(r, h.jsonFormat())
+}
+
+// render is getStatus in the given format. It reports whether the body is
+// JSON, which ?verbose asks for whatever the format.
+func (h *Health) render(r *http.Request, useJSON bool) (int, []byte, bool) {
 	var status Status
-	var reason string
 	var body []byte
-	var useJSON bool
-	var statusCode int
+
+	switch {
+	case useJSON || h.verbose(r):
+		// Asking for the verbose report gets it in JSON whatever the format
+		status, body = h.renderJSON(r)
+		useJSON = true
+	case h.minimal(r):
+		status, _, _ = h.overall()
+		body = append([]byte(status), ": "...)
+	default:
+		status, body = h.plainText()
+	}
+
+	return h.statusCode(status), body, useJSON
+}
+
+// report builds the JSON response body for r, restricted to the checks it
+// selects. Admin-only sections are only included when r passes the admin
+// auth.
+func (h *Health) report(r *http.Request) responseBody {
+	status, reason, results := h.overallOf(parseSelection(r))
 
 	h.mutex.RLock()
-	status = h.status
-	reason = h.reason
-	useJSON = h.useJSON
+	includeRuntime := h.includeRuntime
+	runID := h.runID
 	h.mutex.RUnlock()
 
-	if useJSON {
-		body, _ = json.Marshal(responseBody{
-			Status: string(status),
-			Reason: reason,
-		})
-	} else {
-		body = []byte(string(status) + ": " + reason)
+	report := responseBody{
+		Status: string(status),
+		Reason: reason,
+		RunID:  runID,
+		Availability: h.availabilityReport(),
+	}
+	report.Checks, report.Groups = groupResults(results)
+	for _, result := range results {
+		if result.Status == Degraded {
+			report.Degraded = append(report.Degraded, result.Name)
+			if result.Informational {
+				report.Warnings = append(report.Warnings, result.Name+": "+result.Reason)
+			}
+		}
+	}
+	if includeRuntime {
+		report.Runtime = readRuntimeStats()
+	}
+	if h.isAdmin(r) {
+		report.Debug = h.getDebugLinks()
+	}
+	if h.isAdmin(r) || h.verbose(r) {
+		report.Requests = h.requestStats()
 	}
 
-	if status == Up {
-		statusCode = http.StatusOK
-	} else {
-		statusCode = http.StatusServiceUnavailable
+	return report
+}
+
+// plainText renders the terse "STATUS: reason" body with a single
+// allocation, since it's what load balancers hit on every probe. A text
+// template, when set, takes over.
+func (h *Health) plainText() (Status, []byte) {
+	h.mutex.RLock()
+	tmpl := h.textTemplate
+	h.mutex.RUnlock()
+
+	if tmpl != nil {
+		if status, body, ok := h.templatedText(tmpl); ok {
+			return status, body
+		}
+	}
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	if h.translator != nil {
+		return h.translatedText()
+	}
+
+	limit := h.reasonLimit()
+
+	status, reason, failed := h.aggregate()
+	if failed == nil {
+		reason = truncate(reason, limit)
+		body := make([]byte, 0, len(status)+2+len(reason))
+		body = append(body, status...)
+		body = append(body, ": "...)
+		return status, append(body, reason...)
+	}
+
+	// The limit applies to the combined "name: reason"
+	if limit >= 0 {
+		limit = max(0, limit-len(failed.Name)-2)
 	}
+	reason = truncate(failed.Reason, limit)
+	body := make([]byte, 0, len(status)+4+len(failed.Name)+len(reason))
+	body = append(body, status...)
+	body = append(body, ": "...)
+	body = append(body, failed.Name...)
+	body = append(body, ": "...)
+	return status, append(body, reason...)
+}
 
-	return statusCode, body, useJSON
+// overall combines the manually set status with the results of the last
+// check evaluation. With the default aggregation a failing or timed out check
+// takes the service down even when the manual status is UP; checks that were
+// not evaluated are ignored.
+func (h *Health) overall() (Status, string, []CheckResult) {
+	return h.overallOf(selection{})
 }
 
-func Handle() *healthHandler {
+// overallOf is overall restricted to the selected checks, as if the others
+// weren't registered.
+func (h *Health) overallOf(sel selection) (Status, string, []CheckResult) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	limit := h.reasonLimit()
+
+	selected := h.results
+	if sel.active() {
+		selected = make([]CheckResult, 0, len(h.results))
+		for _, result := range h.results {
+			if sel.includes(result.Name) {
+				selected = append(selected, result)
+			}
+		}
+	}
+
+	status, reason, failed := h.aggregateOf(selected)
+	if failed != nil {
+		reason = failed.Name + ": " + failed.Reason
+	}
+	reason = truncate(reason, limit)
+
+	var results []CheckResult
+	if len(selected) > 0 {
+		results = make([]CheckResult, len(selected))
+		copy(results, selected)
+		for i := range results {
+			results[i].Reason = truncate(results[i].Reason, limit)
+			results[i].Details = truncateDetails(results[i].Details, limit)
+		}
+	}
+
+	return status, reason, results
+}
+
+// aggregate computes the overall status with the handler's Aggregator,
+// without allocating when it doesn't. failed is the check that took the
+// service down, if any, in which case the reason comes from it. It must be
+// called with the mutex held.
+func (h *Health) aggregate() (Status, string, *CheckResult) {
+	return h.aggregateOf(h.results)
+}
+
+// aggregateOf is aggregate over the given results instead of the last ones.
+func (h *Health) aggregateOf(results []CheckResult) (Status, string, *CheckResult) {
+	if h.status != Up {
+		return h.status, h.reason, nil
+	}
+
+	aggregator := h.aggregator
+	if aggregator == nil {
+		aggregator = worstOf{}
+	}
+	if status, failed := aggregator.Aggregate(results); status != Up {
+		return status, "", failed
+	}
+
+	return h.status, h.reason, nil
+}
+
+// Handle returns the default handler, which the package-level functions
+// operate on.
+func Handle() *Health {
 	return handler
 }
 
 func GetStatus() Status {
-	handler.mutex.RLock()
-	defer handler.mutex.RUnlock()
-
-	return handler.status
+	return handler.GetStatus()
 }
 
 func SetStatus(status Status) {
-	handler.mutex.Lock()
-	defer handler.mutex.Unlock()
-
-	handler.status = status
+	handler.SetStatus(status)
 }
 
 func SetReason(reason string) {
-	handler.mutex.Lock()
-	defer handler.mutex.Unlock()
-
-	handler.reason = reason
+	handler.SetReason(reason)
 }
 
 func GetReason() string {
-	handler.mutex.RLock()
-	defer handler.mutex.RUnlock()
-
-	return handler.reason
+	return handler.GetReason()
 }
 
 func SetHealthy() {
-	SetStatus(Up)
-	SetReason("")
+	handler.SetHealthy()
 }
 
 func SetUnhealthy(reason string) {
-	SetStatus(Down)
-	SetReason(reason)
+	handler.SetUnhealthy(reason)
+}
+
+// GetStatus returns the status set on the handler, regardless of its checks.
+func (h *Health) GetStatus() Status {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	return h.status
+}
+
+// SetStatus sets the handler's status, which overrides its checks unless
+// it is UP.
+func (h *Health) SetStatus(status Status) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.status = status
+	h.observeLocked()
 }
 
-func (h *healthHandler) WithJSON(v bool) *healthHandler {
+// SetReason sets the reason reported with the handler's status.
+func (h *Health) SetReason(reason string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.reason = reason
+	h.observeLocked()
+}
+
+// GetReason returns the reason set on the handler.
+func (h *Health) GetReason() string {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	return h.reason
+}
+
+// SetHealthy sets the handler UP with no reason.
+func (h *Health) SetHealthy() {
+	h.setStatusAndReason(Up, "")
+}
+
+// SetUnhealthy sets the handler DOWN with the given reason.
+func (h *Health) SetUnhealthy(reason string) {
+	h.setStatusAndReason(Down, reason)
+}
+
+// setStatusAndReason changes both at once, so no transition is recorded with
+// the new status and the old reason.
+func (h *Health) setStatusAndReason(status Status, reason string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.status = status
+	h.reason = reason
+	h.draining = false
+	h.observeLocked()
+}
+
+// Reset restores the default handler to a pristine UP state. See
+// (*Health).Reset.
+func Reset() {
+	handler.Reset()
+}
+
+// Reset atomically clears the handler's state: status and reason go back to
+// UP with no reason, and registered checks, their results and the transition
+// history are dropped, along with the probe log, notifiers, sinks, silences
+// and escalations. Background evaluation started with Start is cancelled,
+// and evaluations still running when Reset returns drop their results.
+// Configuration such as the response format or the clock is kept. It is
+// mostly useful for isolating tests that share the default handler.
+func (h *Health) Reset() {
+	h.mutex.Lock()
+	stops := h.stops
+	h.stops = nil
+	h.interval = 0
+	h.generation++
+	h.status = Up
+	h.reason = ""
+	h.checks = nil
+	h.results = nil
+	h.samples = nil
+	h.liveness = nil
+	h.notifiers = nil
+	h.sinks = nil
+	h.silences = nil
+	h.escalations = nil
+	h.runID = ""
+	h.started = false
+	h.draining = false
+	h.history.reset()
+	h.mutex.Unlock()
+
+	h.probes.reset()
+	for _, cancel := range stops {
+		cancel()
+	}
+}
+
+// WithJSON makes the handler answer in JSON rather than plain text. Handlers
+// built with FormatHandler or HealthHandler keep the format they were built
+// with.
+func (h *Health) WithJSON(v bool) *Health {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
 	h.useJSON = v
 	return h
 }
+
+// jsonFormat reports whether the handler answers in JSON.
+func (h *Health) jsonFormat() bool {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	return h.useJSON
+}
@@ -10,8 +10,8 @@ import (
 )
 
 func TestHealthHandler(t *testing.T) {
-	// Reset health status before each test
-	SetHealthy()
+	// Reset health state before each test
+	Reset()
 
 	tests := []struct {
 		name           string
@@ -125,8 +125,8 @@ func TestHealthHandler(t *testing.T) {
 }
 
 func TestSHTTPHealthHandler(t *testing.T) {
-	// Reset health status before each test
-	SetHealthy()
+	// Reset health state before each test
+	Reset()
 
 	tests := []struct {
 		name           string
@@ -224,8 +224,8 @@ func TestSHTTPHealthHandler(t *testing.T) {
 }
 
 func TestSHTTPJSONHealthHandler(t *testing.T) {
-	// Reset health status before each test
-	SetHealthy()
+	// Reset health state before each test
+	Reset()
 
 	tests := []struct {
 		name           string
@@ -355,8 +355,8 @@ func TestStatusManagement(t *testing.T) {
 }
 
 func TestConcurrentAccess(t *testing.T) {
-	// Reset health status
-	SetHealthy()
+	// Reset health state
+	Reset()
 
 	// Create a done channel to signal completion
 	done := make(chan bool)
@@ -385,4 +385,24 @@ func TestConcurrentAccess(t *testing.T) {
 	}
 
 	// If we got here without deadlock or panic, the test passes
-} 
\ No newline at end of file
+} 
+func TestReset(t *testing.T) {
+	SetUnhealthy("Test reason")
+	Register("db", func(ctx context.Context) error { return nil })
+	Evaluate(context.Background())
+
+	Reset()
+
+	if status := GetStatus(); status != Up {
+		t.Errorf("Reset should restore UP: got %v", status)
+	}
+	if reason := GetReason(); reason != "" {
+		t.Errorf("Reset should clear the reason: got %v", reason)
+	}
+	if results := Handle().Results(); len(results) != 0 {
+		t.Errorf("Reset should clear check results: got %v", results)
+	}
+	if results := Evaluate(context.Background()); len(results) != 0 {
+		t.Errorf("Reset should clear registered checks: got %v", results)
+	}
+}
@@ -0,0 +1,25 @@
+module github.com/andres-vara/health/healthcel
+
+go 1.24.0
+
+require (
+	github.com/andres-vara/health v0.0.0
+	github.com/google/cel-go v0.26.1
+)
+
+require (
+	cel.dev/expr v0.24.0 // indirect
+	github.com/andres-vara/shttp v0.0.1 // indirect
+	github.com/andres-vara/slogr v0.0.3 // indirect
+	github.com/antlr4-go/antlr/v4 v4.13.0 // indirect
+	github.com/stoewer/go-strcase v1.2.0 // indirect
+	golang.org/x/exp v0.0.0-20230515195305-f3d0a9c9a5cc // indirect
+	google.golang.org/genproto/googleapis/api v0.0.0-20240826202546-f6391c0de4c7 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240826202546-f6391c0de4c7 // indirect
+	google.golang.org/protobuf v1.34.2 // indirect
+)
+
+// Expression checks live in their own module to keep CEL and its
+// dependencies out of the health module's; it is developed against the
+// local copy.
+replace github.com/andres-vara/health => ../
@@ -0,0 +1,282 @@
+// Package healthcel adds checks written as CEL expressions over the gauges
+// and check details of a handler, so operators can declare guardrails in
+// config files without code changes:
+//
+//	mem.heap_bytes < quantity("2Gi") && queue.depth < 1000
+//
+// It is its own module so the health package itself doesn't depend on CEL.
+package healthcel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/andres-vara/health"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/interpreter"
+)
+
+// env is the environment of every expression: standard CEL plus quantity.
+var env = mustEnv()
+
+func mustEnv() *cel.Env {
+	env, err := cel.NewEnv(
+		cel.Function("quantity",
+			cel.Overload("quantity_string", []*cel.Type{cel.StringType}, cel.DoubleType,
+				cel.UnaryBinding(quantity))),
+	)
+	if err != nil {
+		panic(err)
+	}
+	return env
+}
+
+// suffixes are the multipliers of the size suffixes quantity accepts,
+// binary ones first so "Ki" isn't taken for "K".
+var suffixes = []struct {
+	suffix     string
+	multiplier float64
+}{
+	{"Ki", 1 << 10}, {"Mi", 1 << 20}, {"Gi", 1 << 30}, {"Ti", 1 << 40},
+	{"K", 1e3}, {"M", 1e6}, {"G", 1e9}, {"T", 1e12},
+}
+
+// quantity parses a number with an optional binary (Ki, Mi, Gi, Ti) or
+// decimal (K, M, G, T) size suffix, as in quantity("2Gi").
+func quantity(value ref.Val) ref.Val {
+	s, ok := value.(types.String)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(value)
+	}
+
+	number, multiplier := string(s), 1.0
+	for _, suffix := range suffixes {
+		if trimmed, found := strings.CutSuffix(number, suffix.suffix); found {
+			number, multiplier = trimmed, suffix.multiplier
+			break
+		}
+	}
+	v, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return types.NewErr("invalid quantity %q", string(s))
+	}
+	return types.Double(v * multiplier)
+}
+
+// Register registers a check on h passing while expr is true. See Check.
+func Register(h *health.Health, name, expr string) error {
+	check, err := Check(h, expr)
+	if err != nil {
+		return err
+	}
+
+	h.Register(name, check)
+	return nil
+}
+
+// Check compiles expr into a check passing while it is true. The expression
+// is CEL, evaluated each cycle with these variables:
+//
+//   - the gauges of h (see health.RegisterGauge) by name, as doubles, such
+//     as mem.heap_bytes or queue.depth
+//   - details, the details of the checks' last results by check and key, as
+//     in details.db.lag_seconds or details["orders-db"].connected
+//
+// Besides the standard CEL functions, quantity("2Gi") gives the number of a
+// size with a binary (Ki, Mi, Gi, Ti) or decimal (K, M, G, T) suffix. As
+// CEL doesn't mix doubles and ints in arithmetic, constants multiplied with
+// gauges are written as doubles, as in queue.depth * 2.0; comparisons mix
+// them freely. Details are read from the last results, so the previous
+// cycle's for checks run in the same one.
+//
+// Gauges are sampled once per evaluation, and a failing check reports the
+// values the expression saw. Unknown gauges and details fail the check as
+// configuration errors.
+func Check(h *health.Health, expr string) (health.CheckFunc, error) {
+	parsed, issues := env.Parse(expr)
+	if issues.Err() != nil {
+		return nil, fmt.Errorf("healthcel: invalid expression %q: %w", expr, issues.Err())
+	}
+	program, err := env.Program(parsed)
+	if err != nil {
+		return nil, fmt.Errorf("healthcel: invalid expression %q: %w", expr, err)
+	}
+	refs := detailRefs(parsed)
+
+	return func(ctx context.Context) error {
+		vars := &activation{h: h}
+		out, _, err := program.ContextEval(ctx, vars)
+		if err != nil {
+			return health.ConfigurationError(fmt.Errorf("evaluating %s: %w", expr, err))
+		}
+		result, ok := out.Value().(bool)
+		if !ok {
+			return health.ConfigurationError(fmt.Errorf("%s is a %s, not a condition", expr, out.Type().TypeName()))
+		}
+		if result {
+			return nil
+		}
+
+		if values := vars.describe(refs); values != "" {
+			return errors.New(expr + " is false (" + values + ")")
+		}
+		return errors.New(expr + " is false")
+	}, nil
+}
+
+// Provider returns the factory of expression checks on h for config files,
+// taking an {"expr": "..."} config:
+//
+//	h.RegisterProvider("expression", healthcel.Provider(h))
+//
+//	{"checks": [{"name": "guardrails", "type": "expression", "config": {"expr": "queue.depth < 1000"}}]}
+func Provider(h *health.Health) health.ProviderFactory {
+	return func(config json.RawMessage) (health.CheckFunc, error) {
+		var settings struct {
+			Expr string `json:"expr"`
+		}
+		if err := json.Unmarshal(config, &settings); err != nil {
+			return nil, err
+		}
+
+		return Check(h, settings.Expr)
+	}
+}
+
+// activation resolves the variables of one evaluation, sampling every
+// gauge and the details once so the values reported are the ones the
+// expression saw.
+type activation struct {
+	h       *health.Health
+	gauges  map[string]float64
+	details map[string]map[string]any
+}
+
+// ResolveName implements interpreter.Activation.
+func (a *activation) ResolveName(name string) (any, bool) {
+	if name == "details" {
+		if a.details == nil {
+			a.details = detailsOf(a.h)
+		}
+		return a.details, true
+	}
+
+	if v, ok := a.gauges[name]; ok {
+		return v, true
+	}
+	v, ok := a.h.ReadGauge(name)
+	if ok {
+		if a.gauges == nil {
+			a.gauges = make(map[string]float64)
+		}
+		a.gauges[name] = v
+	}
+	return v, ok
+}
+
+// Parent implements interpreter.Activation.
+func (a *activation) Parent() interpreter.Activation {
+	return nil
+}
+
+// describe lists the gauges sampled and the details in refs, by name.
+func (a *activation) describe(refs []detailRef) string {
+	var parts []string
+	for name, v := range a.gauges {
+		parts = append(parts, name+"="+strconv.FormatFloat(v, 'f', -1, 64))
+	}
+	for _, ref := range refs {
+		if v, ok := a.details[ref.check][ref.key]; ok {
+			parts = append(parts, ref.String()+"="+fmt.Sprint(v))
+		}
+	}
+	slices.Sort(parts)
+	return strings.Join(slices.Compact(parts), ", ")
+}
+
+// detailsOf returns the details of h's last results by check.
+func detailsOf(h *health.Health) map[string]map[string]any {
+	results := h.Results()
+	details := make(map[string]map[string]any, len(results))
+	for _, result := range results {
+		if result.Details == nil {
+			result.Details = map[string]any{}
+		}
+		details[result.Name] = result.Details
+	}
+	return details
+}
+
+// detailRef is a detail an expression reads.
+type detailRef struct {
+	check, key string
+}
+
+func (r detailRef) String() string {
+	if isIdentifier(r.check) {
+		return "details." + r.check + "." + r.key
+	}
+	return "details[" + strconv.Quote(r.check) + "]." + r.key
+}
+
+// isIdentifier reports whether s can be selected as a field in CEL.
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// detailRefs finds the details.<check>.<key> and details["<check>"].<key>
+// references of an expression.
+func detailRefs(parsed *cel.Ast) []detailRef {
+	var refs []detailRef
+	ast.MatchDescendants(ast.NavigateAST(parsed.NativeRep()), func(e ast.NavigableExpr) bool {
+		if e.Kind() != ast.SelectKind {
+			return false
+		}
+		if check, ok := detailsCheck(e.AsSelect().Operand()); ok {
+			refs = append(refs, detailRef{check: check, key: e.AsSelect().FieldName()})
+		}
+		return false
+	})
+	return refs
+}
+
+// detailsCheck returns the check e selects among the details, as in
+// details.db or details["db"].
+func detailsCheck(e ast.Expr) (string, bool) {
+	isDetails := func(e ast.Expr) bool {
+		return e.Kind() == ast.IdentKind && e.AsIdent() == "details"
+	}
+
+	switch e.Kind() {
+	case ast.SelectKind:
+		if isDetails(e.AsSelect().Operand()) {
+			return e.AsSelect().FieldName(), true
+		}
+	case ast.CallKind:
+		call := e.AsCall()
+		if call.FunctionName() != operators.Index || len(call.Args()) != 2 || !isDetails(call.Args()[0]) {
+			return "", false
+		}
+		if call.Args()[1].Kind() == ast.LiteralKind {
+			if check, ok := call.Args()[1].AsLiteral().(types.String); ok {
+				return string(check), true
+			}
+		}
+	}
+	return "", false
+}
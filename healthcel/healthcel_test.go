@@ -0,0 +1,164 @@
+package healthcel
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+// newHandler returns a handler with a few gauges and a check reporting
+// details, evaluated once so expressions can read them.
+func newHandler(t *testing.T) *health.Health {
+	t.Helper()
+
+	h := health.New()
+	h.RegisterGauge("queue.depth", func() float64 { return 250 })
+	h.RegisterGauge("pool.used", func() float64 { return 1 << 30 })
+	h.Register("db", func(ctx context.Context) error {
+		health.SetDetail(ctx, "lag", 3)
+		health.SetDetail(ctx, "open", int64(12))
+		health.SetDetail(ctx, "wait", 1500*time.Millisecond)
+		return nil
+	})
+	h.Register("internal-db", func(ctx context.Context) error {
+		health.SetDetail(ctx, "ok", true)
+		return nil
+	})
+	h.Evaluate(context.Background())
+	return h
+}
+
+func TestExpressions(t *testing.T) {
+	h := newHandler(t)
+
+	tests := []struct {
+		expr string
+		pass bool
+	}{
+		{`pool.used < quantity("2Gi") && queue.depth < 1000`, true},
+		{`pool.used < quantity("512Mi") || queue.depth > 100`, true},
+		{`pool.used == quantity("1Gi")`, true},
+		{`!(queue.depth <= 250)`, false},
+		{`queue.depth * 2.0 + 10.0 / 2.0 - 5.0 == 500.0`, true},
+		{`quantity("1K") == 1000.0 && quantity("1.5Ki") == 1536.0`, true},
+		{`details.db.lag < 5 && details.db.open == 12`, true},
+		{`details["internal-db"].ok`, true},
+		{`details.db.wait < duration("2s")`, true},
+		// The right side isn't evaluated once the result is known
+		{`false && missing > 1`, false},
+		{`true || missing > 1`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			check, err := Check(h, tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = check(context.Background())
+			if err != nil && health.CategoryOf(err) == health.CategoryConfiguration {
+				t.Fatal(err)
+			}
+			if pass := err == nil; pass != tt.pass {
+				t.Errorf("got %v want %v (%v)", pass, tt.pass, err)
+			}
+		})
+	}
+}
+
+func TestExpressionErrors(t *testing.T) {
+	h := newHandler(t)
+
+	for _, expr := range []string{"", "queue.depth <", "(queue.depth", "queue.depth ~ 1", "2Gi"} {
+		if _, err := Check(h, expr); err == nil {
+			t.Errorf("%q: expected a parse error", expr)
+		}
+	}
+
+	evalErrors := map[string]string{
+		"nope.gauge > 1":            "nope.gauge",
+		"details.db.missing > 1":    "missing",
+		"details.cache.hits > 1":    "cache",
+		"queue.depth * 2 > 1.0":     "no such overload",
+		"queue.depth":               "not a condition",
+		`quantity("lots") > 1.0`:    "invalid quantity",
+		`details.db.wait < 1`:       "no such overload",
+		`details["db"].lag && true`: "no such overload",
+	}
+	for expr, want := range evalErrors {
+		check, err := Check(h, expr)
+		if err != nil {
+			t.Fatalf("%q: %v", expr, err)
+		}
+		err = check(context.Background())
+		if err == nil || !strings.Contains(err.Error(), want) || health.CategoryOf(err) != health.CategoryConfiguration {
+			t.Errorf("%q: got %v, want a configuration error containing %q", expr, err, want)
+		}
+	}
+}
+
+func TestCheckReportsValues(t *testing.T) {
+	depth, lag := 10.0, 2.0
+
+	h := health.New()
+	h.RegisterGauge("queue.depth", func() float64 { return depth })
+	h.Register("db", func(ctx context.Context) error {
+		health.SetDetail(ctx, "lag_seconds", lag)
+		return nil
+	})
+	if err := Register(h, "guardrails", "queue.depth < 100 && details.db.lag_seconds < 5 && mem.heap_bytes > 0.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The expression reads the last results, so it needs a cycle of them
+	guardrails := func() health.CheckResult {
+		h.Evaluate(context.Background())
+		for _, result := range h.Evaluate(context.Background()) {
+			if result.Name == "guardrails" {
+				return result
+			}
+		}
+		t.Fatal("no guardrails result")
+		return health.CheckResult{}
+	}
+
+	if r := guardrails(); r.Status != health.Up {
+		t.Errorf("expected the guardrails to pass: %+v", r)
+	}
+
+	depth, lag = 10, 8
+	r := guardrails()
+	if r.Status != health.Down {
+		t.Fatalf("expected the guardrails to fail: %+v", r)
+	}
+	if !strings.Contains(r.Reason, "details.db.lag_seconds=8") || !strings.Contains(r.Reason, "queue.depth=10") {
+		t.Errorf("reason should report the values seen: %q", r.Reason)
+	}
+}
+
+func TestProvider(t *testing.T) {
+	h := health.New()
+	h.RegisterGauge("queue.depth", func() float64 { return 5 })
+	h.RegisterProvider("expression", Provider(h))
+
+	err := h.LoadChecks(strings.NewReader(`{"checks": [
+		{"name": "queue", "type": "expression", "config": {"expr": "queue.depth < 10"}}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if results := h.Evaluate(context.Background()); results[0].Status != health.Up {
+		t.Errorf("unexpected result: %+v", results[0])
+	}
+
+	err = h.LoadChecks(strings.NewReader(`{"checks": [
+		{"name": "broken", "type": "expression", "config": {"expr": "queue.depth <"}}
+	]}`))
+	if err == nil {
+		t.Error("expected an invalid expression to be rejected")
+	}
+}
@@ -0,0 +1,90 @@
+// Package healthprom exports the health state as a Prometheus collector, for
+// services already using the Prometheus client library. It is its own module
+// so the health package itself doesn't depend on the client.
+package healthprom
+
+import (
+	"slices"
+
+	"github.com/andres-vara/health"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// statuses are the statuses every status metric has a series for, so alerts
+// can match on a zero value.
+var statuses = []health.Status{health.Up, health.Degraded, health.Overloaded, health.Down, health.TimedOut, health.NotEvaluated}
+
+var (
+	statusDesc = prometheus.NewDesc("health_status",
+		"Overall health status, 1 for the current one.", []string{"status"}, nil)
+	checkStatusDesc = prometheus.NewDesc("health_check_status",
+		"Status of each check, 1 for the current one.", []string{"check", "status"}, nil)
+	checkDurationDesc = prometheus.NewDesc("health_check_duration_seconds",
+		"Duration of the last run of each check.", []string{"check"}, nil)
+	transitionsDesc = prometheus.NewDesc("health_transitions_total",
+		`Status transitions, check="" for the overall status.`, []string{"check"}, nil)
+)
+
+// Collector returns a collector for the default handler. See NewCollector.
+func Collector() prometheus.Collector {
+	return NewCollector(health.Handle())
+}
+
+// NewCollector returns a collector publishing the same metrics as
+// (*health.Health).MetricsHandler, read from h on every scrape:
+//
+//	prometheus.MustRegister(healthprom.Collector())
+func NewCollector(h *health.Health) prometheus.Collector {
+	return collector{h}
+}
+
+type collector struct {
+	h *health.Health
+}
+
+// Describe implements prometheus.Collector.
+func (c collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- statusDesc
+	ch <- checkStatusDesc
+	ch <- checkDurationDesc
+	ch <- transitionsDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c collector) Collect(ch chan<- prometheus.Metric) {
+	snapshot := c.h.Snapshot()
+
+	for _, s := range statusesWith(snapshot.Status) {
+		ch <- prometheus.MustNewConstMetric(statusDesc, prometheus.GaugeValue, boolValue(s == snapshot.Status), string(s))
+	}
+
+	results := snapshot.Checks
+	for _, group := range snapshot.Groups {
+		results = append(results, group.Checks...)
+	}
+	for _, r := range results {
+		for _, s := range statusesWith(r.Status) {
+			ch <- prometheus.MustNewConstMetric(checkStatusDesc, prometheus.GaugeValue, boolValue(s == r.Status), r.Name, string(s))
+		}
+		ch <- prometheus.MustNewConstMetric(checkDurationDesc, prometheus.GaugeValue, r.Duration.Seconds(), r.Name)
+	}
+
+	for name, changes := range c.h.History().Changes() {
+		ch <- prometheus.MustNewConstMetric(transitionsDesc, prometheus.CounterValue, float64(changes), name)
+	}
+}
+
+// statusesWith returns statuses plus status when it is a custom one.
+func statusesWith(status health.Status) []health.Status {
+	if slices.Contains(statuses, status) {
+		return statuses
+	}
+	return append(slices.Clip(statuses), status)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,56 @@
+package healthprom
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/andres-vara/health"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+	h := health.New()
+	var dbErr error
+	h.Register("db", func(ctx context.Context) error { return dbErr })
+	h.Evaluate(context.Background())
+	dbErr = errors.New("connection refused")
+	h.Evaluate(context.Background())
+
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(NewCollector(h))
+
+	expected := `
+# HELP health_check_status Status of each check, 1 for the current one.
+# TYPE health_check_status gauge
+health_check_status{check="db",status="DEGRADED"} 0
+health_check_status{check="db",status="DOWN"} 1
+health_check_status{check="db",status="NOT_EVALUATED"} 0
+health_check_status{check="db",status="OVERLOADED"} 0
+health_check_status{check="db",status="TIMED_OUT"} 0
+health_check_status{check="db",status="UP"} 0
+# HELP health_status Overall health status, 1 for the current one.
+# TYPE health_status gauge
+health_status{status="DEGRADED"} 0
+health_status{status="DOWN"} 1
+health_status{status="NOT_EVALUATED"} 0
+health_status{status="OVERLOADED"} 0
+health_status{status="TIMED_OUT"} 0
+health_status{status="UP"} 0
+# HELP health_transitions_total Status transitions, check="" for the overall status.
+# TYPE health_transitions_total counter
+health_transitions_total{check=""} 1
+health_transitions_total{check="db"} 1
+`
+	err := testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"health_status", "health_check_status", "health_transitions_total")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if n := testutil.CollectAndCount(NewCollector(h), "health_check_duration_seconds"); n != 1 {
+		t.Errorf("expected one duration series, got %d", n)
+	}
+}
@@ -0,0 +1,117 @@
+package healthtest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+// ScriptedChecker is a check that returns a predefined sequence of results,
+// one per call. Once the script runs out the last result keeps repeating.
+type ScriptedChecker struct {
+	mutex   sync.Mutex
+	results []error
+	calls   int
+}
+
+// NewScriptedChecker returns a checker that plays back results in order. A nil
+// entry is a passing check. With no results the check always passes.
+func NewScriptedChecker(results ...error) *ScriptedChecker {
+	return &ScriptedChecker{results: results}
+}
+
+// Check returns the next scripted result. It can be registered directly:
+//
+//	h.Register("db", healthtest.NewScriptedChecker(nil, errDown).Check)
+func (s *ScriptedChecker) Check(ctx context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.calls++
+	if len(s.results) == 0 {
+		return nil
+	}
+	if s.calls > len(s.results) {
+		return s.results[len(s.results)-1]
+	}
+	return s.results[s.calls-1]
+}
+
+// Calls returns how many times the check has run.
+func (s *ScriptedChecker) Calls() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.calls
+}
+
+// Invocation describes a single call to a RecordingChecker.
+type Invocation struct {
+	// Context is the context the check was called with.
+	Context context.Context
+	// Deadline is the context deadline, if HasDeadline is set.
+	Deadline    time.Time
+	HasDeadline bool
+	// Timeout is the time that was left until the deadline when the check
+	// was called.
+	Timeout time.Duration
+	// Err is what the check returned.
+	Err error
+	// Canceled reports whether the context was already done when the check
+	// returned.
+	Canceled bool
+}
+
+// RecordingChecker wraps a check and records every invocation, so tests can
+// verify how often checks run and with which deadlines.
+type RecordingChecker struct {
+	check health.CheckFunc
+
+	mutex       sync.Mutex
+	invocations []Invocation
+}
+
+// NewRecordingChecker records calls to check. A nil check always passes.
+func NewRecordingChecker(check health.CheckFunc) *RecordingChecker {
+	return &RecordingChecker{check: check}
+}
+
+// Check runs the wrapped check and records the call.
+func (r *RecordingChecker) Check(ctx context.Context) error {
+	invocation := Invocation{Context: ctx}
+	invocation.Deadline, invocation.HasDeadline = ctx.Deadline()
+	if invocation.HasDeadline {
+		invocation.Timeout = time.Until(invocation.Deadline)
+	}
+
+	if r.check != nil {
+		invocation.Err = r.check(ctx)
+	}
+	invocation.Canceled = ctx.Err() != nil
+
+	r.mutex.Lock()
+	r.invocations = append(r.invocations, invocation)
+	r.mutex.Unlock()
+
+	return invocation.Err
+}
+
+// Calls returns how many times the check has run.
+func (r *RecordingChecker) Calls() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return len(r.invocations)
+}
+
+// Invocations returns a copy of the recorded calls, oldest first.
+func (r *RecordingChecker) Invocations() []Invocation {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	invocations := make([]Invocation, len(r.invocations))
+	copy(invocations, r.invocations)
+	return invocations
+}
@@ -0,0 +1,93 @@
+package healthtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+func TestScriptedChecker(t *testing.T) {
+	errDown := errors.New("down")
+	checker := NewScriptedChecker(nil, errDown)
+
+	expected := []error{nil, errDown, errDown}
+	for i, want := range expected {
+		if err := checker.Check(context.Background()); err != want {
+			t.Errorf("call %d: got %v want %v", i+1, err, want)
+		}
+	}
+	if calls := checker.Calls(); calls != 3 {
+		t.Errorf("got %d calls want 3", calls)
+	}
+
+	if err := NewScriptedChecker().Check(context.Background()); err != nil {
+		t.Errorf("empty script should pass, got %v", err)
+	}
+}
+
+func TestScriptedCheckerRegistered(t *testing.T) {
+	health.Reset()
+	h := health.Handle()
+	h.Register("healthtest-scripted", NewScriptedChecker(nil, errors.New("down")).Check)
+
+	Tick(t, h)
+	AssertUp(t, h, "healthtest-scripted")
+
+	Tick(t, h)
+	AssertDown(t, h, "healthtest-scripted")
+}
+
+func TestRecordingChecker(t *testing.T) {
+	errDown := errors.New("down")
+	checker := NewRecordingChecker(func(ctx context.Context) error { return errDown })
+
+	if err := checker.Check(context.Background()); err != errDown {
+		t.Errorf("wrapped error not returned: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	_ = checker.Check(ctx)
+
+	invocations := checker.Invocations()
+	if len(invocations) != 2 || checker.Calls() != 2 {
+		t.Fatalf("expected 2 invocations, got %d", len(invocations))
+	}
+
+	if invocations[0].HasDeadline {
+		t.Error("first call should not have a deadline")
+	}
+	if invocations[0].Err != errDown {
+		t.Errorf("unexpected recorded error: %v", invocations[0].Err)
+	}
+
+	second := invocations[1]
+	if !second.HasDeadline || second.Timeout <= 0 || second.Timeout > time.Minute {
+		t.Errorf("unexpected deadline recording: %+v", second)
+	}
+	if second.Context != ctx {
+		t.Error("recorded context does not match")
+	}
+}
+
+func TestRecordingCheckerSeesBudget(t *testing.T) {
+	health.Reset()
+	h := health.Handle()
+	checker := NewRecordingChecker(nil)
+	h.Register("healthtest-recorded", checker.Check)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	h.Evaluate(ctx)
+
+	invocations := checker.Invocations()
+	if len(invocations) != 1 {
+		t.Fatalf("expected 1 invocation, got %d", len(invocations))
+	}
+	if !invocations[0].HasDeadline || invocations[0].Timeout > time.Second {
+		t.Errorf("check did not run under the evaluation deadline: %+v", invocations[0])
+	}
+}
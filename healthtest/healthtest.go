@@ -0,0 +1,137 @@
+// Package healthtest provides helpers for unit-testing code that wires up
+// health checks: a controllable clock, deterministic check cycles, and
+// assertions on check results.
+package healthtest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+// Target is the part of a health handler the helpers need. It is satisfied by
+// health.Handle() and any other handler built by the health package.
+type Target interface {
+	Evaluate(ctx context.Context) []health.CheckResult
+	Results() []health.CheckResult
+}
+
+// Clock is a health.Clock whose time only moves when the test says so.
+type Clock struct {
+	mutex   sync.Mutex
+	now     time.Time
+	tickers []*ticker
+}
+
+// NewClock returns a clock stopped at start.
+func NewClock(start time.Time) *Clock {
+	return &Clock{now: start}
+}
+
+// Now returns the clock's current time.
+func (c *Clock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+// NewTicker returns a ticker that fires as the clock is advanced.
+func (c *Clock) NewTicker(d time.Duration) health.Ticker {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	t := &ticker{
+		clock:    c,
+		interval: d,
+		next:     c.now.Add(d),
+		ch:       make(chan time.Time, 1),
+	}
+	c.tickers = append(c.tickers, t)
+
+	return t
+}
+
+// Advance moves the clock forward by d, firing every ticker that comes due.
+// Like time.Ticker, a ticker that hasn't been read drops the extra ticks.
+func (c *Clock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(d)
+	for _, t := range c.tickers {
+		for !t.next.After(c.now) {
+			select {
+			case t.ch <- t.next:
+			default:
+			}
+			t.next = t.next.Add(t.interval)
+		}
+	}
+}
+
+type ticker struct {
+	clock    *Clock
+	interval time.Duration
+	next     time.Time
+	ch       chan time.Time
+}
+
+func (t *ticker) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *ticker) Stop() {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+
+	for i, other := range t.clock.tickers {
+		if other == t {
+			t.clock.tickers = append(t.clock.tickers[:i], t.clock.tickers[i+1:]...)
+			return
+		}
+	}
+}
+
+// Tick runs one check cycle synchronously, exactly as a scheduler tick would,
+// and returns its results. Use it instead of Start and sleeps so the test
+// controls when checks run.
+func Tick(t testing.TB, h Target) []health.CheckResult {
+	t.Helper()
+
+	return h.Evaluate(context.Background())
+}
+
+// AssertStatus fails the test unless the named check's last result has the
+// given status.
+func AssertStatus(t testing.TB, h Target, name string, status health.Status) {
+	t.Helper()
+
+	for _, result := range h.Results() {
+		if result.Name == name {
+			if result.Status != status {
+				t.Errorf("check %q: got status %v want %v (reason: %q)", name, result.Status, status, result.Reason)
+			}
+			return
+		}
+	}
+
+	t.Errorf("check %q has no result; was it registered and evaluated?", name)
+}
+
+// AssertUp fails the test unless the named check is UP.
+func AssertUp(t testing.TB, h Target, name string) {
+	t.Helper()
+
+	AssertStatus(t, h, name, health.Up)
+}
+
+// AssertDown fails the test unless the named check is DOWN.
+func AssertDown(t testing.TB, h Target, name string) {
+	t.Helper()
+
+	AssertStatus(t, h, name, health.Down)
+}
@@ -0,0 +1,135 @@
+package healthtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+func TestClockAdvance(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewClock(start)
+
+	ticker := clock.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	clock.Advance(30 * time.Second)
+	select {
+	case <-ticker.C():
+		t.Fatal("ticker fired before its interval elapsed")
+	default:
+	}
+
+	clock.Advance(30 * time.Second)
+	select {
+	case tick := <-ticker.C():
+		if !tick.Equal(start.Add(time.Minute)) {
+			t.Errorf("unexpected tick time: %v", tick)
+		}
+	default:
+		t.Fatal("ticker did not fire after its interval elapsed")
+	}
+
+	if now := clock.Now(); !now.Equal(start.Add(time.Minute)) {
+		t.Errorf("unexpected time: %v", now)
+	}
+}
+
+func TestSchedulerTicks(t *testing.T) {
+	clock := NewClock(time.Now())
+	health.Reset()
+	h := health.Handle().WithClock(clock)
+	defer h.WithClock(nil)
+
+	calls := make(chan struct{}, 1)
+	h.Register("healthtest-scheduled", func(ctx context.Context) error {
+		select {
+		case calls <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	h.Start(ctx, time.Minute)
+
+	// The first evaluation happens straight away, the next one only once
+	// the clock reaches the interval.
+	<-calls
+	clock.Advance(time.Minute)
+	<-calls
+}
+
+func TestDrainTicks(t *testing.T) {
+	clock := NewClock(time.Now())
+	h := health.New(health.WithClock(clock))
+
+	done := h.RequestStarted()
+	drained := make(chan error, 1)
+	go func() { drained <- h.DrainAndWait(context.Background()) }()
+
+	// Let the drain start waiting; the request finishing is then only
+	// noticed once the clock ticks
+	for h.GetStatus() != health.Down {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	done()
+	select {
+	case err := <-drained:
+		t.Fatalf("drain finished without the clock ticking: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		clock.Advance(time.Second)
+		select {
+		case err := <-drained:
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			return
+		case <-time.After(time.Millisecond):
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("drain didn't finish as the clock ticked")
+		}
+	}
+}
+
+func TestAssertions(t *testing.T) {
+	health.Reset()
+	h := health.Handle()
+	h.Register("healthtest-up", func(ctx context.Context) error { return nil })
+	h.Register("healthtest-down", func(ctx context.Context) error { return errors.New("unreachable") })
+
+	Tick(t, h)
+
+	AssertUp(t, h, "healthtest-up")
+	AssertDown(t, h, "healthtest-down")
+	AssertStatus(t, h, "healthtest-down", health.Down)
+
+	// A failing assertion reports through the given testing.TB
+	rec := &recorder{TB: t}
+	AssertUp(rec, h, "healthtest-down")
+	AssertDown(rec, h, "missing")
+	if rec.failures != 2 {
+		t.Errorf("expected 2 failures, got %d", rec.failures)
+	}
+}
+
+type recorder struct {
+	testing.TB
+	failures int
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures++
+}
@@ -0,0 +1,235 @@
+package health
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultHistorySize is the number of transitions kept by default.
+const DefaultHistorySize = 1000
+
+// Transition is a change of status, either of the overall status or of a
+// single check.
+type Transition struct {
+	// Check is the name of the check, empty for the overall status
+	Check  string    `json:"check,omitempty"`
+	From   Status    `json:"from"`
+	To     Status    `json:"to"`
+	Reason string    `json:"reason,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// History records status transitions of the overall status and of every
+// check. It keeps a bounded number of transitions, dropping the oldest ones.
+type History struct {
+	mutex       sync.RWMutex
+	limit       int
+	transitions []Transition
+	series      map[string]*series
+	// recorded counts the transitions ever recorded, even across resets so
+	// cursors never go back, and dropped is when the newest dropped one
+	// happened
+	recorded uint64
+	dropped  time.Time
+}
+
+// series is what's known about one tracked status (a check, or the overall
+// status for the empty name).
+type series struct {
+	current Status
+	// start is when tracking starts (the first observation, or the oldest
+	// transition dropped from the history) and initial the status from then
+	start   time.Time
+	initial Status
+	// changes counts the transitions ever recorded, including those
+	// dropped since
+	changes uint64
+}
+
+// GetHistory returns the transition history of the default handler.
+func GetHistory() *History {
+	return handler.History()
+}
+
+// History returns the handler's transition history.
+func (h *Health) History() *History {
+	return &h.history
+}
+
+// WithHistorySize sets how many transitions are kept. Zero restores
+// DefaultHistorySize.
+func (h *Health) WithHistorySize(n int) *Health {
+	h.history.mutex.Lock()
+	defer h.history.mutex.Unlock()
+
+	h.history.limit = n
+	h.history.trim()
+	return h
+}
+
+// Transitions returns a copy of the recorded transitions, oldest first.
+func (hist *History) Transitions() []Transition {
+	hist.mutex.RLock()
+	defer hist.mutex.RUnlock()
+
+	transitions := make([]Transition, len(hist.transitions))
+	copy(transitions, hist.transitions)
+	return transitions
+}
+
+// changedSince returns the checks that changed status after cursor, with
+// the cursor to ask from next time. Cursors count the transitions recorded.
+// complete is false when the history no longer goes back that far.
+func (hist *History) changedSince(cursor uint64) (checks map[string]bool, next uint64, complete bool) {
+	hist.mutex.RLock()
+	defer hist.mutex.RUnlock()
+
+	first := hist.recorded - uint64(len(hist.transitions))
+	if cursor < first || cursor > hist.recorded {
+		return nil, hist.recorded, false
+	}
+
+	checks = make(map[string]bool)
+	for _, t := range hist.transitions[cursor-first:] {
+		checks[t.Check] = true
+	}
+	return checks, hist.recorded, true
+}
+
+// changedAfter is changedSince for the transitions after a time.
+func (hist *History) changedAfter(since time.Time) (checks map[string]bool, next uint64, complete bool) {
+	hist.mutex.RLock()
+	defer hist.mutex.RUnlock()
+
+	if hist.dropped.After(since) {
+		return nil, hist.recorded, false
+	}
+
+	checks = make(map[string]bool)
+	for _, t := range hist.transitions {
+		if t.At.After(since) {
+			checks[t.Check] = true
+		}
+	}
+	return checks, hist.recorded, true
+}
+
+// statusesAt returns the status at the given time of every check tracked
+// by then, and of the overall status under the empty name.
+func (hist *History) statusesAt(at time.Time) map[string]Status {
+	hist.mutex.RLock()
+	defer hist.mutex.RUnlock()
+
+	statuses := make(map[string]Status, len(hist.series))
+	for name, s := range hist.series {
+		if !s.start.After(at) {
+			statuses[name] = s.initial
+		}
+	}
+	for _, t := range hist.transitions {
+		if t.At.After(at) {
+			break
+		}
+		if _, ok := statuses[t.Check]; ok {
+			statuses[t.Check] = t.To
+		}
+	}
+	return statuses
+}
+
+// changedAt returns when the status of check (empty for the overall status)
+// last changed, or when tracking started if it never did. It is zero when
+// nothing was observed.
+func (hist *History) changedAt(check string) time.Time {
+	hist.mutex.RLock()
+	defer hist.mutex.RUnlock()
+
+	for i := len(hist.transitions) - 1; i >= 0; i-- {
+		if hist.transitions[i].Check == check {
+			return hist.transitions[i].At
+		}
+	}
+	if s, ok := hist.series[check]; ok {
+		return s.start
+	}
+	return time.Time{}
+}
+
+// observe records status for check (empty for the overall status) at the
+// given time, adding a transition when it differs from the previous one. It
+// returns the transition added, if any.
+func (hist *History) observe(check string, status Status, reason string, at time.Time) (Transition, bool) {
+	hist.mutex.Lock()
+	defer hist.mutex.Unlock()
+
+	if hist.series == nil {
+		hist.series = make(map[string]*series)
+	}
+
+	s, ok := hist.series[check]
+	if !ok {
+		hist.series[check] = &series{current: status, start: at, initial: status}
+		return Transition{}, false
+	}
+	if s.current == status {
+		return Transition{}, false
+	}
+
+	t := Transition{
+		Check:  check,
+		From:   s.current,
+		To:     status,
+		Reason: reason,
+		At:     at,
+	}
+	hist.transitions = append(hist.transitions, t)
+	hist.recorded++
+	s.current = status
+	s.changes++
+	hist.trim()
+	return t, true
+}
+
+// trim drops the oldest transitions over the limit. Tracking of the affected
+// series then starts from the dropped transition. It must be called with the
+// mutex held.
+func (hist *History) trim() {
+	limit := hist.limit
+	if limit <= 0 {
+		limit = DefaultHistorySize
+	}
+
+	for len(hist.transitions) > limit {
+		dropped := hist.transitions[0]
+		if s, ok := hist.series[dropped.Check]; ok {
+			s.start = dropped.At
+			s.initial = dropped.To
+		}
+		hist.dropped = dropped.At
+		hist.transitions = hist.transitions[1:]
+	}
+}
+
+// reset forgets everything recorded.
+func (hist *History) reset() {
+	hist.mutex.Lock()
+	defer hist.mutex.Unlock()
+
+	hist.transitions = nil
+	hist.series = nil
+	hist.dropped = time.Time{}
+}
+
+// observeLocked records the current overall status in the history and
+// publishes a change. It must be called with the handler's mutex held, after
+// every change of state.
+func (h *Health) observeLocked() {
+	status, reason, failed := h.aggregate()
+	if failed != nil {
+		reason = failed.Name + ": " + failed.Reason
+	}
+
+	if t, ok := h.history.observe("", status, reason, h.clockLocked().Now()); ok {
+		h.transitionLocked(t)
+	}
+}
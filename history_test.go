@@ -0,0 +1,86 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// newToggleHandler returns a handler with a single "db" check whose outcome
+// is controlled by the returned function, on a clock that only moves when
+// advanced.
+func newToggleHandler() (*Health, *stepClock, func(up bool)) {
+	clock := &stepClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	h := &Health{status: Up}
+	h.WithClock(clock)
+
+	var failing error
+	h.Register("db", func(ctx context.Context) error { return failing })
+
+	set := func(up bool) {
+		failing = nil
+		if !up {
+			failing = errors.New("refused")
+		}
+		h.Evaluate(context.Background())
+	}
+
+	return h, clock, set
+}
+
+func TestHistoryTransitions(t *testing.T) {
+	h, clock, set := newToggleHandler()
+
+	set(true)
+	clock.advance(time.Minute)
+	set(true)
+	clock.advance(time.Minute)
+	set(false)
+	clock.advance(time.Minute)
+	set(true)
+
+	transitions := h.History().Transitions()
+
+	// Both the check and the overall status went down and back up
+	if len(transitions) != 4 {
+		t.Fatalf("expected 4 transitions, got %d: %+v", len(transitions), transitions)
+	}
+
+	down := transitions[1]
+	if down.Check != "" || down.From != Up || down.To != Down || down.Reason != "db: refused" {
+		t.Errorf("unexpected overall transition: %+v", down)
+	}
+	if !down.At.Equal(clock.Now().Add(-time.Minute)) {
+		t.Errorf("transition recorded at %v", down.At)
+	}
+
+	if transitions[0].Check != "db" || transitions[2].Check != "db" || transitions[2].To != Up {
+		t.Errorf("unexpected check transitions: %+v", transitions)
+	}
+}
+
+func TestHistorySizeLimit(t *testing.T) {
+	h, clock, set := newToggleHandler()
+	h.WithHistorySize(4)
+
+	for i := 0; i < 10; i++ {
+		set(i%2 == 0)
+		clock.advance(time.Second)
+	}
+
+	if transitions := h.History().Transitions(); len(transitions) != 4 {
+		t.Errorf("expected the history to be capped at 4, got %d", len(transitions))
+	}
+}
+
+func TestHistoryReset(t *testing.T) {
+	h, _, set := newToggleHandler()
+	set(true)
+	set(false)
+
+	h.Reset()
+	if transitions := h.History().Transitions(); len(transitions) != 0 {
+		t.Errorf("Reset should clear the history, got %+v", transitions)
+	}
+}
@@ -0,0 +1,65 @@
+package health
+
+import "time"
+
+// Option configures a Health built by New.
+type Option func(*Health)
+
+// New returns a Health that is UP, with no checks, configured by opts. Any
+// builder method can be applied afterwards as well:
+//
+//	tenant := health.New(health.WithJSON(true)).WithRetryAfter(30 * time.Second)
+//	tenant.Register("db", pingDB)
+//	mux.Handle("/tenants/acme/health", tenant)
+func New(opts ...Option) *Health {
+	h := &Health{status: Up}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// WithJSON makes New's handler answer in JSON rather than plain text.
+func WithJSON(v bool) Option {
+	return func(h *Health) {
+		h.WithJSON(v)
+	}
+}
+
+// WithClock sets the clock of New's handler. See (*Health).WithClock.
+func WithClock(c Clock) Option {
+	return func(h *Health) {
+		h.WithClock(c)
+	}
+}
+
+// WithEvaluationTimeout bounds the evaluations of New's handler. See
+// (*Health).WithEvaluationTimeout.
+func WithEvaluationTimeout(timeout time.Duration) Option {
+	return func(h *Health) {
+		h.WithEvaluationTimeout(timeout)
+	}
+}
+
+// WithMaxConcurrency sets how many checks New's handler runs at once. See
+// (*Health).WithMaxConcurrency.
+func WithMaxConcurrency(n int) Option {
+	return func(h *Health) {
+		h.WithMaxConcurrency(n)
+	}
+}
+
+// WithHistorySize sets how many transitions New's handler keeps.
+func WithHistorySize(n int) Option {
+	return func(h *Health) {
+		h.WithHistorySize(n)
+	}
+}
+
+// WithAggregator sets how the check results of New's handler roll up into
+// its overall status.
+func WithAggregator(a Aggregator) Option {
+	return func(h *Health) {
+		h.WithAggregator(a)
+	}
+}
@@ -0,0 +1,54 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewInstancesAreIndependent(t *testing.T) {
+	Reset()
+	defer Reset()
+
+	a := New(WithJSON(true))
+	b := New()
+
+	a.SetUnhealthy("maintenance")
+	b.Register("db", func(ctx context.Context) error { return errors.New("connection refused") })
+	b.Evaluate(context.Background())
+
+	if a.GetStatus() != Down || a.GetReason() != "maintenance" || len(a.Results()) != 0 {
+		t.Errorf("unexpected state of a: %v %q %v", a.GetStatus(), a.GetReason(), a.Results())
+	}
+	if b.GetStatus() != Up || len(b.Results()) != 1 {
+		t.Errorf("unexpected state of b: %v %v", b.GetStatus(), b.Results())
+	}
+	if GetStatus() != Up || len(Handle().Results()) != 0 {
+		t.Error("instances changed the default handler")
+	}
+
+	rr := httptest.NewRecorder()
+	a.ServeHTTP(rr, httptest.NewRequest("GET", "/health", nil))
+	if rr.Code != http.StatusServiceUnavailable || rr.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("unexpected response from a: %d %q", rr.Code, rr.Header().Get("Content-Type"))
+	}
+
+	rr = httptest.NewRecorder()
+	b.ServeHTTP(rr, httptest.NewRequest("GET", "/health", nil))
+	if rr.Code != http.StatusServiceUnavailable || rr.Body.String() != "DOWN: db: connection refused" {
+		t.Errorf("unexpected response from b: %d %q", rr.Code, rr.Body.String())
+	}
+}
+
+func TestSetUnhealthyRecordsOneTransition(t *testing.T) {
+	h := New()
+	h.SetHealthy()
+	h.SetUnhealthy("maintenance")
+
+	transitions := h.History().Transitions()
+	if len(transitions) != 1 || transitions[0].To != Down || transitions[0].Reason != "maintenance" {
+		t.Errorf("expected a single transition with the reason, got %+v", transitions)
+	}
+}
@@ -0,0 +1,45 @@
+package health
+
+import "errors"
+
+// JoinErrors combines the failures of several checks into one, like
+// errors.Join, for checks made of others. The result is only DEGRADED or
+// OVERLOADED when every failure is: a single plain failure makes the whole
+// a plain failure, with the markers of the others dropped so they can't hide
+// it. Failures all marked, but not all alike, are OVERLOADED. Categories and
+// errors.Is keep working through the result. It returns nil when every err
+// is nil.
+func JoinErrors(errs ...error) error {
+	joined := errors.Join(errs...)
+	if joined == nil {
+		return nil
+	}
+
+	for _, err := range errs {
+		if err != nil && !isDegraded(err) && !isOverload(err) {
+			return &unmarkedError{err: joined}
+		}
+	}
+	return joined
+}
+
+// unmarkedError hides the DegradedError and OverloadError markers of err.
+type unmarkedError struct {
+	err error
+}
+
+func (e *unmarkedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *unmarkedError) Is(target error) bool {
+	return errors.Is(e.err, target)
+}
+
+func (e *unmarkedError) As(target any) bool {
+	switch target.(type) {
+	case **degradedError, **overloadError:
+		return false
+	}
+	return errors.As(e.err, target)
+}
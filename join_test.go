@@ -0,0 +1,48 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestJoinErrors(t *testing.T) {
+	down := DependencyError(errors.New("db down"))
+	slow := DegradedError(errors.New("cache slow"))
+	shedding := OverloadError(errors.New("queue full"))
+
+	tests := []struct {
+		name string
+		errs []error
+		want Status
+	}{
+		{"none", []error{nil, nil}, Up},
+		{"plain and degraded", []error{down, slow}, Down},
+		{"overloaded and plain", []error{shedding, nil, down}, Down},
+		{"all degraded", []error{slow, nil, slow}, Degraded},
+		{"all overloaded", []error{shedding, shedding}, Overloaded},
+		{"degraded and overloaded", []error{slow, shedding}, Overloaded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New()
+			h.Register("combo", func(ctx context.Context) error { return JoinErrors(tt.errs...) })
+			if results := h.Evaluate(context.Background()); results[0].Status != tt.want {
+				t.Errorf("got %s want %s: %+v", results[0].Status, tt.want, results[0])
+			}
+		})
+	}
+
+	// A plain failure keeps what it wraps but the markers
+	err := JoinErrors(down, slow)
+	if err.Error() != "db down\ncache slow" {
+		t.Errorf("unexpected message %q", err)
+	}
+	if !errors.Is(err, down) || CategoryOf(err) != CategoryDependency {
+		t.Errorf("the joined failures should stay reachable: %v", err)
+	}
+	if isDegraded(err) || isOverload(err) {
+		t.Error("a plain failure shouldn't carry a marker")
+	}
+}
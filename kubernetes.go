@@ -0,0 +1,238 @@
+package health
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"time"
+)
+
+const (
+	// LivenessPath is the conventional path of the liveness endpoint
+	LivenessPath = "/livez"
+	// ReadinessPath is the conventional path of the readiness endpoint
+	ReadinessPath = "/readyz"
+	// StartupPath is the conventional path of the startup endpoint
+	StartupPath = "/startupz"
+	// HealthzPath is the conventional path of the full health report
+	HealthzPath = "/healthz"
+
+	// livenessLockTimeout is how long the liveness endpoint waits for the
+	// handler's lock before reporting the process as deadlocked.
+	livenessLockTimeout = 500 * time.Millisecond
+
+	probePeriod = 5 * time.Second
+)
+
+// Probe is a health endpoint together with the probe settings suggested for
+// it in a Kubernetes pod spec.
+type Probe struct {
+	Path    string
+	Handler http.Handler
+
+	InitialDelaySeconds int
+	PeriodSeconds       int
+	TimeoutSeconds      int
+	FailureThreshold    int
+}
+
+// KubernetesProbes are the liveness, readiness and startup endpoints wired by
+// KubernetesDefaults.
+type KubernetesProbes struct {
+	Liveness  Probe
+	Readiness Probe
+	Startup   Probe
+}
+
+// KubernetesDefaults wires the conventional probe endpoints for the default
+// handler. See (*Health).KubernetesDefaults.
+func KubernetesDefaults(mux *http.ServeMux, startupGrace time.Duration) KubernetesProbes {
+	return handler.KubernetesDefaults(mux, startupGrace)
+}
+
+// KubernetesDefaults wires the conventional trio of probe endpoints onto mux
+// and returns their paths and suggested probe settings:
+//
+//   - /livez answers 200 unless the handler is deadlocked or a probe added
+//     with RegisterLiveness fails, so a failing dependency never gets the
+//     pod restarted.
+//   - /readyz reports the overall status, including the registered checks.
+//   - /startupz answers 503 until the service has been UP or DEGRADED
+//     once, and its suggested settings give the service startupGrace to
+//     get there.
+//
+// A nil mux only builds the probes, so their handlers can be mounted on any
+// router.
+func (h *Health) KubernetesDefaults(mux *http.ServeMux, startupGrace time.Duration) KubernetesProbes {
+	h.mutex.RLock()
+	onDemand := h.onDemand
+	h.mutex.RUnlock()
+
+	probes := KubernetesProbes{
+		Liveness: Probe{
+			Path:             LivenessPath,
+			Handler:          http.HandlerFunc(h.serveLiveness),
+			PeriodSeconds:    10,
+			TimeoutSeconds:   1,
+			FailureThreshold: 3,
+		},
+		Readiness: Probe{
+			Path:    ReadinessPath,
+			Handler: h,
+			// Leave room for on-demand evaluation to finish before the
+			// kubelet gives up on the request.
+			PeriodSeconds:    seconds(probePeriod),
+			TimeoutSeconds:   seconds(onDemand) + 1,
+			FailureThreshold: 3,
+		},
+		Startup: Probe{
+			Path:             StartupPath,
+			Handler:          http.HandlerFunc(h.serveStartup),
+			PeriodSeconds:    seconds(probePeriod),
+			TimeoutSeconds:   seconds(onDemand) + 1,
+			FailureThreshold: max(1, int(math.Ceil(float64(startupGrace)/float64(probePeriod)))),
+		},
+	}
+
+	if mux != nil {
+		for _, probe := range []Probe{probes.Liveness, probes.Readiness, probes.Startup} {
+			mux.Handle(probe.Path, probe.Handler)
+		}
+	}
+
+	return probes
+}
+
+// Mount registers the default handler's endpoints at the conventional
+// paths. See (*Health).Mount.
+func Mount(mux *http.ServeMux) {
+	handler.Mount(mux)
+}
+
+// MountFunc registers the default handler's endpoints with handle. See
+// (*Health).MountFunc.
+func MountFunc(handle func(path string, h http.Handler)) {
+	handler.MountFunc(handle)
+}
+
+// Mount registers the probe endpoints of KubernetesDefaults (/livez, /readyz
+// and /startupz) and the health report at /healthz on mux, so wiring a
+// service to Kubernetes is one line:
+//
+//	health.Mount(mux)
+func (h *Health) Mount(mux *http.ServeMux) {
+	h.MountFunc(func(path string, handler http.Handler) {
+		mux.Handle(path, handler)
+	})
+}
+
+// MountFunc is Mount for any router: handle is called with every path and
+// its handler.
+func (h *Health) MountFunc(handle func(path string, h http.Handler)) {
+	// The grace period only affects the suggested probe settings, which
+	// aren't used here
+	probes := h.KubernetesDefaults(nil, 0)
+	for _, probe := range []Probe{probes.Liveness, probes.Readiness, probes.Startup} {
+		handle(probe.Path, probe.Handler)
+	}
+	handle(HealthzPath, h)
+}
+
+// WriteYAML writes a snippet for a container spec configuring the three
+// probes against port.
+func (p KubernetesProbes) WriteYAML(w io.Writer, port int) error {
+	for _, probe := range []struct {
+		key string
+		Probe
+	}{
+		{"livenessProbe", p.Liveness},
+		{"readinessProbe", p.Readiness},
+		{"startupProbe", p.Startup},
+	} {
+		_, err := fmt.Fprintf(w, "%s:\n  httpGet:\n    path: %s\n    port: %d\n", probe.key, probe.Path, port)
+		if err != nil {
+			return err
+		}
+		if probe.InitialDelaySeconds > 0 {
+			if _, err := fmt.Fprintf(w, "  initialDelaySeconds: %d\n", probe.InitialDelaySeconds); err != nil {
+				return err
+			}
+		}
+		_, err = fmt.Fprintf(w, "  periodSeconds: %d\n  timeoutSeconds: %d\n  failureThreshold: %d\n",
+			probe.PeriodSeconds, probe.TimeoutSeconds, probe.FailureThreshold)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// serveLiveness answers 200 as long as the handler's lock can be taken and
+// the liveness probes pass. The lock is only ever held briefly, so failing
+// to get it means the process is wedged and should be restarted.
+func (h *Health) serveLiveness(w http.ResponseWriter, r *http.Request) {
+	acquired := make(chan struct{})
+	go func() {
+		h.mutex.RLock()
+		h.mutex.RUnlock()
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+	case <-time.After(livenessLockTimeout):
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(string(Down) + ": deadlocked"))
+		return
+	}
+	// Only once the lock is known to be free, as the clock needs it
+	h.recordProbe(r)
+
+	if reason := h.probeLiveness(r.Context()); reason != "" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(string(Down) + ": " + reason))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(string(Up) + ": "))
+}
+
+// serveStartup answers 503 until the service has been available (UP or
+// DEGRADED) once and 200 from then on, regardless of later failures (those
+// are readiness' business).
+func (h *Health) serveStartup(w http.ResponseWriter, r *http.Request) {
+	h.recordProbe(r)
+
+	h.mutex.RLock()
+	started := h.started
+	h.mutex.RUnlock()
+
+	if !started {
+		h.evaluateOnDemand(r.Context(), selection{})
+	}
+	status, _, _ := h.overall()
+
+	h.mutex.Lock()
+	if isAvailable(status) {
+		h.started = true
+	}
+	started = h.started
+	h.mutex.Unlock()
+
+	if !started {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(string(Down) + ": starting"))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(string(Up) + ": "))
+}
+
+// seconds rounds d up to whole seconds, as used in probe settings.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
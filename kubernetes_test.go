@@ -0,0 +1,165 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKubernetesDefaults(t *testing.T) {
+	h := &Health{status: Up}
+	h.WithOnDemand(2 * time.Second)
+
+	mux := http.NewServeMux()
+	probes := h.KubernetesDefaults(mux, time.Minute)
+
+	if probes.Liveness.Path != "/livez" || probes.Readiness.Path != "/readyz" || probes.Startup.Path != "/startupz" {
+		t.Errorf("unexpected paths: %+v", probes)
+	}
+	if probes.Readiness.TimeoutSeconds != 3 {
+		t.Errorf("readiness timeout should leave room for on-demand evaluation: got %d", probes.Readiness.TimeoutSeconds)
+	}
+	if probes.Startup.FailureThreshold != 12 {
+		t.Errorf("startup failure threshold should cover the grace period: got %d", probes.Startup.FailureThreshold)
+	}
+
+	failing := true
+	h.Register("db", func(ctx context.Context) error {
+		if failing {
+			return errors.New("not ready")
+		}
+		return nil
+	})
+
+	get := func(path string) int {
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest("GET", path, nil))
+		return rr.Code
+	}
+
+	// A failing check never fails liveness
+	expected := map[string]int{
+		"/livez":    http.StatusOK,
+		"/readyz":   http.StatusServiceUnavailable,
+		"/startupz": http.StatusServiceUnavailable,
+	}
+	for path, want := range expected {
+		if code := get(path); code != want {
+			t.Errorf("%s: got %d want %d", path, code, want)
+		}
+	}
+
+	failing = false
+	if code := get("/startupz"); code != http.StatusOK {
+		t.Errorf("startup should pass once the service is UP: got %d", code)
+	}
+
+	// Once started, the startup probe keeps passing
+	failing = true
+	if code := get("/startupz"); code != http.StatusOK {
+		t.Errorf("startup should stay passed: got %d", code)
+	}
+	if code := get("/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("readiness should follow the checks: got %d", code)
+	}
+}
+
+func TestStartupWhenDegraded(t *testing.T) {
+	h := &Health{status: Up}
+	probes := h.KubernetesDefaults(nil, 0)
+	upstreamErr := errors.New("connection refused")
+	h.Register("opt", func(ctx context.Context) error { return errors.New("flaky") }, Informational())
+	h.Register("upstream", func(ctx context.Context) error { return upstreamErr }, RequiredAtStartup())
+
+	get := func(probe Probe) int {
+		rr := httptest.NewRecorder()
+		probe.Handler.ServeHTTP(rr, httptest.NewRequest("GET", probe.Path, nil))
+		return rr.Code
+	}
+
+	h.Evaluate(context.Background())
+	if code := get(probes.Startup); code != http.StatusServiceUnavailable {
+		t.Fatalf("startup should wait for the startup check: got %d", code)
+	}
+
+	// A degraded service is available, so it is started
+	upstreamErr = nil
+	h.Evaluate(context.Background())
+	if code := get(probes.Startup); code != http.StatusOK {
+		t.Errorf("startup should pass once DEGRADED: got %d", code)
+	}
+
+	// and the startup check no longer takes it down
+	upstreamErr = errors.New("connection refused")
+	h.Evaluate(context.Background())
+	if status, _, _ := h.overall(); status == Down {
+		t.Errorf("startup check still counts after warm-up: %v", status)
+	}
+}
+
+func TestMount(t *testing.T) {
+	h := &Health{status: Up}
+
+	mux := http.NewServeMux()
+	h.Mount(mux)
+
+	h.SetUnhealthy("maintenance")
+	for path, code := range map[string]int{
+		LivenessPath:  http.StatusOK,
+		ReadinessPath: http.StatusServiceUnavailable,
+		StartupPath:   http.StatusServiceUnavailable,
+		HealthzPath:   http.StatusServiceUnavailable,
+	} {
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest("GET", path, nil))
+		if rr.Code != code {
+			t.Errorf("%s: got %d want %d", path, rr.Code, code)
+		}
+	}
+
+	var paths []string
+	h.MountFunc(func(path string, _ http.Handler) { paths = append(paths, path) })
+	if strings.Join(paths, " ") != "/livez /readyz /startupz /healthz" {
+		t.Errorf("unexpected paths %v", paths)
+	}
+}
+
+func TestLivenessDeadlocked(t *testing.T) {
+	h := &Health{status: Up}
+	probes := h.KubernetesDefaults(nil, 0)
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	rr := httptest.NewRecorder()
+	probes.Liveness.Handler.ServeHTTP(rr, httptest.NewRequest("GET", "/livez", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("got %d want %d", rr.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestKubernetesProbesYAML(t *testing.T) {
+	h := &Health{status: Up}
+	probes := h.KubernetesDefaults(nil, 30*time.Second)
+
+	var buf bytes.Buffer
+	if err := probes.WriteYAML(&buf, 8080); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"livenessProbe:\n  httpGet:\n    path: /livez\n    port: 8080\n",
+		"readinessProbe:\n  httpGet:\n    path: /readyz\n",
+		"startupProbe:\n  httpGet:\n    path: /startupz\n",
+		"  failureThreshold: 6\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("YAML is missing %q:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// errNotAcquired is reported by a probe that couldn't acquire what it tests
+var errNotAcquired = errors.New("not acquired in time")
+
+// livenessProbe is a probe registered with RegisterLiveness.
+type livenessProbe struct {
+	name  string
+	probe CheckFunc
+}
+
+// RegisterLiveness adds a liveness probe to the default handler. See
+// (*Health).RegisterLiveness.
+func RegisterLiveness(name string, probe CheckFunc) {
+	handler.RegisterLiveness(name, probe)
+}
+
+// RegisterLiveness adds a probe to the liveness endpoint, which then answers
+// 503 when the probe fails or doesn't return in time, so the orchestrator
+// restarts a process deadlocked but still running. Probes should only test
+// that something critical can be acquired, such as with Acquirable or
+// CanSend: a failing dependency must never restart the process.
+func (h *Health) RegisterLiveness(name string, probe CheckFunc) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.liveness = append(h.liveness, livenessProbe{name: name, probe: probe})
+}
+
+// Acquirable returns a liveness probe that locker, typically a mutex
+// guarding critical state, can be locked. The lock is released straight
+// away. While a lock attempt is stuck no new one is made, so a deadlock
+// doesn't pile up goroutines.
+func Acquirable(locker sync.Locker) CheckFunc {
+	var (
+		mutex   sync.Mutex
+		pending chan struct{}
+	)
+
+	return func(ctx context.Context) error {
+		mutex.Lock()
+		if pending == nil {
+			pending = make(chan struct{})
+			go func(acquired chan struct{}) {
+				locker.Lock()
+				locker.Unlock()
+				close(acquired)
+			}(pending)
+		}
+		acquired := pending
+		mutex.Unlock()
+
+		select {
+		case <-acquired:
+			mutex.Lock()
+			if pending == acquired {
+				pending = nil
+			}
+			mutex.Unlock()
+			return nil
+		case <-ctx.Done():
+			return errNotAcquired
+		}
+	}
+}
+
+// CanSend returns a liveness probe that value can be sent on ch, such as
+// the control channel of a worker loop that must keep receiving.
+func CanSend[T any](ch chan<- T, value T) CheckFunc {
+	return func(ctx context.Context) error {
+		select {
+		case ch <- value:
+			return nil
+		case <-ctx.Done():
+			return errNotAcquired
+		}
+	}
+}
+
+// probeLiveness runs the liveness probes concurrently, each within
+// livenessLockTimeout, and describes the ones failing.
+func (h *Health) probeLiveness(ctx context.Context) string {
+	h.mutex.RLock()
+	probes := h.liveness
+	h.mutex.RUnlock()
+
+	failures := make([]string, len(probes))
+	var wg sync.WaitGroup
+	for i, p := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, livenessLockTimeout)
+			defer cancel()
+
+			done := make(chan error, 1)
+			go func() { done <- p.probe(ctx) }()
+
+			var err error
+			select {
+			case err = <-done:
+			case <-ctx.Done():
+				err = errNotAcquired
+			}
+			if err != nil {
+				failures[i] = fmt.Sprintf("%s: %v", p.name, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var reasons []string
+	for _, failure := range failures {
+		if failure != "" {
+			reasons = append(reasons, failure)
+		}
+	}
+	return strings.Join(reasons, ", ")
+}
@@ -0,0 +1,45 @@
+package health
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLivenessProbes(t *testing.T) {
+	h := &Health{status: Up}
+	probes := h.KubernetesDefaults(nil, 0)
+
+	var critical sync.Mutex
+	control := make(chan struct{}, 1)
+	h.RegisterLiveness("state", Acquirable(&critical))
+	h.RegisterLiveness("worker", CanSend(control, struct{}{}))
+
+	get := func() *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		probes.Liveness.Handler.ServeHTTP(rr, httptest.NewRequest("GET", "/livez", nil))
+		return rr
+	}
+
+	if rr := get(); rr.Code != http.StatusOK {
+		t.Fatalf("expected acquirable probes to pass, got %d: %s", rr.Code, rr.Body)
+	}
+
+	// The worker stopped receiving: its buffer is full from the first probe
+	critical.Lock()
+	rr := get()
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected stuck probes to fail liveness, got %d", rr.Code)
+	}
+	if body := rr.Body.String(); !strings.Contains(body, "state:") || !strings.Contains(body, "worker:") {
+		t.Errorf("expected both probes in the reason, got %q", body)
+	}
+
+	critical.Unlock()
+	<-control
+	if rr := get(); rr.Code != http.StatusOK {
+		t.Errorf("expected liveness to recover, got %d: %s", rr.Code, rr.Body)
+	}
+}
@@ -0,0 +1,115 @@
+package health
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// metricStatuses are the statuses every status metric has a series for, so
+// alerts can match on a zero value.
+var metricStatuses = []Status{Up, Degraded, Overloaded, Down, TimedOut, NotEvaluated}
+
+// MetricsHandler serves the default handler's metrics. See
+// (*Health).MetricsHandler.
+func MetricsHandler() http.Handler {
+	return handler.MetricsHandler()
+}
+
+// MetricsHandler serves the health state in the Prometheus text exposition
+// format, so teams can alert on health and flapping without scraping the
+// health endpoint's body:
+//
+//   - health_status{status}: 1 for the overall status, 0 for the others
+//   - health_check_status{check,status}: the same for every check
+//   - health_check_duration_seconds{check}: how long the last run took
+//   - health_transitions_total{check}: status changes, check="" for the
+//     overall status
+//
+// It depends on nothing but the standard library: scrape it directly, or
+// mount it next to the client library's handler. The healthprom module
+// publishes the same metrics as a prometheus.Collector.
+func (h *Health) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = h.WriteMetrics(w)
+	})
+}
+
+// WriteMetrics writes the metrics served by MetricsHandler to w.
+func (h *Health) WriteMetrics(w io.Writer) error {
+	status, _, results := h.overall()
+	changes := h.history.Changes()
+
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "# HELP health_status Overall health status, 1 for the current one.")
+	fmt.Fprintln(bw, "# TYPE health_status gauge")
+	for _, s := range statusesWith(status) {
+		fmt.Fprintf(bw, "health_status{status=%s} %s\n", quote(string(s)), boolValue(s == status))
+	}
+
+	fmt.Fprintln(bw, "# HELP health_check_status Status of each check, 1 for the current one.")
+	fmt.Fprintln(bw, "# TYPE health_check_status gauge")
+	for _, r := range results {
+		for _, s := range statusesWith(r.Status) {
+			fmt.Fprintf(bw, "health_check_status{check=%s,status=%s} %s\n", quote(r.Name), quote(string(s)), boolValue(s == r.Status))
+		}
+	}
+
+	fmt.Fprintln(bw, "# HELP health_check_duration_seconds Duration of the last run of each check.")
+	fmt.Fprintln(bw, "# TYPE health_check_duration_seconds gauge")
+	for _, r := range results {
+		fmt.Fprintf(bw, "health_check_duration_seconds{check=%s} %s\n", quote(r.Name), strconv.FormatFloat(r.Duration.Seconds(), 'g', -1, 64))
+	}
+
+	fmt.Fprintln(bw, "# HELP health_transitions_total Status transitions, check=\"\" for the overall status.")
+	fmt.Fprintln(bw, "# TYPE health_transitions_total counter")
+	names := make([]string, 0, len(changes))
+	for name := range changes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		fmt.Fprintf(bw, "health_transitions_total{check=%s} %d\n", quote(name), changes[name])
+	}
+
+	return bw.Flush()
+}
+
+// Changes returns how many transitions were ever recorded, by check (empty
+// for the overall status).
+func (hist *History) Changes() map[string]uint64 {
+	hist.mutex.RLock()
+	defer hist.mutex.RUnlock()
+
+	changes := make(map[string]uint64, len(hist.series))
+	for name, s := range hist.series {
+		changes[name] = s.changes
+	}
+	return changes
+}
+
+// statusesWith returns metricStatuses plus status when it is a custom one.
+func statusesWith(status Status) []Status {
+	if slices.Contains(metricStatuses, status) {
+		return metricStatuses
+	}
+	return append(slices.Clip(metricStatuses), status)
+}
+
+// quote quotes a label value as the exposition format wants it.
+func quote(v string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v) + `"`
+}
+
+func boolValue(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
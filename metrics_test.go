@@ -0,0 +1,43 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	h := New()
+	h.SetHealthy()
+	fail := errors.New("connection refused")
+	h.Register(`db "main"`, func(ctx context.Context) error { return nil })
+	h.Register("cache", func(ctx context.Context) error { return fail })
+	h.Evaluate(context.Background())
+	fail = nil
+	h.Evaluate(context.Background())
+
+	rr := httptest.NewRecorder()
+	h.MetricsHandler().ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	body := rr.Body.String()
+	for _, want := range []string{
+		"# TYPE health_status gauge\n",
+		`health_status{status="UP"} 1`,
+		`health_status{status="DOWN"} 0`,
+		`health_check_status{check="cache",status="UP"} 1`,
+		`health_check_status{check="db \"main\"",status="DOWN"} 0`,
+		`health_check_duration_seconds{check="cache"} `,
+		"# TYPE health_transitions_total counter\n",
+		`health_transitions_total{check=""} 2`,
+		`health_transitions_total{check="cache"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics are missing %q:\n%s", want, body)
+		}
+	}
+}
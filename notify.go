@@ -0,0 +1,73 @@
+package health
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// defaultNotifyTimeout bounds a single notification.
+	defaultNotifyTimeout = 10 * time.Second
+	// maxPendingNotifications is how many transitions wait for a slow
+	// notifier before the oldest ones are dropped.
+	maxPendingNotifications = 100
+)
+
+// Notifier is told about status transitions, of the overall status (with an
+// empty Check) and of every check, such as to post them to a chat channel
+// or open an incident.
+type Notifier interface {
+	Notify(ctx context.Context, t Transition) error
+}
+
+// NotifierFunc adapts a function to the Notifier interface.
+type NotifierFunc func(ctx context.Context, t Transition) error
+
+// Notify calls f(ctx, t).
+func (f NotifierFunc) Notify(ctx context.Context, t Transition) error {
+	return f(ctx, t)
+}
+
+// AddNotifier adds a notifier to the default handler. See
+// (*Health).AddNotifier.
+func AddNotifier(n Notifier) {
+	handler.AddNotifier(n)
+}
+
+// AddNotifier tells n about every transition from now on. Notifications are
+// sent in the background, in order, one at a time per notifier, so a slow or
+// unreachable endpoint never holds up evaluation; a notifier falling too far
+// behind loses the oldest transitions. Failed notifications aren't retried.
+func (h *Health) AddNotifier(n Notifier) *Health {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.notifiers = append(h.notifiers, newQueue(maxPendingNotifications, func(t Transition) {
+		ctx, cancel := context.WithTimeout(context.Background(), defaultNotifyTimeout)
+		defer cancel()
+
+		_ = n.Notify(ctx, t)
+	}))
+	return h
+}
+
+// transitionLocked publishes t to the sinks, escalations and notifiers. It
+// must be called with the handler's mutex held.
+func (h *Health) transitionLocked(t Transition) {
+	h.recordLocked(Event{Kind: EventTransition, At: t.At, Transition: &t})
+	for _, q := range h.escalations {
+		q.push(t)
+	}
+	h.notifyLocked(t)
+}
+
+// notifyLocked queues t for every notifier, unless it is silenced. It must
+// be called with the handler's mutex held.
+func (h *Health) notifyLocked(t Transition) {
+	if h.silencedLocked(t) {
+		return
+	}
+	for _, q := range h.notifiers {
+		q.push(t)
+	}
+}
@@ -0,0 +1,96 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andres-vara/health"
+)
+
+type request struct {
+	path, query, auth string
+	body              map[string]any
+}
+
+// apiServer records the requests posted to it.
+func apiServer(t *testing.T) (*httptest.Server, chan request) {
+	t.Helper()
+	requests := make(chan request, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		req := request{path: r.URL.Path, query: r.URL.RawQuery, auth: r.Header.Get("Authorization")}
+		_ = json.Unmarshal(data, &req.body)
+		requests <- req
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, requests
+}
+
+func TestOpsgenie(t *testing.T) {
+	srv, requests := apiServer(t)
+	n := &Opsgenie{APIKey: "key", Service: "orders", Priority: "P2", URL: srv.URL + "/v2/alerts"}
+
+	if err := n.Notify(context.Background(), down); err != nil {
+		t.Fatal(err)
+	}
+	open := <-requests
+	if open.path != "/v2/alerts" || open.auth != "GenieKey key" {
+		t.Errorf("unexpected open request %+v", open)
+	}
+	if open.body["alias"] != "health-orders-db" || open.body["message"] != "orders/db is DOWN" || open.body["priority"] != "P2" {
+		t.Errorf("unexpected alert %v", open.body)
+	}
+
+	up := down
+	up.From, up.To = health.Down, health.Up
+	if err := n.Notify(context.Background(), up); err != nil {
+		t.Fatal(err)
+	}
+	closed := <-requests
+	if closed.path != "/v2/alerts/health-orders-db/close" || closed.query != "identifierType=alias" {
+		t.Errorf("unexpected close request %+v", closed)
+	}
+	if closed.body["note"] != "orders/db is UP" {
+		t.Errorf("unexpected close note %v", closed.body)
+	}
+
+	// A degraded status closes the alert without claiming it is UP
+	degraded := down
+	degraded.From, degraded.To = health.Down, health.Degraded
+	if err := n.Notify(context.Background(), degraded); err != nil {
+		t.Fatal(err)
+	}
+	if closed := <-requests; closed.body["note"] != "orders/db is DEGRADED" {
+		t.Errorf("unexpected close note %v", closed.body)
+	}
+}
+
+func TestVictorOps(t *testing.T) {
+	srv, requests := apiServer(t)
+	n := &VictorOps{APIKey: "key", RoutingKey: "team", Service: "orders", URL: srv.URL}
+
+	degraded := down
+	degraded.From, degraded.To = health.Down, health.Degraded
+	up := down
+	up.From, up.To = health.Degraded, health.Up
+	for _, tr := range []health.Transition{down, degraded, up} {
+		if err := n.Notify(context.Background(), tr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, want := range []string{"CRITICAL", "WARNING", "RECOVERY"} {
+		req := <-requests
+		if req.path != "/key/team" {
+			t.Errorf("unexpected path %q", req.path)
+		}
+		if req.body["message_type"] != want || req.body["entity_id"] != "health-orders-db" {
+			t.Errorf("expected a %s message for the check, got %v", want, req.body)
+		}
+	}
+}
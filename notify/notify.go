@@ -0,0 +1,79 @@
+// Package notify provides health.Notifier implementations posting status
+// transitions to chat and alerting services:
+//
+//	health.AddNotifier(&notify.Teams{URL: webhookURL, Service: "orders"})
+//
+// Every notifier is told about the transitions of the overall status and of
+// every check; Only narrows that down.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/andres-vara/health"
+)
+
+// Only passes on to n the transitions of the given checks, an empty name
+// standing for the overall status. Without names, only the overall status
+// is passed on.
+func Only(n health.Notifier, checks ...string) health.Notifier {
+	if len(checks) == 0 {
+		checks = []string{""}
+	}
+	return health.NotifierFunc(func(ctx context.Context, t health.Transition) error {
+		for _, check := range checks {
+			if t.Check == check {
+				return n.Notify(ctx, t)
+			}
+		}
+		return nil
+	})
+}
+
+// subject names what a transition is about, the service for the overall
+// status.
+func subject(t health.Transition, service string) string {
+	if t.Check != "" {
+		if service != "" {
+			return service + "/" + t.Check
+		}
+		return t.Check
+	}
+	if service != "" {
+		return service
+	}
+	return "service"
+}
+
+// post sends body to url and fails on anything but a 2xx answer.
+func post(ctx context.Context, client *http.Client, url string, header http.Header, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("notify: %s answered %s: %s", req.URL.Host, resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
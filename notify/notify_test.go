@@ -0,0 +1,113 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+var down = health.Transition{
+	Check:  "db",
+	From:   health.Up,
+	To:     health.Down,
+	Reason: "connection refused",
+	At:     time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+}
+
+// receiver records the bodies posted to it.
+func receiver(t *testing.T, status int) (*httptest.Server, chan []byte) {
+	t.Helper()
+	bodies := make(chan []byte, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- body
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, bodies
+}
+
+func TestTeams(t *testing.T) {
+	srv, bodies := receiver(t, http.StatusOK)
+
+	n := &Teams{URL: srv.URL, Service: "orders"}
+	if err := n.Notify(context.Background(), down); err != nil {
+		t.Fatal(err)
+	}
+
+	var msg teamsMessage
+	if err := json.Unmarshal(<-bodies, &msg); err != nil {
+		t.Fatal(err)
+	}
+	card := msg.Attachments[0].Content
+	if msg.Attachments[0].ContentType != "application/vnd.microsoft.card.adaptive" || card.Type != "AdaptiveCard" {
+		t.Fatalf("unexpected message %+v", msg)
+	}
+	if card.Body[0]["text"] != "orders/db is DOWN" || card.Body[0]["color"] != "Attention" {
+		t.Errorf("unexpected title block %v", card.Body[0])
+	}
+	if card.Body[1]["text"] != "connection refused" {
+		t.Errorf("expected the reason, got %v", card.Body[1])
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	srv, bodies := receiver(t, http.StatusOK)
+
+	n, err := NewWebhook(srv.URL, `{"text": {{printf "%s is %s: %s" .Subject .To .Reason | json}}, "at": "{{rfc3339 .At}}"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Notify(context.Background(), down); err != nil {
+		t.Fatal(err)
+	}
+
+	var body map[string]string
+	if err := json.Unmarshal(<-bodies, &body); err != nil {
+		t.Fatal(err)
+	}
+	if body["text"] != "db is DOWN: connection refused" || body["at"] != "2024-01-01T12:00:00Z" {
+		t.Errorf("unexpected body %v", body)
+	}
+
+	if _, err := NewWebhook(srv.URL, "{{"); err == nil {
+		t.Error("expected an invalid template to fail")
+	}
+}
+
+func TestRejected(t *testing.T) {
+	srv, _ := receiver(t, http.StatusForbidden)
+
+	err := (&Teams{URL: srv.URL}).Notify(context.Background(), down)
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("expected the rejection to fail, got %v", err)
+	}
+}
+
+func TestOnly(t *testing.T) {
+	var got []string
+	n := health.NotifierFunc(func(ctx context.Context, t health.Transition) error {
+		got = append(got, t.Check)
+		return nil
+	})
+
+	overall := down
+	overall.Check = ""
+	for _, tr := range []health.Transition{down, overall} {
+		_ = Only(n).Notify(context.Background(), tr)
+		_ = Only(n, "db").Notify(context.Background(), tr)
+	}
+
+	// Only the check's transition for Only(n, "db") and only the overall
+	// one for Only(n)
+	if strings.Join(got, ",") != "db," {
+		t.Errorf("unexpected notifications %q", got)
+	}
+}
@@ -0,0 +1,96 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/andres-vara/health"
+)
+
+// DefaultOpsgenieURL is the Opsgenie alert API in the US region.
+const DefaultOpsgenieURL = "https://api.opsgenie.com/v2/alerts"
+
+// Opsgenie opens an Opsgenie alert when a status goes DOWN (or anything but
+// UP or DEGRADED) and closes it when it is UP or DEGRADED again, noting
+// which. Alerts are identified by an alias made of the service and check
+// names, so repeated failures are deduplicated into the open alert.
+type Opsgenie struct {
+	// APIKey is the key of an API integration
+	APIKey string
+	// Service names the service in alerts and their alias
+	Service string
+	// Priority of the alerts, P1 to P5; Opsgenie's default (P3) if empty
+	Priority string
+	// Tags are added to the alerts
+	Tags []string
+	// URL of the alert API, such as https://api.eu.opsgenie.com/v2/alerts
+	// for the EU region; DefaultOpsgenieURL if empty
+	URL string
+	// HTTPClient is used for requests; http.DefaultClient if nil
+	HTTPClient *http.Client
+}
+
+type opsgenieAlert struct {
+	Message     string   `json:"message"`
+	Alias       string   `json:"alias"`
+	Description string   `json:"description,omitempty"`
+	Priority    string   `json:"priority,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Source      string   `json:"source,omitempty"`
+}
+
+type opsgenieClose struct {
+	Source string `json:"source,omitempty"`
+	Note   string `json:"note,omitempty"`
+}
+
+// Notify implements health.Notifier.
+func (n *Opsgenie) Notify(ctx context.Context, t health.Transition) error {
+	base := n.URL
+	if base == "" {
+		base = DefaultOpsgenieURL
+	}
+	base = strings.TrimSuffix(base, "/")
+
+	header := http.Header{"Authorization": {"GenieKey " + n.APIKey}}
+	alias := alertKey(t, n.Service)
+
+	if t.To == health.Up || t.To == health.Degraded {
+		body, err := json.Marshal(opsgenieClose{
+			Source: n.Service,
+			Note:   subject(t, n.Service) + " is " + string(t.To),
+		})
+		if err != nil {
+			return err
+		}
+		return post(ctx, n.HTTPClient, base+"/"+url.PathEscape(alias)+"/close?identifierType=alias", header, body)
+	}
+
+	body, err := json.Marshal(opsgenieAlert{
+		Message:     subject(t, n.Service) + " is " + string(t.To),
+		Alias:       alias,
+		Description: t.Reason,
+		Priority:    n.Priority,
+		Tags:        n.Tags,
+		Source:      n.Service,
+	})
+	if err != nil {
+		return err
+	}
+	return post(ctx, n.HTTPClient, base, header, body)
+}
+
+// alertKey identifies the incident of a transition, for deduplication.
+func alertKey(t health.Transition, service string) string {
+	key := "health"
+	if service != "" {
+		key += "-" + service
+	}
+	if t.Check != "" {
+		key += "-" + t.Check
+	}
+	return key
+}
@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+// Teams posts transitions as adaptive cards to a Microsoft Teams incoming
+// webhook (or a Workflows webhook accepting the same payload).
+type Teams struct {
+	// URL is the webhook URL
+	URL string
+	// Service names the service in messages; transitions of checks are
+	// shown as "service/check"
+	Service string
+	// HTTPClient is used for requests; http.DefaultClient if nil
+	HTTPClient *http.Client
+}
+
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+type teamsAttachment struct {
+	ContentType string    `json:"contentType"`
+	Content     teamsCard `json:"content"`
+}
+
+type teamsCard struct {
+	Schema  string           `json:"$schema"`
+	Type    string           `json:"type"`
+	Version string           `json:"version"`
+	Body    []map[string]any `json:"body"`
+}
+
+// Notify implements health.Notifier.
+func (n *Teams) Notify(ctx context.Context, t health.Transition) error {
+	color := "Attention"
+	if t.To == health.Up {
+		color = "Good"
+	}
+
+	body := []map[string]any{{
+		"type":   "TextBlock",
+		"text":   subject(t, n.Service) + " is " + string(t.To),
+		"weight": "Bolder",
+		"size":   "Medium",
+		"color":  color,
+	}}
+	if t.Reason != "" {
+		body = append(body, map[string]any{"type": "TextBlock", "text": t.Reason, "wrap": true})
+	}
+	body = append(body, map[string]any{
+		"type": "FactSet",
+		"facts": []map[string]string{
+			{"title": "Previously", "value": string(t.From)},
+			{"title": "Since", "value": t.At.UTC().Format(time.RFC3339)},
+		},
+	})
+
+	payload, err := json.Marshal(teamsMessage{
+		Type: "message",
+		Attachments: []teamsAttachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content: teamsCard{
+				Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+				Type:    "AdaptiveCard",
+				Version: "1.4",
+				Body:    body,
+			},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+
+	return post(ctx, n.HTTPClient, n.URL, nil, payload)
+}
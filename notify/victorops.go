@@ -0,0 +1,72 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/andres-vara/health"
+)
+
+// DefaultVictorOpsURL is the base of the Splunk On-Call (VictorOps) REST
+// endpoint integration.
+const DefaultVictorOpsURL = "https://alert.victorops.com/integrations/generic/20131114/alert"
+
+// VictorOps opens a Splunk On-Call (VictorOps) incident when a status goes
+// DOWN (or anything but UP or DEGRADED), downgrades it to a warning while it
+// is DEGRADED and resolves it when it is UP again, through the REST
+// endpoint integration. Incidents are identified by an entity ID made of
+// the service and check names.
+type VictorOps struct {
+	// APIKey is the key of the REST endpoint integration
+	APIKey string
+	// RoutingKey routes the incidents to a team
+	RoutingKey string
+	// Service names the service in incidents and their entity ID
+	Service string
+	// URL of the REST endpoint; DefaultVictorOpsURL if empty
+	URL string
+	// HTTPClient is used for requests; http.DefaultClient if nil
+	HTTPClient *http.Client
+}
+
+type victorOpsAlert struct {
+	MessageType       string `json:"message_type"`
+	EntityID          string `json:"entity_id"`
+	EntityDisplayName string `json:"entity_display_name"`
+	StateMessage      string `json:"state_message,omitempty"`
+	StateStartTime    int64  `json:"state_start_time"`
+	MonitoringTool    string `json:"monitoring_tool"`
+}
+
+// Notify implements health.Notifier.
+func (n *VictorOps) Notify(ctx context.Context, t health.Transition) error {
+	base := n.URL
+	if base == "" {
+		base = DefaultVictorOpsURL
+	}
+	endpoint := strings.TrimSuffix(base, "/") + "/" + url.PathEscape(n.APIKey) + "/" + url.PathEscape(n.RoutingKey)
+
+	messageType := "CRITICAL"
+	switch t.To {
+	case health.Up:
+		messageType = "RECOVERY"
+	case health.Degraded:
+		messageType = "WARNING"
+	}
+
+	body, err := json.Marshal(victorOpsAlert{
+		MessageType:       messageType,
+		EntityID:          alertKey(t, n.Service),
+		EntityDisplayName: subject(t, n.Service) + " is " + string(t.To),
+		StateMessage:      t.Reason,
+		StateStartTime:    t.At.Unix(),
+		MonitoringTool:    "health",
+	})
+	if err != nil {
+		return err
+	}
+	return post(ctx, n.HTTPClient, endpoint, nil, body)
+}
@@ -0,0 +1,74 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"text/template"
+	"time"
+
+	"github.com/andres-vara/health"
+)
+
+// Webhook posts transitions to any webhook, with a body rendered from a
+// template, for chat platforms (or anything else) without a dedicated
+// notifier.
+type Webhook struct {
+	// URL is the webhook URL
+	URL string
+	// Service names the service in messages, as {{.Subject}}
+	Service string
+	// Header is added to requests; the content type defaults to JSON
+	Header http.Header
+	// HTTPClient is used for requests; http.DefaultClient if nil
+	HTTPClient *http.Client
+
+	body *template.Template
+}
+
+// WebhookData is what the body template of a Webhook is executed with.
+type WebhookData struct {
+	health.Transition
+	// Subject is "service", "service/check" or the check name
+	Subject string
+	Service string
+}
+
+// NewWebhook returns a notifier posting to url the body rendered from the
+// given text/template, such as for a Mattermost or Google Chat webhook:
+//
+//	notify.NewWebhook(url, `{"text": {{printf "%s is %s: %s" .Subject .To .Reason | json}}}`)
+//
+// Besides the usual template functions, json encodes a value as JSON (use
+// it for strings in JSON bodies) and rfc3339 formats a time.
+func NewWebhook(url, body string) (*Webhook, error) {
+	tmpl, err := template.New("webhook").Funcs(template.FuncMap{
+		"json":    jsonString,
+		"rfc3339": func(t time.Time) string { return t.UTC().Format(time.RFC3339) },
+	}).Parse(body)
+	if err != nil {
+		return nil, err
+	}
+	return &Webhook{URL: url, body: tmpl}, nil
+}
+
+// Notify implements health.Notifier.
+func (n *Webhook) Notify(ctx context.Context, t health.Transition) error {
+	var body bytes.Buffer
+	err := n.body.Execute(&body, WebhookData{
+		Transition: t,
+		Subject:    subject(t, n.Service),
+		Service:    n.Service,
+	})
+	if err != nil {
+		return err
+	}
+
+	return post(ctx, n.HTTPClient, n.URL, n.Header, body.Bytes())
+}
+
+func jsonString(v any) (string, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
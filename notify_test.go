@@ -0,0 +1,40 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNotifier(t *testing.T) {
+	h := &Health{status: Up}
+	got := make(chan Transition, 10)
+	h.AddNotifier(NotifierFunc(func(ctx context.Context, tr Transition) error {
+		got <- tr
+		return nil
+	}))
+
+	fail := errors.New("connection refused")
+	h.Register("db", func(ctx context.Context) error { return fail })
+	h.Evaluate(context.Background())
+	fail = nil
+	h.Evaluate(context.Background())
+
+	// The first evaluation only starts tracking, the second one recovers
+	// the check and then the overall status, in that order.
+	want := []Transition{
+		{Check: "db", From: Down, To: Up},
+		{From: Down, To: Up},
+	}
+	for _, w := range want {
+		select {
+		case tr := <-got:
+			if tr.Check != w.Check || tr.From != w.From || tr.To != w.To {
+				t.Errorf("expected %+v, got %+v", w, tr)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no notification for %+v", w)
+		}
+	}
+}
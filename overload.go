@@ -0,0 +1,30 @@
+package health
+
+import "errors"
+
+type overloadError struct {
+	err error
+}
+
+func (e *overloadError) Error() string {
+	return e.err.Error()
+}
+
+func (e *overloadError) Unwrap() error {
+	return e.err
+}
+
+// OverloadError marks err as the service shedding load: the check is then
+// reported as OVERLOADED instead of DOWN. It returns nil for a nil err.
+func OverloadError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &overloadError{err: err}
+}
+
+// isOverload reports whether err was marked with OverloadError.
+func isOverload(err error) bool {
+	var overload *overloadError
+	return errors.As(err, &overload)
+}
@@ -0,0 +1,53 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOverloaded(t *testing.T) {
+	h := &Health{status: Overloaded}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("expected 429, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("expected a default Retry-After of 1, got %q", got)
+	}
+	if rec.Body.String() != "OVERLOADED: " {
+		t.Errorf("unexpected body: %q", rec.Body.String())
+	}
+}
+
+func TestOverloadedCheck(t *testing.T) {
+	h := &Health{status: Up}
+	var dbErr error
+	h.Register("queue", func(ctx context.Context) error {
+		return OverloadError(errors.New("10000 jobs waiting"))
+	})
+	h.Register("db", func(ctx context.Context) error { return dbErr })
+
+	results := h.Evaluate(context.Background())
+	if results[1].Status != Overloaded || results[1].Reason != "10000 jobs waiting" {
+		t.Errorf("unexpected queue result: %v %q", results[1].Status, results[1].Reason)
+	}
+	if status, reason, _ := h.overall(); status != Overloaded || reason != "queue: 10000 jobs waiting" {
+		t.Errorf("expected OVERLOADED, got %v %q", status, reason)
+	}
+
+	// A broken dependency is worse than being overloaded
+	dbErr = errors.New("refused")
+	h.Evaluate(context.Background())
+	if status, _, _ := h.overall(); status != Down {
+		t.Errorf("expected DOWN, got %v", status)
+	}
+
+	if OverloadError(nil) != nil {
+		t.Error("marking a nil error should return nil")
+	}
+}
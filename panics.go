@@ -0,0 +1,97 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// PanicCounter counts panics recovered by the application and reports them
+// through health, turning "silently recovering and corrupting state" into a
+// visible signal. See RecoverCounter.
+type PanicCounter struct {
+	clock     func() Clock
+	window    time.Duration
+	threshold int
+
+	mutex    sync.Mutex
+	warnOnly bool
+	panics   []time.Time
+	last     string
+}
+
+// RecoverCounter registers a panic counter on the default handler. See
+// (*Health).RecoverCounter.
+func RecoverCounter(name string, window time.Duration, threshold int) *PanicCounter {
+	return handler.RecoverCounter(name, window, threshold)
+}
+
+// RecoverCounter registers a check named name failing while more than
+// threshold panics were recovered within window. Report them from recover
+// blocks:
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			panics.Recovered(r)
+//		}
+//	}()
+func (h *Health) RecoverCounter(name string, window time.Duration, threshold int) *PanicCounter {
+	c := &PanicCounter{clock: h.getClock, window: window, threshold: threshold}
+	h.Register(name, c.check)
+	return c
+}
+
+// WarnOnly makes the counter's check degraded instead of failing when over
+// the threshold.
+func (c *PanicCounter) WarnOnly() *PanicCounter {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.warnOnly = true
+	return c
+}
+
+// Recovered records a recovered panic, v being the value returned by
+// recover. A nil v, when nothing panicked, is ignored.
+func (c *PanicCounter) Recovered(v any) {
+	if v == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.clock().Now()
+	c.panics = append(c.dropExpired(now), now)
+	c.last = fmt.Sprint(v)
+}
+
+// dropExpired returns the panics within the window ending at now. It must
+// be called with the mutex held.
+func (c *PanicCounter) dropExpired(now time.Time) []time.Time {
+	i := 0
+	for i < len(c.panics) && now.Sub(c.panics[i]) > c.window {
+		i++
+	}
+	c.panics = c.panics[i:]
+	return c.panics
+}
+
+func (c *PanicCounter) check(ctx context.Context) error {
+	c.mutex.Lock()
+	count := len(c.dropExpired(c.clock().Now()))
+	last, warnOnly := c.last, c.warnOnly
+	c.mutex.Unlock()
+
+	SetDetail(ctx, "panics", count)
+	if count <= c.threshold {
+		return nil
+	}
+
+	err := fmt.Errorf("%d panics recovered in %v, last: %s", count, c.window, last)
+	if warnOnly {
+		return DegradedError(err)
+	}
+	return InternalError(err)
+}
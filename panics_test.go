@@ -0,0 +1,51 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRecoverCounter(t *testing.T) {
+	clock := &stepClock{now: time.Now()}
+	h := (&Health{status: Up}).WithClock(clock)
+	panics := h.RecoverCounter("panics", time.Minute, 2)
+
+	recovering := func() {
+		defer func() { panics.Recovered(recover()) }()
+		panic("nil map write")
+	}
+	status := func() Status {
+		h.Evaluate(context.Background())
+		return h.Results()[0].Status
+	}
+
+	panics.Recovered(nil)
+	recovering()
+	recovering()
+	if s := status(); s != Up {
+		t.Errorf("expected panics up to the threshold to pass, got %s", s)
+	}
+
+	recovering()
+	if s := status(); s != Down {
+		t.Errorf("expected panics over the threshold to fail, got %s", s)
+	}
+	if reason := h.Results()[0].Reason; reason != "3 panics recovered in 1m0s, last: nil map write" {
+		t.Errorf("unexpected reason %q", reason)
+	}
+
+	clock.advance(2 * time.Minute)
+	if s := status(); s != Up {
+		t.Errorf("expected old panics to expire, got %s", s)
+	}
+
+	panics.WarnOnly()
+	recovering()
+	recovering()
+	recovering()
+	h.Evaluate(context.Background())
+	if r := h.Results()[0]; r.Status != Degraded {
+		t.Errorf("expected the check to be degraded only, got %+v", r)
+	}
+}
@@ -0,0 +1,98 @@
+package health
+
+import (
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// maxProbeSources is how many distinct probe sources are remembered, the
+// least recently seen ones being forgotten first.
+const maxProbeSources = 20
+
+// RequestStats are the health requests served, included in the detailed
+// JSON report to show which load balancers and kubelets actually probe the
+// service and to spot rogue scrapers.
+type RequestStats struct {
+	// Total counts the health requests ever served
+	Total uint64 `json:"total"`
+	// Sources are the recent probe sources, most recently seen first
+	Sources []ProbeSource `json:"sources,omitempty"`
+}
+
+// ProbeSource is a client probing the health endpoints.
+type ProbeSource struct {
+	// Address is the remote address of the connection, without the port
+	Address string `json:"address"`
+	// ForwardedFor is the X-Forwarded-For header, for probes going through
+	// a proxy
+	ForwardedFor string `json:"forwarded_for,omitempty"`
+	UserAgent    string `json:"user_agent,omitempty"`
+	// Path is the one last requested
+	Path     string    `json:"path"`
+	Count    uint64    `json:"count"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// probeLog records the sources of health requests.
+type probeLog struct {
+	mutex   sync.Mutex
+	total   uint64
+	sources []ProbeSource
+}
+
+// recordProbe notes r as a health request.
+func (h *Health) recordProbe(r *http.Request) {
+	if r == nil {
+		return
+	}
+
+	address := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		address = host
+	}
+	source := ProbeSource{
+		Address:      address,
+		ForwardedFor: r.Header.Get("X-Forwarded-For"),
+		UserAgent:    r.UserAgent(),
+		Path:         r.URL.Path,
+		LastSeen:     h.getClock().Now(),
+	}
+
+	log := &h.probes
+	log.mutex.Lock()
+	defer log.mutex.Unlock()
+
+	log.total++
+	i := slices.IndexFunc(log.sources, func(s ProbeSource) bool {
+		return s.Address == source.Address && s.ForwardedFor == source.ForwardedFor && s.UserAgent == source.UserAgent
+	})
+	if i >= 0 {
+		source.Count = log.sources[i].Count
+		log.sources = slices.Delete(log.sources, i, i+1)
+	} else if len(log.sources) == maxProbeSources {
+		log.sources = log.sources[:maxProbeSources-1]
+	}
+	source.Count++
+	log.sources = slices.Insert(log.sources, 0, source)
+}
+
+// reset forgets the recorded requests.
+func (log *probeLog) reset() {
+	log.mutex.Lock()
+	defer log.mutex.Unlock()
+
+	log.total = 0
+	log.sources = nil
+}
+
+// requestStats returns a copy of the recorded requests.
+func (h *Health) requestStats() *RequestStats {
+	log := &h.probes
+	log.mutex.Lock()
+	defer log.mutex.Unlock()
+
+	return &RequestStats{Total: log.total, Sources: slices.Clone(log.sources)}
+}
@@ -0,0 +1,80 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProbeSources(t *testing.T) {
+	h := &Health{status: Up}
+
+	get := func(target, addr, agent, token string) responseBody {
+		r := httptest.NewRequest("GET", target, nil)
+		r.RemoteAddr = addr
+		r.Header.Set("User-Agent", agent)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, r)
+
+		var report responseBody
+		_ = json.Unmarshal(rr.Body.Bytes(), &report)
+		return report
+	}
+
+	get("/health", "10.0.0.1:4321", "kube-probe/1.30", "")
+	get("/health", "10.0.0.1:4322", "kube-probe/1.30", "")
+	report := get("/health?verbose=1", "10.0.0.2:80", "ELB-HealthChecker/2.0", "")
+
+	if report.Requests == nil {
+		t.Fatal("verbose report lacks the request stats")
+	}
+	if report.Requests.Total != 3 || len(report.Requests.Sources) != 2 {
+		t.Fatalf("unexpected request stats: %+v", report.Requests)
+	}
+	if s := report.Requests.Sources[0]; s.Address != "10.0.0.2" || s.UserAgent != "ELB-HealthChecker/2.0" || s.Count != 1 {
+		t.Errorf("unexpected most recent source: %+v", s)
+	}
+	if s := report.Requests.Sources[1]; s.Address != "10.0.0.1" || s.UserAgent != "kube-probe/1.30" || s.Count != 2 {
+		t.Errorf("unexpected kubelet source: %+v", s)
+	}
+
+	// With admin auth only admins get them
+	h.WithAdminAuth(BearerToken("s3cret"))
+	h.WithJSON(true)
+	if report := get("/health", "10.0.0.3:80", "curl/8.0", ""); report.Requests != nil {
+		t.Errorf("request stats shown to a non-admin: %+v", report.Requests)
+	}
+	if report := get("/health", "10.0.0.3:80", "curl/8.0", "s3cret"); report.Requests == nil || report.Requests.Total != 5 {
+		t.Errorf("unexpected request stats for an admin: %+v", report.Requests)
+	}
+}
+
+func TestProbeSourcesBounded(t *testing.T) {
+	h := &Health{status: Up}
+	for i := range maxProbeSources + 5 {
+		r := httptest.NewRequest("GET", "/health", nil)
+		r.Header.Set("User-Agent", string(rune('a'+i)))
+		h.recordProbe(r)
+	}
+
+	stats := h.requestStats()
+	if len(stats.Sources) != maxProbeSources || stats.Total != maxProbeSources+5 {
+		t.Errorf("unexpected request stats: total %d, %d sources", stats.Total, len(stats.Sources))
+	}
+	if stats.Sources[0].UserAgent != string(rune('a'+maxProbeSources+4)) {
+		t.Errorf("most recent source not first: %+v", stats.Sources[0])
+	}
+}
+
+func TestResetProbeSources(t *testing.T) {
+	h := &Health{status: Up}
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/health", nil))
+
+	h.Reset()
+	if stats := h.requestStats(); stats.Total != 0 || len(stats.Sources) != 0 {
+		t.Errorf("Reset should clear the probe log: %+v", stats)
+	}
+}
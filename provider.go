@@ -0,0 +1,128 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// ProviderFactory builds a check from its configuration, the raw JSON of the
+// "config" field of a check in a config file.
+type ProviderFactory func(config json.RawMessage) (CheckFunc, error)
+
+// CheckConfig is a check declared in a config file.
+type CheckConfig struct {
+	Name string `json:"name"`
+	// Type is the name of the provider building the check
+	Type   string          `json:"type"`
+	Config json.RawMessage `json:"config,omitempty"`
+}
+
+// Config is the content of a checks config file:
+//
+//	{"checks": [{"name": "orders-db", "type": "postgres", "config": {...}}]}
+type Config struct {
+	Checks []CheckConfig `json:"checks"`
+}
+
+var (
+	providersMutex sync.RWMutex
+	providers      = map[string]ProviderFactory{}
+)
+
+// RegisterProvider makes a check type available to every handler's config
+// loader. It is meant to be called from the init function of packages
+// providing checks, so importing them is enough to use their types.
+// Registering a name twice replaces the previous factory.
+func RegisterProvider(name string, factory ProviderFactory) {
+	providersMutex.Lock()
+	defer providersMutex.Unlock()
+
+	providers[name] = factory
+}
+
+// RegisterProvider makes a check type available to this handler's config
+// loader only, taking precedence over a global provider of the same name.
+func (h *Health) RegisterProvider(name string, factory ProviderFactory) *Health {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.providers == nil {
+		h.providers = make(map[string]ProviderFactory)
+	}
+	h.providers[name] = factory
+	return h
+}
+
+// provider looks up a check type, on the handler first, then globally.
+func (h *Health) provider(name string) (ProviderFactory, bool) {
+	h.mutex.RLock()
+	factory, ok := h.providers[name]
+	h.mutex.RUnlock()
+	if ok {
+		return factory, true
+	}
+
+	providersMutex.RLock()
+	factory, ok = providers[name]
+	providersMutex.RUnlock()
+	return factory, ok
+}
+
+// LoadChecks registers the checks of a config file on the default handler.
+func LoadChecks(r io.Reader) error {
+	return handler.LoadChecks(r)
+}
+
+// LoadChecksFile registers the checks of the config file at path on the
+// default handler.
+func LoadChecksFile(path string) error {
+	return handler.LoadChecksFile(path)
+}
+
+// LoadChecksFile registers the checks of the config file at path.
+func (h *Health) LoadChecksFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return h.LoadChecks(f)
+}
+
+// LoadChecks reads a JSON config (see Config) and registers its checks,
+// instantiating each one through the provider named by its type. Nothing is
+// registered unless every check can be built.
+func (h *Health) LoadChecks(r io.Reader) error {
+	var config Config
+	if err := json.NewDecoder(r).Decode(&config); err != nil {
+		return fmt.Errorf("health: reading checks config: %w", err)
+	}
+
+	checks := make([]namedCheck, 0, len(config.Checks))
+	for _, c := range config.Checks {
+		if c.Name == "" {
+			return fmt.Errorf("health: check of type %q has no name", c.Type)
+		}
+
+		factory, ok := h.provider(c.Type)
+		if !ok {
+			return fmt.Errorf("health: check %q: unknown type %q", c.Name, c.Type)
+		}
+
+		check, err := factory(c.Config)
+		if err != nil {
+			return fmt.Errorf("health: check %q: %w", c.Name, err)
+		}
+		checks = append(checks, namedCheck{name: c.Name, check: adaptCheck(check)})
+	}
+
+	for _, c := range checks {
+		h.RegisterResult(c.name, c.check)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// staticProvider builds checks failing with the configured error message.
+func staticProvider(config json.RawMessage) (CheckFunc, error) {
+	var settings struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(config, &settings); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context) error {
+		if settings.Error != "" {
+			return errors.New(settings.Error)
+		}
+		return nil
+	}, nil
+}
+
+func TestLoadChecks(t *testing.T) {
+	RegisterProvider("test-static", staticProvider)
+
+	h := &Health{status: Up}
+	err := h.LoadChecks(strings.NewReader(`{"checks": [
+		{"name": "ok", "type": "test-static", "config": {}},
+		{"name": "broken", "type": "test-static", "config": {"error": "refused"}}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := h.Evaluate(context.Background())
+	if len(results) != 2 || results[0].Status != Down || results[0].Reason != "refused" || results[1].Status != Up {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
+
+func TestInstanceProviderTakesPrecedence(t *testing.T) {
+	RegisterProvider("test-shadowed", func(config json.RawMessage) (CheckFunc, error) {
+		return nil, errors.New("global provider used")
+	})
+
+	h := &Health{status: Up}
+	h.RegisterProvider("test-shadowed", staticProvider)
+
+	if err := h.LoadChecks(strings.NewReader(`{"checks": [{"name": "db", "type": "test-shadowed", "config": {}}]}`)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadChecksErrors(t *testing.T) {
+	RegisterProvider("test-static", staticProvider)
+
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{"invalid json", `{"checks": [`, "reading checks config"},
+		{"unknown type", `{"checks": [{"name": "db", "type": "nope"}]}`, `unknown type "nope"`},
+		{"missing name", `{"checks": [{"type": "test-static"}]}`, "has no name"},
+		{"factory error", `{"checks": [{"name": "db", "type": "test-static", "config": "bad"}]}`, `check "db"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Health{status: Up}
+			err := h.LoadChecks(strings.NewReader(tt.config))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want an error containing %q", err, tt.want)
+			}
+			if results := h.Evaluate(context.Background()); len(results) != 0 {
+				t.Errorf("nothing should be registered on error, got %+v", results)
+			}
+		})
+	}
+}
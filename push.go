@@ -0,0 +1,67 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// defaultPushTimeout bounds a single push.
+const defaultPushTimeout = 5 * time.Second
+
+// PushTo pushes the default handler's health to url. See
+// (*Health).PushTo.
+func PushTo(ctx context.Context, url string, interval time.Duration) error {
+	return handler.PushTo(ctx, url, interval)
+}
+
+// PushTo POSTs the handler's health to url every interval until ctx is
+// done, in the JSON format RemoteStatus parses, such as to the instance URL
+// of a fleet aggregation server. Failed pushes are retried at the next
+// interval: the receiving end tracks freshness, so missed pushes show up
+// there. It returns nil once ctx is done, or an error right away when the
+// interval isn't positive.
+func (h *Health) PushTo(ctx context.Context, url string, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("health: push interval must be positive, got %s", interval)
+	}
+
+	ticker := h.getClock().NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		h.push(ctx, url)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+		}
+	}
+}
+
+func (h *Health) push(ctx context.Context, url string) {
+	status, reason, results := h.overall()
+	body, err := json.Marshal(RemoteStatus{Status: status, Reason: reason, Checks: results})
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultPushTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+}
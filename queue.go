@@ -0,0 +1,50 @@
+package health
+
+import "sync"
+
+// queue delivers values in order, from a goroutine that only runs while
+// values are pending, so producers holding the handler's mutex never wait on
+// slow consumers. Past its limit, the oldest values are dropped.
+type queue[T any] struct {
+	deliver func(T)
+	limit   int
+
+	mutex   sync.Mutex
+	pending []T
+	running bool
+}
+
+func newQueue[T any](limit int, deliver func(T)) *queue[T] {
+	return &queue[T]{deliver: deliver, limit: limit}
+}
+
+func (q *queue[T]) push(v T) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if len(q.pending) >= q.limit {
+		q.pending = q.pending[1:]
+	}
+	q.pending = append(q.pending, v)
+
+	if !q.running {
+		q.running = true
+		go q.run()
+	}
+}
+
+func (q *queue[T]) run() {
+	for {
+		q.mutex.Lock()
+		if len(q.pending) == 0 {
+			q.running = false
+			q.mutex.Unlock()
+			return
+		}
+		v := q.pending[0]
+		q.pending = q.pending[1:]
+		q.mutex.Unlock()
+
+		q.deliver(v)
+	}
+}
@@ -0,0 +1,36 @@
+package health
+
+import "testing"
+
+func TestQueueDropsOldest(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	var got []Transition
+	done := make(chan struct{})
+	q := newQueue(maxPendingNotifications, func(tr Transition) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		got = append(got, tr)
+		if tr.Reason == "last" {
+			close(done)
+		}
+	})
+
+	// The first transition is picked up straight away and blocks the
+	// notifier, the following ones overflow the queue.
+	q.push(Transition{Reason: "first"})
+	<-started
+	for i := 0; i < maxPendingNotifications+5; i++ {
+		q.push(Transition{})
+	}
+	q.push(Transition{Reason: "last"})
+	close(release)
+	<-done
+
+	if got[0].Reason != "first" || len(got) != maxPendingNotifications+1 {
+		t.Errorf("expected the first and %d latest transitions, got %d", maxPendingNotifications, len(got))
+	}
+}
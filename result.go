@@ -0,0 +1,65 @@
+package health
+
+import (
+	"context"
+	"time"
+)
+
+// Result is what a ResultFunc reports: more than pass or fail, it can carry
+// what the check measured.
+type Result struct {
+	// Status is derived from Err when empty: UP without an error, otherwise
+	// as for the error returned by a CheckFunc
+	Status Status
+	// Message is the reason given with the status, Err's message if empty
+	Message string
+	// Latency is what the check measured of its dependency, such as a
+	// round trip
+	Latency time.Duration
+	// Details are added to those set with SetDetail
+	Details map[string]any
+	Err     error
+}
+
+// ResultFunc checks a single dependency and reports a Result, for checks
+// conveying measurements rather than just pass or fail:
+//
+//	health.RegisterResult("replica", func(ctx context.Context) health.Result {
+//		lag, err := replicationLag(ctx)
+//		return health.Result{Err: err, Details: map[string]any{"lag_seconds": lag.Seconds()}}
+//	})
+type ResultFunc func(ctx context.Context) Result
+
+// adaptCheck turns a CheckFunc into a ResultFunc reporting its error.
+func adaptCheck(check CheckFunc) ResultFunc {
+	return func(ctx context.Context) Result {
+		return Result{Err: check(ctx)}
+	}
+}
+
+// Checker is a check with a name of its own, for advanced checks carrying
+// state or structured metadata (replication lag, pool usage, ...) into the
+// JSON report:
+//
+//	type replicaChecker struct{ db *sql.DB }
+//
+//	func (c replicaChecker) Name() string { return "replica" }
+//
+//	func (c replicaChecker) Check(ctx context.Context) health.Result {
+//		lag, err := replicationLag(ctx, c.db)
+//		return health.Result{Err: err, Details: map[string]any{"lag_seconds": lag.Seconds()}}
+//	}
+type Checker interface {
+	Name() string
+	Check(ctx context.Context) Result
+}
+
+// RegisterChecker adds a Checker to the default handler.
+func RegisterChecker(c Checker, opts ...CheckOption) {
+	handler.RegisterChecker(c, opts...)
+}
+
+// RegisterChecker adds a Checker under its name, like RegisterResult.
+func (h *Health) RegisterChecker(c Checker, opts ...CheckOption) *Health {
+	return h.RegisterResult(c.Name(), c.Check, opts...)
+}
@@ -0,0 +1,70 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRegisterResult(t *testing.T) {
+	h := New()
+	h.RegisterResult("replica", func(ctx context.Context) Result {
+		SetDetail(ctx, "host", "replica-1")
+		return Result{
+			Status:  Degraded,
+			Message: "replication lagging",
+			Latency: 40 * time.Millisecond,
+			Details: map[string]any{"lag_seconds": 12.5},
+		}
+	})
+	h.RegisterResult("primary", func(ctx context.Context) Result {
+		return Result{Err: errors.New("connection refused")}
+	})
+	h.RegisterResult("cache", func(ctx context.Context) Result {
+		return Result{Latency: time.Millisecond}
+	})
+
+	results := h.Evaluate(context.Background())
+
+	cache, primary, replica := results[0], results[1], results[2]
+	if cache.Status != Up || cache.Latency != time.Millisecond {
+		t.Errorf("unexpected cache result %+v", cache)
+	}
+	if primary.Status != Down || primary.Reason != "connection refused" {
+		t.Errorf("the status should be derived from the error, got %+v", primary)
+	}
+	if replica.Status != Degraded || replica.Reason != "replication lagging" || replica.Latency != 40*time.Millisecond {
+		t.Errorf("unexpected replica result %+v", replica)
+	}
+	if replica.Details["host"] != "replica-1" || replica.Details["lag_seconds"] != 12.5 {
+		t.Errorf("expected the details set and returned, got %v", replica.Details)
+	}
+}
+
+type lagChecker struct {
+	lag time.Duration
+}
+
+func (c lagChecker) Name() string { return "replication" }
+
+func (c lagChecker) Check(ctx context.Context) Result {
+	result := Result{Details: map[string]any{"lag_seconds": c.lag.Seconds()}}
+	if c.lag > time.Minute {
+		result.Err = errors.New("replica lagging")
+	}
+	return result
+}
+
+func TestRegisterChecker(t *testing.T) {
+	h := New()
+	h.RegisterChecker(lagChecker{lag: 2 * time.Minute}, WithOwner("storage-team"))
+
+	r := h.Evaluate(context.Background())[0]
+	if r.Name != "replication" || r.Status != Down || r.Owner != "storage-team" {
+		t.Errorf("unexpected result %+v", r)
+	}
+	if r.Details["lag_seconds"] != 120.0 {
+		t.Errorf("expected the checker's details, got %v", r.Details)
+	}
+}
@@ -0,0 +1,24 @@
+package health
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+type runIDKey struct{}
+
+// newRunID returns a random identifier for an evaluation cycle.
+func newRunID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// RunID returns the identifier of the evaluation cycle a check runs in, so
+// checks can include it in their logs. It is empty when ctx doesn't belong
+// to a running check.
+func RunID(ctx context.Context) string {
+	id, _ := ctx.Value(runIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,45 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRunID(t *testing.T) {
+	h := &Health{status: Up, useJSON: true}
+	var seen string
+	h.Register("db", func(ctx context.Context) error {
+		seen = RunID(ctx)
+		return nil
+	})
+	h.Register("cache", func(ctx context.Context) error { return nil })
+
+	results := h.Evaluate(context.Background())
+	if seen == "" || results[0].RunID != seen || results[1].RunID != seen {
+		t.Fatalf("expected every result to carry the run ID %q, got %+v", seen, results)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+	var body responseBody
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.RunID != seen {
+		t.Errorf("expected the response run ID %q, got %q", seen, body.RunID)
+	}
+
+	// A partial evaluation leaves the other results with their own cycle
+	first := seen
+	h.evaluate(context.Background(), selection{only: map[string]bool{"db": true}})
+	results = h.Results()
+	if results[1].RunID == first || results[0].RunID != first {
+		t.Errorf("expected db in a new cycle and cache in the old one, got %+v", results)
+	}
+
+	if RunID(context.Background()) != "" {
+		t.Error("expected no run ID outside of a check")
+	}
+}
@@ -0,0 +1,75 @@
+package health
+
+import (
+	"os"
+	"runtime"
+	"time"
+)
+
+// RuntimeStats are basic process vitals included in the JSON report when
+// enabled with WithRuntime.
+type RuntimeStats struct {
+	GoVersion  string `json:"go_version"`
+	NumCPU     int    `json:"num_cpu"`
+	Goroutines int    `json:"goroutines"`
+
+	HeapAlloc uint64 `json:"heap_alloc_bytes"`
+	HeapSys   uint64 `json:"heap_sys_bytes"`
+	Sys       uint64 `json:"sys_bytes"`
+
+	NumGC        uint32        `json:"num_gc"`
+	LastGC       time.Time     `json:"last_gc,omitempty"`
+	GCPauseTotal time.Duration `json:"gc_pause_total"`
+
+	// OpenFDs is the number of open file descriptors, where the platform
+	// exposes it
+	OpenFDs int `json:"open_fds,omitempty"`
+}
+
+// WithRuntime includes a runtime section (goroutines, memory, GC, open file
+// descriptors, ...) in the JSON report, giving first responders basic
+// process vitals from the endpoint they already hit. Reading the memory
+// statistics briefly stops the world, so it's off by default.
+func (h *Health) WithRuntime(v bool) *Health {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.includeRuntime = v
+	return h
+}
+
+func readRuntimeStats() *RuntimeStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	stats := &RuntimeStats{
+		GoVersion:  runtime.Version(),
+		NumCPU:     runtime.NumCPU(),
+		Goroutines: runtime.NumGoroutine(),
+
+		HeapAlloc: mem.HeapAlloc,
+		HeapSys:   mem.HeapSys,
+		Sys:       mem.Sys,
+
+		NumGC:        mem.NumGC,
+		GCPauseTotal: time.Duration(mem.PauseTotalNs),
+		OpenFDs:      openFDs(),
+	}
+	if mem.LastGC > 0 {
+		stats.LastGC = time.Unix(0, int64(mem.LastGC))
+	}
+
+	return stats
+}
+
+// openFDs counts the open file descriptors on platforms with a /proc or /dev
+// file descriptor directory, and returns 0 elsewhere.
+func openFDs() int {
+	for _, dir := range []string{"/proc/self/fd", "/dev/fd"} {
+		if entries, err := os.ReadDir(dir); err == nil {
+			// Reading the directory opens one descriptor of its own
+			return len(entries) - 1
+		}
+	}
+	return 0
+}
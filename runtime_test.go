@@ -0,0 +1,42 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+)
+
+func TestRuntimeSection(t *testing.T) {
+	h := &Health{status: Up, useJSON: true}
+
+	get := func() responseBody {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest("GET", "/health", nil))
+
+		var response responseBody
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+		return response
+	}
+
+	if response := get(); response.Runtime != nil {
+		t.Error("runtime section should be off by default")
+	}
+
+	h.WithRuntime(true)
+	stats := get().Runtime
+	if stats == nil {
+		t.Fatal("runtime section missing")
+	}
+	if stats.GoVersion != runtime.Version() || stats.NumCPU != runtime.NumCPU() {
+		t.Errorf("unexpected runtime info: %+v", stats)
+	}
+	if stats.Goroutines <= 0 || stats.HeapAlloc == 0 || stats.Sys == 0 {
+		t.Errorf("missing process vitals: %+v", stats)
+	}
+	if runtime.GOOS == "linux" && stats.OpenFDs <= 0 {
+		t.Errorf("expected open file descriptors on linux, got %d", stats.OpenFDs)
+	}
+}
@@ -0,0 +1,125 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Start runs the checks registered on the default handler every interval
+// until ctx is done. See (*Health).Start.
+func Start(ctx context.Context, interval time.Duration) error {
+	return handler.Start(ctx, interval)
+}
+
+// Stop stops the default handler's background evaluation. See
+// (*Health).Stop.
+func Stop(ctx context.Context) error {
+	return handler.Stop(ctx)
+}
+
+// Start evaluates the registered checks immediately and then every interval
+// in a background goroutine, until ctx is done or Stop is called. Ticks come
+// from the handler's clock, so a fake clock controls when evaluations happen.
+// The interval is also the default Retry-After of 503 responses. It returns
+// an error, without starting anything, when the interval isn't positive.
+func (h *Health) Start(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("health: evaluation interval must be positive, got %s", interval)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	h.mutex.Lock()
+	h.interval = interval
+	h.stops = append(h.stops, cancel)
+	h.mutex.Unlock()
+
+	ticker := h.getClock().NewTicker(interval)
+
+	h.running.add()
+	go func() {
+		defer h.running.done()
+		defer cancel()
+		defer ticker.Stop()
+
+		h.Evaluate(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				h.Evaluate(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the background evaluation started with Start, cancelling the
+// checks in flight, and waits for them to return so no check goroutine
+// outlives a server shutdown or a test. Checks run by Evaluate are waited
+// for too. It returns the context's error when the deadline comes first,
+// with checks that ignore their context still running.
+func (h *Health) Stop(ctx context.Context) error {
+	h.mutex.Lock()
+	stops := h.stops
+	h.stops = nil
+	h.interval = 0
+	h.mutex.Unlock()
+
+	for _, cancel := range stops {
+		cancel()
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-h.running.idle():
+		return nil
+	}
+}
+
+// goroutines counts running goroutines, telling when none is left. The zero
+// value counts none.
+type goroutines struct {
+	mutex sync.Mutex
+	n     int
+	// none is closed once n drops to zero, and replaced when it goes up
+	// again
+	none chan struct{}
+}
+
+func (g *goroutines) add() {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.n == 0 {
+		g.none = make(chan struct{})
+	}
+	g.n++
+}
+
+func (g *goroutines) done() {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.n--
+	if g.n == 0 {
+		close(g.none)
+	}
+}
+
+// idle returns a channel closed once no goroutine is running.
+func (g *goroutines) idle() <-chan struct{} {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.n == 0 {
+		none := make(chan struct{})
+		close(none)
+		return none
+	}
+	return g.none
+}
@@ -0,0 +1,146 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStop(t *testing.T) {
+	h := New()
+
+	started := make(chan struct{}, 1)
+	returned := make(chan struct{})
+	h.Register("slow", func(ctx context.Context) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-ctx.Done()
+		// Take a while to return, like a driver cleaning up
+		time.Sleep(50 * time.Millisecond)
+		close(returned)
+		return ctx.Err()
+	})
+
+	h.Start(context.Background(), time.Hour)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := h.Stop(ctx); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	select {
+	case <-returned:
+	default:
+		t.Error("Stop returned before the check in flight")
+	}
+	select {
+	case <-h.running.idle():
+	default:
+		t.Error("expected nothing running")
+	}
+}
+
+func TestStopDeadline(t *testing.T) {
+	h := New()
+
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	h.Register("stuck", func(ctx context.Context) error {
+		close(started)
+		<-release // ignores its context
+		return nil
+	})
+
+	h.Start(context.Background(), time.Hour)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := h.Stop(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected the deadline to pass with a check stuck, got %v", err)
+	}
+}
+
+func TestStartInvalidInterval(t *testing.T) {
+	h := New()
+	if err := h.Start(context.Background(), 0); err == nil {
+		t.Error("expected an error for a zero interval")
+	}
+	if len(h.stops) != 0 {
+		t.Error("nothing should be started")
+	}
+}
+
+func TestResetStops(t *testing.T) {
+	h := New()
+
+	started := make(chan struct{}, 1)
+	h.Register("slow", func(ctx context.Context) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err := h.Start(context.Background(), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	h.Reset()
+	if len(h.stops) != 0 {
+		t.Error("Reset should drop the schedulers")
+	}
+	select {
+	case <-h.running.idle():
+	case <-time.After(time.Second):
+		t.Error("Reset should cancel the background evaluation")
+	}
+}
+
+func TestResetDropsStaleResults(t *testing.T) {
+	h := New()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	h.Register("slow", func(ctx context.Context) error {
+		close(started)
+		<-release
+		return errors.New("gone")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go h.Escalate(ctx, EscalationFunc(func(ctx context.Context, e Escalation) error { return nil }))
+	waitEscalations(h, 1)
+
+	evaluated := make(chan struct{})
+	go func() {
+		defer close(evaluated)
+		h.Evaluate(context.Background())
+	}()
+	<-started
+
+	h.Reset()
+	close(release)
+	<-evaluated
+
+	if results := h.Results(); len(results) != 0 {
+		t.Errorf("an evaluation spanning Reset recorded its results: %+v", results)
+	}
+	if transitions := h.History().Transitions(); len(transitions) != 0 {
+		t.Errorf("an evaluation spanning Reset recorded transitions: %+v", transitions)
+	}
+	h.mutex.RLock()
+	escalations := len(h.escalations)
+	h.mutex.RUnlock()
+	if escalations != 0 {
+		t.Errorf("Reset should drop the escalations, %d left", escalations)
+	}
+}
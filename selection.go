@@ -0,0 +1,89 @@
+package health
+
+import (
+	"net/http"
+	"strings"
+)
+
+// selection restricts the checks a request evaluates and reports, with the
+// ?checks=db,redis and ?exclude=slow-batch parameters of the JSON report.
+// The zero selection includes every check.
+type selection struct {
+	only    map[string]bool
+	exclude map[string]bool
+}
+
+// parseSelection reads the selection parameters of r, which may be nil.
+func parseSelection(r *http.Request) selection {
+	// Probes don't send parameters, don't parse anything for them
+	if r == nil || r.URL == nil || r.URL.RawQuery == "" {
+		return selection{}
+	}
+
+	query := r.URL.Query()
+	return selection{
+		only:    nameSet(query["checks"]),
+		exclude: nameSet(query["exclude"]),
+	}
+}
+
+// detailedSelection is the selection of r if it gets the JSON report (or
+// asks for the verbose one), and the zero selection for the plain text one.
+func (h *Health) detailedSelection(r *http.Request, useJSON bool) selection {
+	if !useJSON && !h.verbose(r) {
+		return selection{}
+	}
+	return parseSelection(r)
+}
+
+// nameSet collects the comma-separated names of the given parameter values,
+// nil when there are none.
+func nameSet(values []string) map[string]bool {
+	var names map[string]bool
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name == "" {
+				continue
+			}
+			if names == nil {
+				names = make(map[string]bool)
+			}
+			names[name] = true
+		}
+	}
+	return names
+}
+
+func (s selection) active() bool {
+	return s.only != nil || s.exclude != nil
+}
+
+func (s selection) includes(name string) bool {
+	if s.only != nil && !s.only[name] {
+		return false
+	}
+	return !s.exclude[name]
+}
+
+// mergeResults combines the results of a partial evaluation with the
+// previous ones, in registration order.
+func mergeResults(checks []namedCheck, previous, fresh []CheckResult) []CheckResult {
+	merged := make([]CheckResult, 0, len(checks))
+	for _, c := range checks {
+		if result, ok := findResult(fresh, c.name); ok {
+			merged = append(merged, result)
+		} else if result, ok := findResult(previous, c.name); ok {
+			merged = append(merged, result)
+		}
+	}
+	return merged
+}
+
+func findResult(results []CheckResult, name string) (CheckResult, bool) {
+	for _, result := range results {
+		if result.Name == name {
+			return result, true
+		}
+	}
+	return CheckResult{}, false
+}
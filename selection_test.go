@@ -0,0 +1,68 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCheckSelection(t *testing.T) {
+	calls := map[string]int{}
+	counted := func(name string, err error) CheckFunc {
+		return func(ctx context.Context) error {
+			calls[name]++
+			return err
+		}
+	}
+
+	h := &Health{status: Up, useJSON: true}
+	h.WithOnDemand(time.Second)
+	h.Register("db", counted("db", nil))
+	h.Register("redis", counted("redis", nil))
+	h.Register("slow-batch", counted("slow-batch", errors.New("backlog")))
+
+	get := func(query string) responseBody {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health"+query, nil))
+
+		var body responseBody
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		return body
+	}
+
+	body := get("?checks=db,redis")
+	if body.Status != string(Up) || len(body.Checks) != 2 {
+		t.Errorf("expected UP with 2 checks, got %s with %+v", body.Status, body.Checks)
+	}
+	if calls["slow-batch"] != 0 {
+		t.Errorf("unselected check was evaluated %d times", calls["slow-batch"])
+	}
+
+	body = get("?exclude=slow-batch")
+	if body.Status != string(Up) || len(body.Checks) != 2 {
+		t.Errorf("expected UP with 2 checks, got %s with %+v", body.Status, body.Checks)
+	}
+
+	body = get("?checks=db&checks=slow-batch&exclude=db")
+	if body.Status != string(Down) || len(body.Checks) != 1 || body.Checks[0].Name != "slow-batch" {
+		t.Errorf("expected only slow-batch, DOWN, got %s with %+v", body.Status, body.Checks)
+	}
+
+	// Partial evaluations keep the other results, in registration order
+	results := h.Results()
+	if len(results) != 3 || results[0].Name != "db" || results[2].Name != "slow-batch" {
+		t.Errorf("unexpected stored results: %+v", results)
+	}
+
+	body = get("")
+	if len(body.Checks) != 3 || calls["db"] != 3 {
+		t.Errorf("expected every check evaluated and reported, got %+v (db ran %d times)", body.Checks, calls["db"])
+	}
+}
@@ -0,0 +1,123 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// serverShutdownTimeout bounds how long a dedicated health server waits for
+// in-flight probes when its context is done.
+const serverShutdownTimeout = 5 * time.Second
+
+// WithH2C makes the dedicated health server also speak HTTP/2 over
+// cleartext, so gRPC health probes and mesh sidecars can multiplex over it
+// without TLS.
+func (h *Health) WithH2C(enabled bool) *Health {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.h2c = enabled
+	return h
+}
+
+// ListenAndServe runs a dedicated health server for the default handler.
+// See (*Health).ListenAndServe.
+func ListenAndServe(ctx context.Context, addrs ...string) error {
+	return handler.ListenAndServe(ctx, addrs...)
+}
+
+// Listen binds every address, like an IPv4 and an IPv6 one, or localhost
+// and the pod IP. It returns the listeners it could bind along with an error
+// joining the failure of every other address, so callers can choose to serve
+// on whatever could be bound.
+func Listen(addrs ...string) ([]net.Listener, error) {
+	var listeners []net.Listener
+	var errs []error
+	for _, addr := range addrs {
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners, errors.Join(errs...)
+}
+
+// ListenAndServe runs a dedicated health server on every one of addrs, apart
+// from the application's own server, until ctx is done. It serves the
+// handler on /health along with the Kubernetes probe endpoints. When an
+// address can't be bound it serves nothing and returns the error of every
+// failed address; use Listen and Serve to serve on the others regardless.
+// It returns nil once shut down by ctx.
+func (h *Health) ListenAndServe(ctx context.Context, addrs ...string) error {
+	listeners, err := Listen(addrs...)
+	if err != nil {
+		for _, listener := range listeners {
+			listener.Close()
+		}
+		return err
+	}
+	return h.Serve(ctx, listeners...)
+}
+
+// Serve is like ListenAndServe on existing listeners, which it closes. When
+// one of them fails the server shuts down on all of them.
+func (h *Health) Serve(ctx context.Context, listeners ...net.Listener) error {
+	server := h.newServer()
+
+	served := make(chan error, len(listeners))
+	for _, listener := range listeners {
+		go func() {
+			served <- server.Serve(listener)
+		}()
+	}
+
+	var errs []error
+	select {
+	case <-ctx.Done():
+	case err := <-served:
+		errs = append(errs, err)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serverShutdownTimeout)
+	defer cancel()
+	_ = server.Shutdown(shutdownCtx)
+
+	for len(errs) < len(listeners) {
+		errs = append(errs, <-served)
+	}
+
+	errs = slices.DeleteFunc(errs, func(err error) bool {
+		return errors.Is(err, http.ErrServerClosed)
+	})
+	return errors.Join(errs...)
+}
+
+// newServer builds the dedicated health server.
+func (h *Health) newServer() *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/health", h)
+	h.KubernetesDefaults(mux, 0)
+
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	h.mutex.RLock()
+	h2c := h.h2c
+	h.mutex.RUnlock()
+
+	if h2c {
+		server.Protocols = new(http.Protocols)
+		server.Protocols.SetHTTP1(true)
+		server.Protocols.SetUnencryptedHTTP2(true)
+	}
+
+	return server
+}
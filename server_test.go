@@ -0,0 +1,103 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestServeH2C(t *testing.T) {
+	h := &Health{status: Up}
+	h.WithH2C(true)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- h.Serve(ctx, listener)
+	}()
+
+	protocols := new(http.Protocols)
+	protocols.SetUnencryptedHTTP2(true)
+	client := &http.Client{Transport: &http.Transport{Protocols: protocols}}
+
+	for _, path := range []string{"/health", ReadinessPath} {
+		resp, err := client.Get("http://" + listener.Addr().String() + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || resp.ProtoMajor != 2 {
+			t.Errorf("%s: got %d over %s, want 200 over HTTP/2", path, resp.StatusCode, resp.Proto)
+		}
+	}
+
+	cancel()
+	if err := <-served; err != nil {
+		t.Errorf("unexpected error after shutdown: %v", err)
+	}
+}
+
+func TestServeMultipleListeners(t *testing.T) {
+	h := &Health{status: Up}
+
+	addrs := []string{"127.0.0.1:0", "[::1]:0"}
+	listeners, err := Listen(addrs...)
+	if err != nil {
+		// Not every sandbox has IPv6 loopback
+		t.Logf("serving on a single address: %v", err)
+	}
+	if len(listeners) == 0 {
+		t.Fatal("no listener bound")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- h.Serve(ctx, listeners...)
+	}()
+
+	for _, listener := range listeners {
+		resp, err := http.Get("http://" + listener.Addr().String() + "/health")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("%s: got %d", listener.Addr(), resp.StatusCode)
+		}
+	}
+
+	cancel()
+	if err := <-served; err != nil {
+		t.Errorf("unexpected error after shutdown: %v", err)
+	}
+}
+
+func TestListenAndServeBindErrors(t *testing.T) {
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer taken.Close()
+
+	h := &Health{status: Up}
+	err = h.ListenAndServe(context.Background(), "127.0.0.1:0", taken.Addr().String(), "invalid")
+
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		t.Fatalf("expected the bind errors, got %v", err)
+	}
+	for _, addr := range []string{taken.Addr().String(), "invalid"} {
+		if !strings.Contains(err.Error(), addr) {
+			t.Errorf("expected the error to name %s, got %v", addr, err)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package health
+
+import (
+	"context"
+	"errors"
+)
+
+// Severity grades the outcome of a check, for checks that have a warning
+// zone before hard failure (a disk filling up, a certificate nearing
+// expiry).
+type Severity string
+
+const (
+	// SeverityOK means the check passed
+	SeverityOK Severity = "OK"
+	// SeverityWarn means the check is in its warning zone; it is reported
+	// as DEGRADED
+	SeverityWarn Severity = "WARN"
+	// SeverityCritical means the check failed; it is reported as DOWN
+	SeverityCritical Severity = "CRITICAL"
+)
+
+// SeverityFunc is a check returning a severity and a message explaining it
+// instead of an error.
+type SeverityFunc func(ctx context.Context) (Severity, string)
+
+// SeverityCheck adapts check to a CheckFunc. WARN maps to DEGRADED and
+// CRITICAL (or any unknown severity) to DOWN, with the message as the
+// reason:
+//
+//	health.Register("disk", health.SeverityCheck(func(ctx context.Context) (health.Severity, string) {
+//		switch used := diskUsage(); {
+//		case used > 0.95:
+//			return health.SeverityCritical, "disk almost full"
+//		case used > 0.8:
+//			return health.SeverityWarn, "disk filling up"
+//		}
+//		return health.SeverityOK, ""
+//	}))
+func SeverityCheck(check SeverityFunc) CheckFunc {
+	return func(ctx context.Context) error {
+		severity, message := check(ctx)
+		if message == "" {
+			message = string(severity)
+		}
+
+		switch severity {
+		case SeverityOK:
+			return nil
+		case SeverityWarn:
+			return DegradedError(errors.New(message))
+		default:
+			return errors.New(message)
+		}
+	}
+}
+
+// SeverityOf grades status: UP is OK, DEGRADED is WARN and everything else
+// is CRITICAL.
+func SeverityOf(status Status) Severity {
+	switch status {
+	case Up:
+		return SeverityOK
+	case Degraded:
+		return SeverityWarn
+	default:
+		return SeverityCritical
+	}
+}
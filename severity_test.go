@@ -0,0 +1,42 @@
+package health
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSeverityCheck(t *testing.T) {
+	h := New()
+	for _, c := range []struct {
+		name     string
+		severity Severity
+		message  string
+	}{
+		{"ok", SeverityOK, ""},
+		{"warn", SeverityWarn, "disk filling up"},
+		{"critical", SeverityCritical, "disk almost full"},
+		{"unknown", Severity("BOGUS"), ""},
+	} {
+		h.Register(c.name, SeverityCheck(func(ctx context.Context) (Severity, string) {
+			return c.severity, c.message
+		}))
+	}
+
+	want := map[string]struct {
+		status Status
+		reason string
+	}{
+		"ok":       {Up, ""},
+		"warn":     {Degraded, "disk filling up"},
+		"critical": {Down, "disk almost full"},
+		"unknown":  {Down, "BOGUS"},
+	}
+	for _, r := range h.Evaluate(context.Background()) {
+		if w := want[r.Name]; r.Status != w.status || r.Reason != w.reason {
+			t.Errorf("%s: got %v %q want %v %q", r.Name, r.Status, r.Reason, w.status, w.reason)
+		}
+		if SeverityOf(r.Status) != map[Status]Severity{Up: SeverityOK, Degraded: SeverityWarn, Down: SeverityCritical}[r.Status] {
+			t.Errorf("%s: unexpected severity %v", r.Name, SeverityOf(r.Status))
+		}
+	}
+}
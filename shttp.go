@@ -0,0 +1,111 @@
+package health
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/andres-vara/shttp"
+)
+
+// RequestIDHeader carries request IDs in requests and responses.
+const RequestIDHeader = "X-Request-ID"
+
+// ShttpRoutes is where MountShttp registers routes, such as *shttp.Server
+// or *shttp.Router.
+type ShttpRoutes interface {
+	GET(path string, handler shttp.Handler)
+}
+
+// ShttpMiddleware returns the default handler's shttp middleware. See
+// (*Health).ShttpMiddleware.
+func ShttpMiddleware() shttp.Middleware {
+	return handler.ShttpMiddleware()
+}
+
+// ShttpMiddleware bundles what an shttp server needs from the handler:
+//
+//   - the request ID, taken from the context (as set by
+//     shttp.RequestIDMiddleware) or the X-Request-ID header, or else
+//     generated, is put in the context and echoed in the response
+//   - the request counts as in flight for DrainAndWait, like with
+//     TrackInFlight, and once draining new requests are rejected with a 503
+//   - responses carry the status headers set with WithStatusHeader
+//
+//	server.Use(health.ShttpMiddleware())
+func (h *Health) ShttpMiddleware() shttp.Middleware {
+	return func(next shttp.Handler) shttp.Handler {
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			id := requestID(ctx)
+			if id == "" {
+				id = r.Header.Get(RequestIDHeader)
+			}
+			if id == "" {
+				id = newRunID()
+			}
+			ctx = context.WithValue(ctx, shttp.RequestIDKey, id)
+			ctx = context.WithValue(ctx, RequestIDKey, id)
+			w.Header().Set(RequestIDHeader, id)
+
+			h.writeStatusHeader(w.Header())
+
+			if h.isDraining() {
+				w.Header().Set("Connection", "close")
+				h.writeHeaders(w.Header(), http.StatusServiceUnavailable)
+				http.Error(w, string(Down)+": draining", http.StatusServiceUnavailable)
+				return nil
+			}
+
+			done := h.RequestStarted()
+			defer done()
+
+			return next(ctx, w, r.WithContext(ctx))
+		}
+	}
+}
+
+// MountShttp registers the default handler's endpoints on an shttp server.
+// See (*Health).MountShttp.
+func MountShttp(routes ShttpRoutes) {
+	handler.MountShttp(routes)
+}
+
+// MountShttp registers the endpoints of Mount (/livez, /readyz, /startupz
+// and /healthz) on an shttp server or router, along with /health in the
+// handler's current format and /health/json:
+//
+//	health.MountShttp(server)
+func (h *Health) MountShttp(routes ShttpRoutes) {
+	format := FormatText
+	if h.jsonFormat() {
+		format = FormatJSON
+	}
+
+	h.MountFunc(func(path string, handler http.Handler) {
+		routes.GET(path, adaptShttp(handler))
+	})
+	routes.GET("/health", adaptShttp(h.FormatHandler(format)))
+	routes.GET("/health/json", adaptShttp(h.FormatHandler(FormatJSON)))
+}
+
+// adaptShttp serves next as an shttp handler, forwarding the request ID.
+func adaptShttp(next http.Handler) shttp.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if id := requestID(ctx); id != "" {
+			w.Header().Set(RequestIDHeader, id)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+		return nil
+	}
+}
+
+// requestID returns the request ID in ctx, whichever key it was set with.
+func requestID(ctx context.Context) string {
+	if id := shttp.GetRequestID(ctx); id != "" {
+		return id
+	}
+	if id, ok := ctx.Value(RequestIDKey).(string); ok {
+		return id
+	}
+	id, _ := ctx.Value("request_id").(string)
+	return id
+}
@@ -0,0 +1,86 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andres-vara/shttp"
+)
+
+func TestShttpMiddleware(t *testing.T) {
+	h := New().WithStatusHeader("X-Health")
+
+	var seen string
+	next := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		seen = shttp.GetRequestID(ctx)
+		if h.InFlight() != 1 {
+			t.Errorf("expected the request to be in flight, got %d", h.InFlight())
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	serve := h.ShttpMiddleware()(next)
+
+	rr := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/orders", nil)
+	r.Header.Set(RequestIDHeader, "abc123")
+	if err := serve(r.Context(), rr, r); err != nil {
+		t.Fatal(err)
+	}
+	if seen != "abc123" || rr.Header().Get(RequestIDHeader) != "abc123" {
+		t.Errorf("request ID not propagated: saw %q, echoed %q", seen, rr.Header().Get(RequestIDHeader))
+	}
+	if rr.Header().Get("X-Health") != string(Up) {
+		t.Errorf("expected the status header, got %v", rr.Header())
+	}
+
+	// Without an ID one is generated
+	rr = httptest.NewRecorder()
+	r = httptest.NewRequest("GET", "/orders", nil)
+	_ = serve(r.Context(), rr, r)
+	if seen == "" || rr.Header().Get(RequestIDHeader) != seen {
+		t.Errorf("expected a generated request ID, saw %q", seen)
+	}
+
+	// Once draining, new requests are turned away
+	if err := h.DrainAndWait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	rr = httptest.NewRecorder()
+	seen = ""
+	r = httptest.NewRequest("GET", "/orders", nil)
+	_ = serve(r.Context(), rr, r)
+	if rr.Code != http.StatusServiceUnavailable || seen != "" {
+		t.Errorf("expected a 503 while draining, got %d", rr.Code)
+	}
+
+	h.SetHealthy()
+	rr = httptest.NewRecorder()
+	_ = serve(r.Context(), rr, r)
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("expected requests to be served again, got %d", rr.Code)
+	}
+}
+
+func TestMountShttp(t *testing.T) {
+	h := New()
+	router := shttp.NewRouter()
+	h.MountShttp(router)
+
+	for path, contentType := range map[string]string{
+		"/health":      "",
+		"/health/json": "application/json",
+		"/healthz":     "",
+		"/livez":       "",
+		"/readyz":      "",
+		"/startupz":    "",
+	} {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", path, nil))
+		if rr.Code != http.StatusOK || rr.Header().Get("Content-Type") != contentType && contentType != "" {
+			t.Errorf("%s: got %d %q", path, rr.Code, rr.Header().Get("Content-Type"))
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package health
+
+import (
+	"slices"
+	"time"
+)
+
+// SilenceMatcher selects the transitions a silence applies to.
+type SilenceMatcher func(t Transition) bool
+
+// MatchChecks matches the transitions of the given checks, an empty name
+// standing for the overall status.
+func MatchChecks(names ...string) SilenceMatcher {
+	return func(t Transition) bool {
+		return slices.Contains(names, t.Check)
+	}
+}
+
+type silence struct {
+	from, until time.Time
+	match       SilenceMatcher
+}
+
+// Silence silences the default handler's notifications. See
+// (*Health).Silence.
+func Silence(until time.Time, match SilenceMatcher) (lift func()) {
+	return handler.Silence(until, match)
+}
+
+// SilenceBetween schedules a silence of the default handler's
+// notifications. See (*Health).SilenceBetween.
+func SilenceBetween(from, until time.Time, match SilenceMatcher) (lift func()) {
+	return handler.SilenceBetween(from, until, match)
+}
+
+// Silence suppresses notifications of the transitions selected by match (all
+// of them if nil) from now until the given time, such as for a deploy
+// window. Transitions are still recorded in the history. The returned
+// function lifts the silence early.
+func (h *Health) Silence(until time.Time, match SilenceMatcher) (lift func()) {
+	return h.SilenceBetween(time.Time{}, until, match)
+}
+
+// SilenceBetween is Silence for a window scheduled ahead of time, such as a
+// maintenance window: notifications of transitions that happen from the
+// from time until the until time are suppressed.
+func (h *Health) SilenceBetween(from, until time.Time, match SilenceMatcher) (lift func()) {
+	s := &silence{from: from, until: until, match: match}
+
+	h.mutex.Lock()
+	h.silences = append(h.silences, s)
+	h.mutex.Unlock()
+
+	return func() {
+		h.mutex.Lock()
+		defer h.mutex.Unlock()
+
+		h.silences = slices.DeleteFunc(h.silences, func(other *silence) bool { return other == s })
+	}
+}
+
+// silencedLocked reports whether a silence applies to t, dropping the
+// silences that are over. It must be called with the handler's mutex held.
+func (h *Health) silencedLocked(t Transition) bool {
+	h.silences = slices.DeleteFunc(h.silences, func(s *silence) bool {
+		return !t.At.Before(s.until)
+	})
+
+	for _, s := range h.silences {
+		if !t.At.Before(s.from) && (s.match == nil || s.match(t)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSilence(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := &stepClock{now: start}
+	h := New(WithClock(clock))
+
+	got := make(chan Transition, 10)
+	h.AddNotifier(NotifierFunc(func(ctx context.Context, tr Transition) error {
+		got <- tr
+		return nil
+	}))
+
+	h.SetHealthy()
+	lift := h.Silence(start.Add(time.Hour), MatchChecks(""))
+	h.SetUnhealthy("deploying")
+	h.SetHealthy()
+	if len(h.History().Transitions()) != 2 {
+		t.Errorf("silenced transitions should still be recorded")
+	}
+
+	// A silence that is lifted or over no longer applies
+	lift()
+	h.SetUnhealthy("broken")
+	h.Silence(start.Add(time.Minute), nil)
+	clock.advance(time.Minute)
+	h.SetHealthy()
+
+	for _, want := range []Status{Down, Up} {
+		select {
+		case tr := <-got:
+			if tr.To != want {
+				t.Errorf("expected a transition to %v, got %+v", want, tr)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no notification of the transition to %v", want)
+		}
+	}
+	select {
+	case tr := <-got:
+		t.Errorf("unexpected notification %+v", tr)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSilenceBetween(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	h := New()
+	h.SilenceBetween(start.Add(time.Hour), start.Add(2*time.Hour), MatchChecks("db"))
+
+	for _, c := range []struct {
+		t        Transition
+		silenced bool
+	}{
+		{Transition{Check: "db", At: start}, false},
+		{Transition{Check: "db", At: start.Add(90 * time.Minute)}, true},
+		{Transition{Check: "cache", At: start.Add(90 * time.Minute)}, false},
+		{Transition{Check: "db", At: start.Add(2 * time.Hour)}, false},
+	} {
+		if got := h.silencedLocked(c.t); got != c.silenced {
+			t.Errorf("%s at %v: silenced %v, want %v", c.t.Check, c.t.At, got, c.silenced)
+		}
+	}
+}
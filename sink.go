@@ -0,0 +1,250 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// maxPendingEvents is how many events wait for a slow sink before the
+// oldest ones are dropped.
+const maxPendingEvents = 1000
+
+// EventKind tells the kinds of events apart.
+type EventKind string
+
+const (
+	// EventResult is the result of a check, for every evaluation
+	EventResult EventKind = "result"
+	// EventTransition is a change of the overall status or of a check's
+	// status
+	EventTransition EventKind = "transition"
+)
+
+// Event is a raw event of the health package, for sinks.
+type Event struct {
+	Kind       EventKind    `json:"kind"`
+	At         time.Time    `json:"at"`
+	Result     *CheckResult `json:"result,omitempty"`
+	Transition *Transition  `json:"transition,omitempty"`
+}
+
+// Sink receives every event: each check result of every evaluation, whether
+// it changed or not, and each transition.
+type Sink interface {
+	Record(e Event) error
+}
+
+// AddSink adds a sink to the default handler. See (*Health).AddSink.
+func AddSink(s Sink) {
+	handler.AddSink(s)
+}
+
+// AddSink sends every event to s from now on. Like notifications, events are
+// delivered in the background and in order; a sink falling too far behind
+// loses the oldest events. Failures to record an event are ignored.
+func (h *Health) AddSink(s Sink) *Health {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.sinks = append(h.sinks, newQueue(maxPendingEvents, func(e Event) {
+		_ = s.Record(e)
+	}))
+	return h
+}
+
+// recordLocked queues e for every sink. It must be called with the
+// handler's mutex held.
+func (h *Health) recordLocked(e Event) {
+	for _, q := range h.sinks {
+		q.push(e)
+	}
+}
+
+// SlogSink logs events to logger: results at debug level, and transitions at
+// info level when recovering and warn level otherwise.
+func SlogSink(logger *slog.Logger) Sink {
+	return slogSink{logger}
+}
+
+type slogSink struct {
+	logger *slog.Logger
+}
+
+func (s slogSink) Record(e Event) error {
+	switch e.Kind {
+	case EventResult:
+		r := e.Result
+		s.logger.LogAttrs(context.Background(), slog.LevelDebug, "health check result",
+			slog.String("check", r.Name),
+			slog.String("status", string(r.Status)),
+			slog.String("reason", r.Reason),
+			slog.Duration("duration", r.Duration))
+	case EventTransition:
+		t := e.Transition
+		level := slog.LevelWarn
+		if t.To == Up {
+			level = slog.LevelInfo
+		}
+		s.logger.LogAttrs(context.Background(), level, "health status changed",
+			slog.String("check", t.Check),
+			slog.String("from", string(t.From)),
+			slog.String("to", string(t.To)),
+			slog.String("reason", t.Reason))
+	}
+	return nil
+}
+
+// MemorySink keeps the latest events in memory, for inspection from a debug
+// endpoint or a test.
+type MemorySink struct {
+	mutex  sync.Mutex
+	events []Event
+	next   int
+	full   bool
+}
+
+// NewMemorySink returns a sink keeping the latest size events.
+func NewMemorySink(size int) *MemorySink {
+	return &MemorySink{events: make([]Event, max(size, 1))}
+}
+
+// Record implements Sink.
+func (s *MemorySink) Record(e Event) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.events[s.next] = e
+	s.next = (s.next + 1) % len(s.events)
+	if s.next == 0 {
+		s.full = true
+	}
+	return nil
+}
+
+// Events returns a copy of the kept events, oldest first.
+func (s *MemorySink) Events() []Event {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.full {
+		return append([]Event(nil), s.events[:s.next]...)
+	}
+	return append(append([]Event(nil), s.events[s.next:]...), s.events[:s.next]...)
+}
+
+// FileSink appends events to a file as JSON lines, rotating it once it
+// reaches a size: the current file is renamed with a ".1" suffix, the
+// previous ".1" to ".2" and so on, keeping a bounded number of old files.
+type FileSink struct {
+	path    string
+	maxSize int64
+	keep    int
+
+	mutex  sync.Mutex
+	file   *os.File
+	size   int64
+	closed bool
+}
+
+// NewFileSink opens (or creates) the file at path for appending. It is
+// rotated once it reaches maxSize bytes (never if zero), keeping keep old
+// files.
+func NewFileSink(path string, maxSize int64, keep int) (*FileSink, error) {
+	s := &FileSink{path: path, maxSize: maxSize, keep: keep}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Record implements Sink.
+func (s *FileSink) Record(e Event) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return os.ErrClosed
+	}
+	// Reopen the file when a rotation failed half way
+	if s.file == nil {
+		if err := s.open(); err != nil {
+			return err
+		}
+	}
+	if s.maxSize > 0 && s.size > 0 && s.size+int64(len(line)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	return err
+}
+
+// Close closes the file. Later events fail to be recorded.
+func (s *FileSink) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed || s.file == nil {
+		s.closed = true
+		return nil
+	}
+	err := s.file.Close()
+	s.file, s.closed = nil, true
+	return err
+}
+
+func (s *FileSink) open() error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	s.file, s.size = f, info.Size()
+	return nil
+}
+
+// rotate shifts the old files and starts a new one. It must be called with
+// the mutex held.
+func (s *FileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	s.file = nil
+
+	if s.keep <= 0 {
+		if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return s.open()
+	}
+
+	for i := s.keep - 1; i >= 1; i-- {
+		err := os.Rename(fmt.Sprintf("%s.%d", s.path, i), fmt.Sprintf("%s.%d", s.path, i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(s.path, s.path+".1"); err != nil {
+		return err
+	}
+	return s.open()
+}
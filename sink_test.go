@@ -0,0 +1,126 @@
+package health
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// waitForEvents waits until the memory sink has received n events.
+func waitForEvents(t *testing.T, s *MemorySink, n int) []Event {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		events := s.Events()
+		if len(events) >= n || time.Now().After(deadline) {
+			return events
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSinkReceivesEveryResult(t *testing.T) {
+	h := New()
+	sink := NewMemorySink(10)
+	h.AddSink(sink)
+
+	fail := errors.New("connection refused")
+	h.Register("db", func(ctx context.Context) error { return fail })
+	h.Evaluate(context.Background())
+	h.Evaluate(context.Background())
+	fail = nil
+	h.Evaluate(context.Background())
+
+	// Three results, unchanged or not, then the check's and the overall
+	// transitions
+	events := waitForEvents(t, sink, 5)
+	var kinds []string
+	for _, e := range events {
+		kinds = append(kinds, string(e.Kind))
+	}
+	if strings.Join(kinds, ",") != "result,result,result,transition,transition" {
+		t.Fatalf("unexpected events %v", kinds)
+	}
+	if events[1].Result.Status != Down || events[3].Transition.Check != "db" || events[4].Transition.To != Up {
+		t.Errorf("unexpected events %+v", events)
+	}
+}
+
+func TestMemorySinkKeepsLatest(t *testing.T) {
+	sink := NewMemorySink(2)
+	for _, check := range []string{"a", "b", "c"} {
+		_ = sink.Record(Event{Kind: EventTransition, Transition: &Transition{Check: check}})
+	}
+
+	events := sink.Events()
+	if len(events) != 2 || events[0].Transition.Check != "b" || events[1].Transition.Check != "c" {
+		t.Errorf("expected the 2 latest events, got %+v", events)
+	}
+}
+
+func TestSlogSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := SlogSink(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	_ = sink.Record(Event{Kind: EventResult, Result: &CheckResult{Name: "db", Status: Up}})
+	_ = sink.Record(Event{Kind: EventTransition, Transition: &Transition{Check: "db", From: Up, To: Down, Reason: "gone"}})
+
+	out := buf.String()
+	if !strings.Contains(out, "level=DEBUG msg=\"health check result\" check=db status=UP") {
+		t.Errorf("result not logged: %s", out)
+	}
+	if !strings.Contains(out, "level=WARN msg=\"health status changed\" check=db from=UP to=DOWN reason=gone") {
+		t.Errorf("transition not logged: %s", out)
+	}
+}
+
+func TestFileSinkRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "health.jsonl")
+	sink, err := NewFileSink(path, 200, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	for i := 0; i < 10; i++ {
+		if err := sink.Record(Event{Kind: EventResult, Result: &CheckResult{Name: "db", Status: Up}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		f, err := os.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		scanner := bufio.NewScanner(f)
+		lines := 0
+		for scanner.Scan() {
+			var e Event
+			if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.Result.Name != "db" {
+				t.Errorf("%s: bad line %q", name, scanner.Text())
+			}
+			lines++
+		}
+		f.Close()
+		if lines == 0 {
+			t.Errorf("%s: no events", name)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected only 2 old files to be kept")
+	}
+
+	sink.Close()
+	if err := sink.Record(Event{}); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("expected recording after Close to fail, got %v", err)
+	}
+}
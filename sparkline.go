@@ -0,0 +1,147 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// sampleInterval is the period of the samples kept for every check
+	sampleInterval = time.Minute
+	// sampleWindow is how far back samples are kept
+	sampleWindow = time.Hour
+)
+
+// sparkBars are the bars of dashboard sparklines, from the fastest check to
+// the slowest one
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// Sample sums up a check's results over one sampleInterval.
+type Sample struct {
+	// At is the start of the interval
+	At time.Time `json:"at"`
+	// Status is the worst status of the interval
+	Status Status `json:"status"`
+	// Latency is the highest of the interval, as measured by the check
+	// itself (see Result) or else how long the check took
+	Latency time.Duration `json:"latency"`
+}
+
+// Samples returns the last hour of per-minute samples of the named check,
+// oldest first. See SeriesHandler.
+func (h *Health) Samples(name string) []Sample {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	return slices.Clone(h.samples[name])
+}
+
+// sampleLocked adds result to its check's samples, merging it with the
+// sample of its minute. It must be called with the mutex held.
+func (h *Health) sampleLocked(result CheckResult) {
+	if result.Status == NotEvaluated {
+		return
+	}
+
+	at := result.CheckedAt.Truncate(sampleInterval)
+	latency := latencyOf(result)
+
+	if h.samples == nil {
+		h.samples = make(map[string][]Sample)
+	}
+	samples := h.samples[result.Name]
+
+	if n := len(samples); n > 0 && samples[n-1].At.Equal(at) {
+		last := &samples[n-1]
+		if sampleRank(result.Status) > sampleRank(last.Status) {
+			last.Status = result.Status
+		}
+		last.Latency = max(last.Latency, latency)
+		return
+	}
+
+	samples = append(samples, Sample{At: at, Status: result.Status, Latency: latency})
+	cutoff := at.Add(-sampleWindow)
+	i := 0
+	for i < len(samples) && !samples[i].At.After(cutoff) {
+		i++
+	}
+	h.samples[result.Name] = slices.Clone(samples[i:])
+}
+
+// sampleRank orders statuses from the best to the worst.
+func sampleRank(status Status) int {
+	switch status {
+	case Up:
+		return 0
+	case Degraded:
+		return 1
+	case Overloaded:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// SeriesHandler serves the default handler's per-check samples. See
+// (*Health).SeriesHandler.
+func SeriesHandler() http.Handler {
+	return handler.SeriesHandler()
+}
+
+// SeriesHandler serves the last hour of per-minute samples (worst status
+// and highest latency) of a check as JSON, for quick trend checks during
+// triage. The check is the {name} wildcard of the route, or the path
+// segment before "/series":
+//
+//	mux.Handle("GET /health/checks/{name}/series", health.SeriesHandler())
+func (h *Health) SeriesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		if name == "" {
+			path := strings.TrimSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/series")
+			name = path[strings.LastIndex(path, "/")+1:]
+		}
+
+		h.mutex.RLock()
+		registered := slices.ContainsFunc(h.checks, func(c namedCheck) bool { return c.name == name })
+		h.mutex.RUnlock()
+		if !registered {
+			http.Error(w, "unknown check "+name, http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(struct {
+			Check    string        `json:"check"`
+			Interval time.Duration `json:"interval"`
+			Samples  []Sample      `json:"samples"`
+		}{name, sampleInterval, append([]Sample{}, h.Samples(name)...)})
+	})
+}
+
+// sparkline renders samples as bars as high as their latency, relative to
+// the slowest one.
+func sparkline(samples []Sample) []dashboardSample {
+	var slowest time.Duration
+	for _, s := range samples {
+		slowest = max(slowest, s.Latency)
+	}
+
+	bars := make([]dashboardSample, 0, len(samples))
+	for _, s := range samples {
+		level := 0
+		if slowest > 0 {
+			level = int(int64(len(sparkBars)-1) * int64(s.Latency) / int64(slowest))
+		}
+		bars = append(bars, dashboardSample{
+			Bar:   string(sparkBars[level]),
+			Class: s.Status,
+			Title: s.At.Format("15:04") + " " + string(s.Status) + " " + s.Latency.String(),
+		})
+	}
+	return bars
+}
@@ -0,0 +1,78 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSamples(t *testing.T) {
+	clock := &stepClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	h := New(WithClock(clock))
+
+	var dbErr error
+	h.RegisterResult("db", func(ctx context.Context) Result {
+		return Result{Err: dbErr, Latency: 10 * time.Millisecond}
+	})
+
+	// Two evaluations within the same minute make a single sample with the
+	// worst status
+	h.Evaluate(context.Background())
+	clock.advance(20 * time.Second)
+	dbErr = errors.New("connection refused")
+	h.Evaluate(context.Background())
+	clock.advance(time.Minute)
+	dbErr = nil
+	h.Evaluate(context.Background())
+
+	samples := h.Samples("db")
+	if len(samples) != 2 || samples[0].Status != Down || samples[1].Status != Up {
+		t.Fatalf("unexpected samples %+v", samples)
+	}
+	if samples[0].Latency != 10*time.Millisecond || !samples[1].At.Equal(samples[0].At.Add(time.Minute)) {
+		t.Errorf("unexpected samples %+v", samples)
+	}
+
+	// Only the last hour is kept
+	for range 90 {
+		clock.advance(time.Minute)
+		h.Evaluate(context.Background())
+	}
+	if n := len(h.Samples("db")); n != 60 {
+		t.Errorf("expected an hour of samples, got %d", n)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /health/checks/{name}/series", h.SeriesHandler())
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", "/health/checks/db/series", nil))
+	var body struct {
+		Check   string   `json:"check"`
+		Samples []Sample `json:"samples"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Check != "db" || len(body.Samples) != 60 {
+		t.Errorf("unexpected series %s", rr.Body)
+	}
+
+	// Without a route wildcard the name comes from the path
+	rr = httptest.NewRecorder()
+	h.SeriesHandler().ServeHTTP(rr, httptest.NewRequest("GET", "/health/checks/missing/series", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown check, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	h.DashboardHandler().ServeHTTP(rr, httptest.NewRequest("GET", "/health/dashboard", nil))
+	if !strings.Contains(rr.Body.String(), `class="trend"`) {
+		t.Error("expected the dashboard to draw the check's sparkline")
+	}
+}
@@ -0,0 +1,110 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// ReliabilityStats summarizes the failures and recoveries of the instance or
+// of a single check, computed from the transition history.
+type ReliabilityStats struct {
+	// Failures counts transitions from available to unavailable
+	Failures int `json:"failures"`
+	// Recoveries counts transitions from unavailable back to available
+	Recoveries int `json:"recoveries"`
+	// MTBF is the mean time between failures: the average time spent
+	// available before each failure
+	MTBF time.Duration `json:"mtbf"`
+	// MTTR is the mean time to recovery: the average time spent
+	// unavailable before each recovery
+	MTTR time.Duration `json:"mttr"`
+}
+
+type statsReport struct {
+	Instance ReliabilityStats            `json:"instance"`
+	Checks   map[string]ReliabilityStats `json:"checks,omitempty"`
+}
+
+// Stats computes reliability statistics for check (empty for the overall
+// status) from the recorded transitions.
+func (hist *History) Stats(check string) ReliabilityStats {
+	hist.mutex.RLock()
+	defer hist.mutex.RUnlock()
+
+	var stats ReliabilityStats
+
+	s, ok := hist.series[check]
+	if !ok {
+		return stats
+	}
+
+	var uptime, downtime time.Duration
+	status := s.initial
+	since := s.start
+	for _, t := range hist.transitions {
+		if t.Check != check {
+			continue
+		}
+
+		wasAvailable, isNowAvailable := isAvailable(status), isAvailable(t.To)
+		switch {
+		case wasAvailable && !isNowAvailable:
+			stats.Failures++
+			uptime += t.At.Sub(since)
+		case !wasAvailable && isNowAvailable:
+			stats.Recoveries++
+			downtime += t.At.Sub(since)
+		}
+
+		if wasAvailable != isNowAvailable {
+			since = t.At
+		}
+		status = t.To
+	}
+
+	if stats.Failures > 0 {
+		stats.MTBF = uptime / time.Duration(stats.Failures)
+	}
+	if stats.Recoveries > 0 {
+		stats.MTTR = downtime / time.Duration(stats.Recoveries)
+	}
+
+	return stats
+}
+
+// checks returns the names of the tracked checks.
+func (hist *History) checks() []string {
+	hist.mutex.RLock()
+	defer hist.mutex.RUnlock()
+
+	var names []string
+	for name := range hist.series {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// StatsHandler serves the reliability statistics of the default handler.
+func StatsHandler() http.Handler {
+	return handler.StatsHandler()
+}
+
+// StatsHandler serves the MTBF/MTTR statistics of the instance and of every
+// check as JSON, for post-incident reviews straight from the instance.
+func (h *Health) StatsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := statsReport{
+			Instance: h.history.Stats(""),
+			Checks:   make(map[string]ReliabilityStats),
+		}
+		for _, name := range h.history.checks() {
+			report.Checks[name] = h.history.Stats(name)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
@@ -0,0 +1,61 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReliabilityStats(t *testing.T) {
+	h, clock, set := newToggleHandler()
+
+	// Up 10m, down 2m, up 20m, down 4m, up again
+	set(true)
+	clock.advance(10 * time.Minute)
+	set(false)
+	clock.advance(2 * time.Minute)
+	set(true)
+	clock.advance(20 * time.Minute)
+	set(false)
+	clock.advance(4 * time.Minute)
+	set(true)
+
+	want := ReliabilityStats{
+		Failures:   2,
+		Recoveries: 2,
+		MTBF:       15 * time.Minute,
+		MTTR:       3 * time.Minute,
+	}
+
+	if got := h.History().Stats(""); got != want {
+		t.Errorf("instance stats: got %+v want %+v", got, want)
+	}
+	if got := h.History().Stats("db"); got != want {
+		t.Errorf("db stats: got %+v want %+v", got, want)
+	}
+	if got := h.History().Stats("missing"); got != (ReliabilityStats{}) {
+		t.Errorf("untracked stats: got %+v", got)
+	}
+}
+
+func TestStatsHandler(t *testing.T) {
+	h, clock, set := newToggleHandler()
+	set(false)
+	clock.advance(time.Minute)
+	set(true)
+
+	rr := httptest.NewRecorder()
+	h.StatsHandler().ServeHTTP(rr, httptest.NewRequest("GET", "/health/stats", nil))
+
+	var report statsReport
+	if err := json.Unmarshal(rr.Body.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Instance.Recoveries != 1 || report.Instance.MTTR != time.Minute {
+		t.Errorf("unexpected instance stats: %+v", report.Instance)
+	}
+	if db, ok := report.Checks["db"]; !ok || db.Recoveries != 1 {
+		t.Errorf("unexpected check stats: %+v", report.Checks)
+	}
+}
@@ -0,0 +1,49 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// WriteStatusFile keeps a file up to date with the default handler's
+// health. See (*Health).WriteStatusFile.
+func WriteStatusFile(ctx context.Context, path string, interval time.Duration) error {
+	return handler.WriteStatusFile(ctx, path, interval)
+}
+
+// WriteStatusFile writes the handler's current StatusSnapshot as JSON to the
+// file at path every interval until ctx is done, so node agents, cron jobs
+// or someone with a shell can read the health without any network
+// listener:
+//
+//	cat /run/app/health.json
+//
+// The file is replaced atomically, readers never see half a snapshot. It
+// returns the first error writing the file, or an error right away when the
+// interval isn't positive.
+func (h *Health) WriteStatusFile(ctx context.Context, path string, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("health: status file interval must be positive, got %s", interval)
+	}
+
+	ticker := h.getClock().NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		data, err := json.MarshalIndent(h.snapshot(), "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := writeFileAtomically(path, append(data, '\n')); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriteStatusFile(t *testing.T) {
+	h := New()
+	h.Register("db", func(ctx context.Context) error { return errors.New("connection refused") })
+	h.Evaluate(context.Background())
+
+	path := filepath.Join(t.TempDir(), "health.json")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := h.WriteStatusFile(ctx, path, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var snapshot StatusSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Status != Down || len(snapshot.Checks) != 1 || snapshot.TakenAt.IsZero() {
+		t.Errorf("unexpected snapshot %+v", snapshot)
+	}
+
+	if err := h.WriteStatusFile(ctx, filepath.Join(path, "missing", "health.json"), time.Hour); err == nil {
+		t.Error("expected an unwritable path to fail")
+	}
+	if err := h.WriteStatusFile(ctx, filepath.Join(path, "health.json"), 0); err == nil {
+		t.Error("expected an error for a zero interval")
+	}
+}
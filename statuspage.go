@@ -0,0 +1,234 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+)
+
+// StatusSnapshot is the health of a service at a point in time, as shown on
+// a status page.
+type StatusSnapshot struct {
+	Status       Status              `json:"status"`
+	Reason       string              `json:"reason,omitempty"`
+	Checks       []CheckResult       `json:"checks,omitempty"`
+	Groups       []GroupReport       `json:"groups,omitempty"`
+	RunID        string              `json:"run_id,omitempty"`
+	Availability *AvailabilityReport `json:"availability,omitempty"`
+	TakenAt      time.Time           `json:"taken_at"`
+}
+
+// snapshot returns the handler's current health.
+func (h *Health) snapshot() StatusSnapshot {
+	return h.snapshotOf(h.report(nil))
+}
+
+// Snapshot returns the default handler's health. See
+// (*Health).Snapshot.
+func Snapshot() StatusSnapshot {
+	return handler.Snapshot()
+}
+
+// Snapshot returns the whole health at once: the overall status and reason,
+// every check's result with its details and timestamps, the run ID and the
+// availability. The status and the results are read together, unlike with
+// separate getters. The snapshot is a deep copy, so it can be kept and
+// handed around without affecting the handler or later snapshots.
+func (h *Health) Snapshot() StatusSnapshot {
+	return h.snapshot().clone()
+}
+
+// clone deep-copies the snapshot.
+func (s StatusSnapshot) clone() StatusSnapshot {
+	s.Checks = cloneResults(s.Checks)
+	if s.Groups != nil {
+		groups := make([]GroupReport, len(s.Groups))
+		for i, group := range s.Groups {
+			group.Checks = cloneResults(group.Checks)
+			groups[i] = group
+		}
+		s.Groups = groups
+	}
+	if s.Availability != nil {
+		availability := *s.Availability
+		s.Availability = &availability
+	}
+	return s
+}
+
+// cloneResults copies results along with their details.
+func cloneResults(results []CheckResult) []CheckResult {
+	if results == nil {
+		return nil
+	}
+
+	clones := make([]CheckResult, len(results))
+	for i, result := range results {
+		if result.Details != nil {
+			details := make(map[string]any, len(result.Details))
+			for key, value := range result.Details {
+				details[key] = cloneDetail(value)
+			}
+			result.Details = details
+		}
+		clones[i] = result
+	}
+	return clones
+}
+
+// cloneDetail deep-copies the maps, slices and arrays a detail value is
+// made of. Other values, pointers included, are copied as they are.
+func cloneDetail(value any) any {
+	switch value := value.(type) {
+	case nil, string, bool, int, int64, float64, time.Duration, time.Time:
+		return value
+	}
+	return cloneValue(reflect.ValueOf(value)).Interface()
+}
+
+func cloneValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		clone := reflect.New(v.Type()).Elem()
+		clone.Set(cloneValue(v.Elem()))
+		return clone
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		clone := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			clone.SetMapIndex(iter.Key(), cloneValue(iter.Value()))
+		}
+		return clone
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		clone := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := range v.Len() {
+			clone.Index(i).Set(cloneValue(v.Index(i)))
+		}
+		return clone
+	case reflect.Array:
+		clone := reflect.New(v.Type()).Elem()
+		for i := range v.Len() {
+			clone.Index(i).Set(cloneValue(v.Index(i)))
+		}
+		return clone
+	}
+	return v
+}
+
+// snapshotOf turns a report into a snapshot taken now.
+func (h *Health) snapshotOf(report responseBody) StatusSnapshot {
+	return StatusSnapshot{
+		Status:       Status(report.Status),
+		Reason:       report.Reason,
+		Checks:       report.Checks,
+		Groups:       report.Groups,
+		RunID:        report.RunID,
+		Availability: report.Availability,
+		TakenAt:      h.getClock().Now(),
+	}
+}
+
+// WriteStatusPage renders snapshot as an HTML status page with the default
+// handler's translator. See (*Health).WriteStatusPage.
+func WriteStatusPage(w io.Writer, snapshot StatusSnapshot) error {
+	return handler.WriteStatusPage(w, snapshot)
+}
+
+// WriteStatusPage renders snapshot as a standalone HTML page, the dashboard
+// without debug links, for a public status page served statically.
+func (h *Health) WriteStatusPage(w io.Writer, snapshot StatusSnapshot) error {
+	return dashboardTemplate.Execute(w, h.dashboardData(snapshot))
+}
+
+// StatusPageDestination stores a rendered status page, such as a file or an
+// object in a bucket:
+//
+//	func(ctx context.Context, page []byte) error {
+//		_, err := s3Client.PutObject(ctx, &s3.PutObjectInput{
+//			Bucket:      aws.String("status"),
+//			Key:         aws.String("index.html"),
+//			Body:        bytes.NewReader(page),
+//			ContentType: aws.String("text/html; charset=utf-8"),
+//		})
+//		return err
+//	}
+type StatusPageDestination func(ctx context.Context, page []byte) error
+
+// StatusPageFile is the destination writing the page to the file at path,
+// atomically so a web server never serves half a page.
+func StatusPageFile(path string) StatusPageDestination {
+	return func(ctx context.Context, page []byte) error {
+		return writeFileAtomically(path, page)
+	}
+}
+
+// PublishStatusPage publishes the default handler's status page. See
+// (*Health).PublishStatusPage.
+func PublishStatusPage(ctx context.Context, interval time.Duration, dest StatusPageDestination) error {
+	return handler.PublishStatusPage(ctx, interval, dest)
+}
+
+// PublishStatusPage renders the status page to dest every interval until
+// ctx is done, so it can be served statically, even while the service
+// itself is down. It returns the first error storing a page, or an error
+// right away when the interval isn't positive.
+func (h *Health) PublishStatusPage(ctx context.Context, interval time.Duration, dest StatusPageDestination) error {
+	if interval <= 0 {
+		return fmt.Errorf("health: status page interval must be positive, got %s", interval)
+	}
+
+	ticker := h.getClock().NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		var page bytes.Buffer
+		if err := h.WriteStatusPage(&page, h.snapshot()); err != nil {
+			return err
+		}
+		if err := dest(ctx, page.Bytes()); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+		}
+	}
+}
+
+// writeFileAtomically replaces the file at path with data through a
+// temporary file in the same directory.
+func writeFileAtomically(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(0o644); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
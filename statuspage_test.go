@@ -0,0 +1,100 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteStatusPage(t *testing.T) {
+	h := &Health{status: Up}
+	snapshot := StatusSnapshot{
+		Status:  Down,
+		Reason:  "db: timeout",
+		Checks:  []CheckResult{{Name: "db", Status: Down, Reason: "timeout"}},
+		TakenAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	var page bytes.Buffer
+	if err := h.WriteStatusPage(&page, snapshot); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"<h1 class=\"DOWN\">DOWN</h1>", "db: timeout", "2024-01-01T12:00:00Z"} {
+		if !strings.Contains(page.String(), want) {
+			t.Errorf("expected the page to contain %q", want)
+		}
+	}
+}
+
+func TestPublishStatusPage(t *testing.T) {
+	h := &Health{status: Up}
+	h.Register("db", func(ctx context.Context) error { return nil })
+	h.Evaluate(context.Background())
+
+	path := filepath.Join(t.TempDir(), "index.html")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := h.PublishStatusPage(ctx, time.Hour, StatusPageFile(path)); err != nil {
+		t.Fatal(err)
+	}
+
+	page, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(page), "<td>db</td>") {
+		t.Errorf("expected the published page to list the check, got %s", page)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("expected no temporary file left, got %v", entries)
+	}
+}
+
+func TestPublishStatusPageInvalidInterval(t *testing.T) {
+	h := &Health{status: Up}
+	path := filepath.Join(t.TempDir(), "index.html")
+	if err := h.PublishStatusPage(context.Background(), 0, StatusPageFile(path)); err == nil {
+		t.Error("expected an error for a zero interval")
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	h := New()
+	h.Register("db", func(ctx context.Context) error {
+		SetDetail(ctx, "pool", "primary")
+		SetDetail(ctx, "replicas", []string{"a", "b"})
+		SetDetail(ctx, "shards", map[string]any{"eu": []any{1, 2}})
+		return nil
+	})
+	h.Register("queue", func(ctx context.Context) error { return nil }, WithGroup("messaging"))
+	h.Evaluate(context.Background())
+
+	snapshot := h.Snapshot()
+	if snapshot.Status != Up || len(snapshot.Checks) != 1 || len(snapshot.Groups) != 1 {
+		t.Fatalf("unexpected snapshot %+v", snapshot)
+	}
+
+	// Modifying the snapshot doesn't affect the handler
+	snapshot.Checks[0].Status = Down
+	snapshot.Checks[0].Details["pool"] = "replica"
+	snapshot.Groups[0].Checks[0].Reason = "tampered"
+	snapshot.Checks[0].Details["replicas"].([]string)[0] = "z"
+	snapshot.Checks[0].Details["shards"].(map[string]any)["eu"].([]any)[0] = 9
+	snapshot.Checks[0].Details["shards"].(map[string]any)["us"] = nil
+
+	again := h.Snapshot()
+	if again.Checks[0].Status != Up || again.Checks[0].Details["pool"] != "primary" || again.Groups[0].Checks[0].Reason != "" {
+		t.Errorf("snapshot shares state with the handler: %+v", again)
+	}
+	if replicas := again.Checks[0].Details["replicas"].([]string); replicas[0] != "a" {
+		t.Errorf("snapshot shares a detail slice with the handler: %v", replicas)
+	}
+	if shards := again.Checks[0].Details["shards"].(map[string]any); len(shards) != 1 || shards["eu"].([]any)[0] != 1 {
+		t.Errorf("snapshot shares a detail map with the handler: %v", shards)
+	}
+}
@@ -0,0 +1,217 @@
+package health
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SupervisorSocketEnv is the environment variable through which a supervisor
+// gives a child process the unix socket to report its health on.
+const SupervisorSocketEnv = "HEALTH_SUPERVISOR_SOCKET"
+
+// WorkerIDEnv is the environment variable naming a worker process in its
+// reports, its process ID by default.
+const WorkerIDEnv = "HEALTH_WORKER_ID"
+
+// childReport is a line sent by ReportToSupervisor.
+type childReport struct {
+	Worker string `json:"worker,omitempty"`
+	RemoteStatus
+}
+
+var (
+	// errNoReport is reported by a child monitor before the child's first
+	// report
+	errNoReport = errors.New("child hasn't reported yet")
+	// errChildExited is reported by a child monitor once the child's
+	// connection was closed
+	errChildExited = errors.New("child exited")
+)
+
+// ReportToSupervisor reports the default handler's health to a supervisor.
+// See (*Health).ReportToSupervisor.
+func ReportToSupervisor(ctx context.Context, interval time.Duration) error {
+	return handler.ReportToSupervisor(ctx, interval)
+}
+
+// ReportToSupervisor sends the handler's health to the supervisor that
+// spawned the process, over the unix socket named by SupervisorSocketEnv,
+// every interval until ctx is done. It does nothing when the variable isn't
+// set, so the same binary runs supervised or not. Every report is a line of
+// JSON in the format RemoteStatus parses, so supervisors in other languages
+// can consume it too, plus a "worker" field naming the process for
+// MonitorWorkers (WorkerIDEnv, or the process ID). It returns an error
+// when the interval isn't positive.
+func (h *Health) ReportToSupervisor(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("health: report interval must be positive, got %s", interval)
+	}
+
+	path := os.Getenv(SupervisorSocketEnv)
+	if path == "" {
+		return nil
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "unix", path)
+	if err != nil {
+		return fmt.Errorf("health: connecting to supervisor: %w", err)
+	}
+	defer conn.Close()
+
+	ticker := h.getClock().NewTicker(interval)
+	defer ticker.Stop()
+
+	worker := os.Getenv(WorkerIDEnv)
+	if worker == "" {
+		worker = strconv.Itoa(os.Getpid())
+	}
+
+	encoder := json.NewEncoder(conn)
+	for {
+		status, reason, results := h.overall()
+		report := childReport{Worker: worker, RemoteStatus: RemoteStatus{Status: status, Reason: reason, Checks: results}}
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("health: reporting to supervisor: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+		}
+	}
+}
+
+// ChildMonitor receives the health a child process reports with
+// ReportToSupervisor:
+//
+//	monitor, err := health.MonitorChild(filepath.Join(dir, "worker.sock"))
+//	cmd := exec.Command("./worker")
+//	cmd.Env = append(os.Environ(), monitor.Env())
+//	health.Register("worker", monitor.Check)
+type ChildMonitor struct {
+	h        *Health
+	path     string
+	listener net.Listener
+
+	mutex     sync.Mutex
+	status    RemoteStatus
+	reported  time.Time
+	connected bool
+	maxAge    time.Duration
+}
+
+// MonitorChild listens for a child's reports on the default handler's clock.
+// See (*Health).MonitorChild.
+func MonitorChild(path string) (*ChildMonitor, error) {
+	return handler.MonitorChild(path)
+}
+
+// MonitorChild listens on the unix socket at path for a child's reports.
+// Reports are timed, and their age judged, on the handler's clock.
+func (h *Health) MonitorChild(path string) (*ChildMonitor, error) {
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &ChildMonitor{h: h, path: path, listener: listener}
+	go m.accept()
+	return m, nil
+}
+
+// Env returns the environment entry pointing a child to the monitor.
+func (m *ChildMonitor) Env() string {
+	return SupervisorSocketEnv + "=" + m.path
+}
+
+// Status returns the last status reported by the child and when it was
+// received, zero before the first report.
+func (m *ChildMonitor) Status() (RemoteStatus, time.Time) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.status, m.reported
+}
+
+// WithMaxAge makes Check fail when the child's last report is older than
+// d, for children that hang without closing their connection. Zero, the
+// default, accepts reports of any age.
+func (m *ChildMonitor) WithMaxAge(d time.Duration) *ChildMonitor {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.maxAge = d
+	return m
+}
+
+// Check is a check reflecting the child's reported status: it fails while
+// the child reports anything but UP or DEGRADED, before its first report,
+// once its connection is closed (when it crashed or exited) and when its
+// last report is older than the maximum age, and is degraded while the
+// child is.
+func (m *ChildMonitor) Check(ctx context.Context) error {
+	m.mutex.Lock()
+	status, reported, connected, maxAge := m.status, m.reported, m.connected, m.maxAge
+	m.mutex.Unlock()
+	age := m.h.getClock().Now().Sub(reported)
+
+	switch {
+	case reported.IsZero():
+		return errNoReport
+	case !connected:
+		return errChildExited
+	case maxAge > 0 && age > maxAge:
+		return fmt.Errorf("child hasn't reported for %s", age.Round(time.Second))
+	case status.Status == Degraded:
+		return DegradedError(fmt.Errorf("child is %s: %s", status.Status, status.Reason))
+	case status.Status != Up:
+		return fmt.Errorf("child is %s: %s", status.Status, status.Reason)
+	}
+	return nil
+}
+
+// Close stops listening for reports.
+func (m *ChildMonitor) Close() error {
+	return m.listener.Close()
+}
+
+func (m *ChildMonitor) accept() {
+	for {
+		conn, err := m.listener.Accept()
+		if err != nil {
+			return
+		}
+		go m.receive(conn)
+	}
+}
+
+func (m *ChildMonitor) receive(conn net.Conn) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(nil, DefaultMaxBodySize)
+	for scanner.Scan() {
+		status, err := parseJSONStatus(scanner.Bytes())
+		if err != nil {
+			continue
+		}
+
+		m.mutex.Lock()
+		m.status, m.reported, m.connected = status, m.h.getClock().Now(), true
+		m.mutex.Unlock()
+	}
+
+	m.mutex.Lock()
+	m.connected = false
+	m.mutex.Unlock()
+}
@@ -0,0 +1,103 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSupervisorReports(t *testing.T) {
+	monitor, err := MonitorChild(filepath.Join(t.TempDir(), "child.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer monitor.Close()
+
+	if err := monitor.Check(context.Background()); err == nil {
+		t.Error("expected the check to fail before the first report")
+	}
+
+	child := &Health{status: Up}
+	child.Register("queue", func(ctx context.Context) error { return errors.New("broker unreachable") })
+	child.Evaluate(context.Background())
+
+	t.Setenv(SupervisorSocketEnv, monitor.path)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- child.ReportToSupervisor(ctx, 10*time.Millisecond) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		status, reported := monitor.Status()
+		if !reported.IsZero() {
+			if status.Status != Down || len(status.Checks) != 1 || status.Checks[0].Name != "queue" {
+				t.Errorf("unexpected report %+v", status)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("no report received")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := monitor.Check(context.Background()); err == nil {
+		t.Error("expected the check to reflect the child being DOWN")
+	}
+
+	// The child going away closes its connection
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for !errors.Is(monitor.Check(context.Background()), errChildExited) {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the check to fail once the child exited, got %v", monitor.Check(context.Background()))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSupervisorStaleReport(t *testing.T) {
+	clock := &stepClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	h := New(WithClock(clock))
+
+	monitor, err := h.MonitorChild(filepath.Join(t.TempDir(), "child.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer monitor.Close()
+	monitor.WithMaxAge(time.Minute)
+
+	monitor.mutex.Lock()
+	monitor.status, monitor.connected = RemoteStatus{Status: Up}, true
+	monitor.reported = clock.Now()
+	monitor.mutex.Unlock()
+	if err := monitor.Check(context.Background()); err != nil {
+		t.Errorf("unexpected error for a fresh report: %v", err)
+	}
+
+	clock.advance(2 * time.Minute)
+	if err := monitor.Check(context.Background()); err == nil || !strings.Contains(err.Error(), "2m0s") {
+		t.Errorf("expected a stale report to fail the check, got %v", err)
+	}
+}
+
+func TestReportToSupervisorUnsupervised(t *testing.T) {
+	t.Setenv(SupervisorSocketEnv, "")
+	h := &Health{status: Up}
+	if err := h.ReportToSupervisor(context.Background(), time.Second); err != nil {
+		t.Errorf("expected nothing to happen without a supervisor, got %v", err)
+	}
+}
+
+func TestReportToSupervisorInvalidInterval(t *testing.T) {
+	t.Setenv(SupervisorSocketEnv, filepath.Join(t.TempDir(), "child.sock"))
+	h := &Health{status: Up}
+	if err := h.ReportToSupervisor(context.Background(), 0); err == nil {
+		t.Error("expected an error for a zero interval")
+	}
+}
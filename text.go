@@ -0,0 +1,65 @@
+package health
+
+import (
+	"bytes"
+	"text/template"
+	"time"
+)
+
+// processStart is when the process started, for the uptime on the real
+// clock
+var processStart = time.Now()
+
+// TextData is what a plain text template is executed with.
+type TextData struct {
+	Status Status
+	Reason string
+	Checks []CheckResult
+	Uptime time.Duration
+}
+
+// WithTextTemplate renders the plain text body with tmpl instead of the
+// "STATUS: reason" format, for legacy probes expecting exact strings:
+//
+//	template.Must(template.New("").Parse(`{{if eq .Status "UP"}}OK{{else}}FAIL{{end}}`))
+//
+// The HTTP status code still follows the status. When the template fails
+// the default format is used. A nil template restores it.
+func (h *Health) WithTextTemplate(tmpl *template.Template) *Health {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.textTemplate = tmpl
+	return h
+}
+
+// templatedText renders the plain text body with tmpl, reporting false when
+// the template fails.
+func (h *Health) templatedText(tmpl *template.Template) (Status, []byte, bool) {
+	status, reason, results := h.overall()
+
+	var body bytes.Buffer
+	err := tmpl.Execute(&body, TextData{
+		Status: status,
+		Reason: reason,
+		Checks: results,
+		Uptime: h.uptime(),
+	})
+	if err != nil {
+		return status, nil, false
+	}
+	return status, body.Bytes(), true
+}
+
+// uptime is how long the process has run, or how long since the clock was
+// set, on the handler's clock.
+func (h *Health) uptime() time.Duration {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	start := h.clockStart
+	if h.clock == nil {
+		start = processStart
+	}
+	return h.clockLocked().Now().Sub(start)
+}
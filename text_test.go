@@ -0,0 +1,61 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"text/template"
+	"time"
+)
+
+func TestTextTemplate(t *testing.T) {
+	h := &Health{status: Up}
+	h.WithTextTemplate(template.Must(template.New("").Parse(`{{if eq .Status "UP"}}pong{{else}}FAIL {{.Reason}}{{end}}`)))
+
+	get := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+		return rec
+	}
+
+	if rec := get(); rec.Code != http.StatusOK || rec.Body.String() != "pong" {
+		t.Errorf("unexpected response: %d %q", rec.Code, rec.Body.String())
+	}
+
+	h.Register("db", func(ctx context.Context) error { return errors.New("refused") })
+	h.Evaluate(context.Background())
+	if rec := get(); rec.Code != http.StatusServiceUnavailable || rec.Body.String() != "FAIL db: refused" {
+		t.Errorf("unexpected response: %d %q", rec.Code, rec.Body.String())
+	}
+
+	// Every field is available
+	h.WithTextTemplate(template.Must(template.New("").Parse(`{{range .Checks}}{{.Name}}={{.Status}}{{end}} {{if gt .Uptime 0}}up{{end}}`)))
+	if rec := get(); rec.Body.String() != "db=DOWN up" {
+		t.Errorf("unexpected body: %q", rec.Body.String())
+	}
+
+	// A failing template falls back to the default format
+	h.WithTextTemplate(template.Must(template.New("").Parse(`{{.Missing}}`)))
+	if rec := get(); rec.Body.String() != "DOWN: db: refused" {
+		t.Errorf("unexpected fallback body: %q", rec.Body.String())
+	}
+
+	h.WithTextTemplate(nil)
+	if rec := get(); rec.Body.String() != "DOWN: db: refused" {
+		t.Errorf("unexpected default body: %q", rec.Body.String())
+	}
+}
+
+func TestTextTemplateUptime(t *testing.T) {
+	clock := &stepClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	h := New(WithClock(clock)).WithTextTemplate(template.Must(template.New("").Parse(`{{.Uptime}}`)))
+
+	clock.advance(5 * time.Minute)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+	if rec.Body.String() != "5m0s" {
+		t.Errorf("uptime should follow the handler's clock, got %q", rec.Body.String())
+	}
+}
@@ -0,0 +1,59 @@
+package health
+
+// Translator localizes human-readable output for operators who don't read
+// English. It applies to the plain text format and the HTML dashboard;
+// machine-readable formats such as JSON always stay in English.
+type Translator interface {
+	// Status returns the word displayed for a status
+	Status(status Status) string
+	// Reason returns the text displayed for a reason. Reasons produced by
+	// the package itself (for example "evaluation timed out before the
+	// check finished") are fixed strings and can be used as lookup keys.
+	Reason(reason string) string
+}
+
+// MapTranslator is a Translator backed by lookup tables. Anything missing
+// from the tables is displayed untranslated.
+type MapTranslator struct {
+	Statuses map[Status]string
+	Reasons  map[string]string
+}
+
+// Status implements Translator
+func (t MapTranslator) Status(status Status) string {
+	if word, ok := t.Statuses[status]; ok {
+		return word
+	}
+	return string(status)
+}
+
+// Reason implements Translator
+func (t MapTranslator) Reason(reason string) string {
+	if text, ok := t.Reasons[reason]; ok {
+		return text
+	}
+	return reason
+}
+
+// WithTranslator sets the translator used for the plain text format and the
+// dashboard. A nil translator restores the English output.
+func (h *Health) WithTranslator(translator Translator) *Health {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.translator = translator
+	return h
+}
+
+// translatedText renders the plain text body through the translator. It must
+// be called with the mutex held.
+func (h *Health) translatedText() (Status, []byte) {
+	status, reason, failed := h.aggregate()
+	if failed != nil {
+		reason = failed.Name + ": " + h.translator.Reason(failed.Reason)
+	} else if reason != "" {
+		reason = h.translator.Reason(reason)
+	}
+
+	return status, []byte(h.translator.Status(status) + ": " + truncate(reason, h.reasonLimit()))
+}
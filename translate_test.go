@@ -0,0 +1,66 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+var german = MapTranslator{
+	Statuses: map[Status]string{
+		Up:   "BEREIT",
+		Down: "GESTÖRT",
+	},
+	Reasons: map[string]string{
+		"maintenance": "Wartungsarbeiten",
+		"refused":     "Verbindung abgelehnt",
+	},
+}
+
+func TestTranslatedPlainText(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(h *Health)
+		want  string
+	}{
+		{"up", func(h *Health) {}, "BEREIT: "},
+		{"manual reason", func(h *Health) { h.status, h.reason = Down, "maintenance" }, "GESTÖRT: Wartungsarbeiten"},
+		{"untranslated reason", func(h *Health) { h.status, h.reason = Down, "disk full" }, "GESTÖRT: disk full"},
+		{"failing check", func(h *Health) {
+			h.Register("db", func(ctx context.Context) error { return errors.New("refused") })
+			h.Evaluate(context.Background())
+		}, "GESTÖRT: db: Verbindung abgelehnt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Health{status: Up}
+			h.WithTranslator(german)
+			tt.setup(h)
+
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, httptest.NewRequest("GET", "/health", nil))
+			if body := rr.Body.String(); body != tt.want {
+				t.Errorf("got %q want %q", body, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONStaysEnglish(t *testing.T) {
+	h := &Health{status: Down, reason: "maintenance", useJSON: true}
+	h.WithTranslator(german)
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest("GET", "/health", nil))
+
+	var response responseBody
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Status != "DOWN" || response.Reason != "maintenance" {
+		t.Errorf("JSON output was translated: %+v", response)
+	}
+}
@@ -0,0 +1,91 @@
+package health
+
+import (
+	"encoding/json"
+	"maps"
+	"unicode/utf8"
+)
+
+// DefaultMaxReasonLength is the default cap, in bytes, on reasons rendered in
+// responses. Some drivers produce multi-kilobyte error strings, which load
+// balancer health checkers choke on or dump into their logs.
+const DefaultMaxReasonLength = 1024
+
+// truncatedIndicator is appended to reasons that were cut short
+const truncatedIndicator = "... [truncated]"
+
+// WithMaxReasonLength caps the length in bytes of every reason (the overall
+// one and each check's) rendered in responses, and of every check detail
+// value. Longer reasons are cut and marked with "... [truncated]", as are
+// detail values, replaced by their JSON encoding when they aren't strings.
+// Zero restores DefaultMaxReasonLength and a negative value disables the
+// limit.
+func (h *Health) WithMaxReasonLength(n int) *Health {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.maxReasonLength = n
+	return h
+}
+
+// reasonLimit returns the effective reason length limit, or a negative value
+// when there's none. It must be called with the mutex held.
+func (h *Health) reasonLimit() int {
+	if h.maxReasonLength == 0 {
+		return DefaultMaxReasonLength
+	}
+	return h.maxReasonLength
+}
+
+// truncate cuts s to at most limit bytes (indicator included) without
+// splitting a UTF-8 sequence. A negative limit leaves s untouched.
+func truncate(s string, limit int) string {
+	if limit < 0 || len(s) <= limit {
+		return s
+	}
+
+	cut := limit - len(truncatedIndicator)
+	if cut <= 0 {
+		return truncatedIndicator[:min(limit, len(truncatedIndicator))]
+	}
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+
+	return s[:cut] + truncatedIndicator
+}
+
+// truncateDetails returns details with every value longer than limit bytes,
+// or longer once encoded to JSON, truncated as a string. The map is only
+// copied when a value is cut, so it can be shared.
+func truncateDetails(details map[string]any, limit int) map[string]any {
+	if limit < 0 {
+		return details
+	}
+
+	truncated, copied := details, false
+	for key, value := range details {
+		var s string
+		switch v := value.(type) {
+		case nil, bool, int, int64, uint64, float64:
+			continue
+		case string:
+			s = v
+		default:
+			data, err := json.Marshal(v)
+			if err != nil {
+				continue
+			}
+			s = string(data)
+		}
+		if len(s) <= limit {
+			continue
+		}
+
+		if !copied {
+			truncated, copied = maps.Clone(details), true
+		}
+		truncated[key] = truncate(s, limit)
+	}
+	return truncated
+}
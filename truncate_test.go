@@ -0,0 +1,106 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		limit int
+		want  string
+	}{
+		{"short", "refused", 100, "refused"},
+		{"exact", "refused", 7, "refused"},
+		{"unlimited", strings.Repeat("x", 5000), -1, strings.Repeat("x", 5000)},
+		{"long", strings.Repeat("x", 100), 20, "xxxxx... [truncated]"},
+		{"multibyte", strings.Repeat("é", 20), 20, "éé... [truncated]"},
+		{"tiny limit", "connection refused", 3, "..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncate(tt.input, tt.limit)
+			if got != tt.want {
+				t.Errorf("got %q want %q", got, tt.want)
+			}
+			if tt.limit >= 0 && len(got) > tt.limit {
+				t.Errorf("result is %d bytes, over the %d byte limit", len(got), tt.limit)
+			}
+		})
+	}
+}
+
+func TestReasonLimitInResponses(t *testing.T) {
+	long := strings.Repeat("x", 5000)
+
+	h := &Health{status: Up}
+	h.Register("db", func(ctx context.Context) error { return errors.New(long) })
+	h.Evaluate(context.Background())
+
+	// The default limit applies to plain text responses
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest("GET", "/health", nil))
+	if body := rr.Body.String(); len(body) != len("DOWN: ")+DefaultMaxReasonLength || !strings.HasSuffix(body, truncatedIndicator) {
+		t.Errorf("plain text body not truncated: %d bytes", len(body))
+	}
+
+	// And to every reason in JSON responses
+	h.WithJSON(true).WithMaxReasonLength(100)
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest("GET", "/health", nil))
+
+	var response responseBody
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Reason) != 100 || len(response.Checks[0].Reason) != 100 {
+		t.Errorf("reasons not truncated: %d and %d bytes", len(response.Reason), len(response.Checks[0].Reason))
+	}
+
+	// A negative limit disables truncation
+	h.WithMaxReasonLength(-1)
+	if results := h.Results(); results[0].Reason != long {
+		t.Errorf("reason truncated despite the limit being disabled: %d bytes", len(results[0].Reason))
+	}
+}
+
+func TestDetailLimitInResponses(t *testing.T) {
+	h := &Health{status: Up}
+	h.WithJSON(true).WithMaxReasonLength(100)
+	h.Register("db", func(ctx context.Context) error {
+		SetDetail(ctx, "query", strings.Repeat("x", 5000))
+		SetDetail(ctx, "replicas", make([]string, 1000))
+		SetDetail(ctx, "connections", 12)
+		return nil
+	})
+	h.Evaluate(context.Background())
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest("GET", "/health", nil))
+	var response responseBody
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	details := response.Checks[0].Details
+	for _, key := range []string{"query", "replicas"} {
+		if s, ok := details[key].(string); !ok || len(s) != 100 || !strings.HasSuffix(s, truncatedIndicator) {
+			t.Errorf("%s not truncated: %v", key, details[key])
+		}
+	}
+	if details["connections"] != float64(12) {
+		t.Errorf("short detail changed: %v", details["connections"])
+	}
+
+	// The recorded details are left alone
+	if query := h.results[0].Details["query"].(string); len(query) != 5000 {
+		t.Errorf("stored detail truncated: %d bytes", len(query))
+	}
+}
@@ -0,0 +1,152 @@
+package health
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+)
+
+// WorkersGroup is the group of the checks MonitorWorkers registers.
+const WorkersGroup = "workers"
+
+// errWorkerExited is reported for a worker whose connection was closed
+var errWorkerExited = errors.New("worker exited")
+
+// WorkerMonitor aggregates the health reported by worker processes sharing
+// one unix socket, as forked or pre-spawned workers do. See MonitorWorkers.
+type WorkerMonitor struct {
+	h        *Health
+	path     string
+	listener net.Listener
+
+	mutex   sync.Mutex
+	workers map[string]*workerState
+}
+
+// workerState is what is known about one worker.
+type workerState struct {
+	status    RemoteStatus
+	connected bool
+}
+
+// MonitorWorkers aggregates workers into the default handler. See
+// (*Health).MonitorWorkers.
+func MonitorWorkers(path string) (*WorkerMonitor, error) {
+	return handler.MonitorWorkers(path)
+}
+
+// MonitorWorkers listens on the unix socket at path for the reports of
+// worker processes, sent with ReportToSupervisor, so the host-level probe
+// reflects every worker. Each worker gets a check named "worker-<id>" in
+// the WorkersGroup group when it first reports, giving a per-worker
+// breakdown in the JSON report. A worker's check fails while it reports
+// anything but UP or DEGRADED (it is degraded then), and once it
+// disconnects, until Forget is called for it.
+func (h *Health) MonitorWorkers(path string) (*WorkerMonitor, error) {
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &WorkerMonitor{h: h, path: path, listener: listener, workers: make(map[string]*workerState)}
+	go m.accept()
+	return m, nil
+}
+
+// Env returns the environment entry pointing workers to the monitor.
+func (m *WorkerMonitor) Env() string {
+	return SupervisorSocketEnv + "=" + m.path
+}
+
+// Forget drops a worker that was stopped on purpose, and its check.
+func (m *WorkerMonitor) Forget(worker string) {
+	m.mutex.Lock()
+	delete(m.workers, worker)
+	m.mutex.Unlock()
+
+	m.h.Unregister(workerCheckName(worker))
+}
+
+// Close stops listening for reports.
+func (m *WorkerMonitor) Close() error {
+	return m.listener.Close()
+}
+
+func workerCheckName(worker string) string {
+	return "worker-" + worker
+}
+
+func (m *WorkerMonitor) accept() {
+	for {
+		conn, err := m.listener.Accept()
+		if err != nil {
+			return
+		}
+		go m.receive(conn)
+	}
+}
+
+func (m *WorkerMonitor) receive(conn net.Conn) {
+	defer conn.Close()
+
+	var worker string
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(nil, DefaultMaxBodySize)
+	for scanner.Scan() {
+		var report childReport
+		if err := json.Unmarshal(scanner.Bytes(), &report); err != nil || report.Worker == "" {
+			continue
+		}
+		report.Status = normalizeStatus(string(report.Status))
+		worker = report.Worker
+
+		m.mutex.Lock()
+		state, known := m.workers[worker]
+		if !known {
+			state = &workerState{}
+			m.workers[worker] = state
+		}
+		state.status, state.connected = report.RemoteStatus, true
+		m.mutex.Unlock()
+
+		if !known {
+			m.h.Register(workerCheckName(worker), m.check(worker), WithGroup(WorkersGroup))
+		}
+	}
+
+	if worker != "" {
+		m.mutex.Lock()
+		if state, ok := m.workers[worker]; ok {
+			state.connected = false
+		}
+		m.mutex.Unlock()
+	}
+}
+
+// check returns the check of worker.
+func (m *WorkerMonitor) check(worker string) CheckFunc {
+	return func(ctx context.Context) error {
+		m.mutex.Lock()
+		state, ok := m.workers[worker]
+		var status RemoteStatus
+		var connected bool
+		if ok {
+			status, connected = state.status, state.connected
+		}
+		m.mutex.Unlock()
+
+		switch {
+		case !ok || !connected:
+			return errWorkerExited
+		case status.Status == Degraded:
+			return DegradedError(fmt.Errorf("worker is %s: %s", status.Status, status.Reason))
+		case status.Status != Up:
+			return fmt.Errorf("worker is %s: %s", status.Status, status.Reason)
+		}
+		return nil
+	}
+}
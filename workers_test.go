@@ -0,0 +1,109 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMonitorWorkers(t *testing.T) {
+	parent := &Health{status: Up}
+	monitor, err := parent.MonitorWorkers(filepath.Join(t.TempDir(), "workers.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer monitor.Close()
+
+	// Two workers share the socket, one of them failing
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, worker := range []struct {
+		id  string
+		err error
+	}{{"1", nil}, {"2", errors.New("queue full")}} {
+		child := &Health{status: Up}
+		child.Register("queue", func(ctx context.Context) error { return worker.err })
+		child.Evaluate(context.Background())
+
+		t.Setenv(SupervisorSocketEnv, monitor.path)
+		t.Setenv(WorkerIDEnv, worker.id)
+		ready := make(chan struct{})
+		go func() {
+			close(ready)
+			_ = child.ReportToSupervisor(ctx, 10*time.Millisecond)
+		}()
+		<-ready
+		waitForCheck(t, parent, workerCheckName(worker.id))
+	}
+
+	parent.Evaluate(context.Background())
+	results := parent.Results()
+	if len(results) != 2 || results[0].Group != WorkersGroup {
+		t.Fatalf("expected a check per worker, got %+v", results)
+	}
+	if results[0].Status != Up || results[1].Status != Down {
+		t.Errorf("expected worker 1 UP and worker 2 DOWN, got %s and %s", results[0].Status, results[1].Status)
+	}
+	if status, _, _ := parent.overall(); status != Down {
+		t.Errorf("expected the failing worker to take the host DOWN, got %s", status)
+	}
+
+	monitor.Forget("2")
+	parent.Evaluate(context.Background())
+	if status, _, _ := parent.overall(); status != Up {
+		t.Errorf("expected a forgotten worker to no longer count, got %s", status)
+	}
+}
+
+func TestMonitorWorkersExit(t *testing.T) {
+	parent := &Health{status: Up}
+	monitor, err := parent.MonitorWorkers(filepath.Join(t.TempDir(), "workers.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer monitor.Close()
+
+	conn, err := net.Dial("unix", monitor.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = conn.Write([]byte(`{"worker":"7","status":"UP"}` + "\n"))
+	waitForCheck(t, parent, "worker-7")
+	conn.Close()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		parent.Evaluate(context.Background())
+		if parent.Results()[0].Status == Down {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected an exited worker to be DOWN")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// waitForCheck waits until a check named name is registered on h.
+func waitForCheck(t *testing.T, h *Health, name string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		h.mutex.RLock()
+		for _, c := range h.checks {
+			if c.name == name {
+				h.mutex.RUnlock()
+				return
+			}
+		}
+		h.mutex.RUnlock()
+
+		if time.Now().After(deadline) {
+			t.Fatalf("check %s never registered", name)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}